@@ -1,14 +1,19 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"kasir-api/internal/config"
 	"kasir-api/internal/database"
+	domain "kasir-api/internal/domains"
 	handler "kasir-api/internal/handlers"
+	"kasir-api/internal/middleware"
 	repository "kasir-api/internal/repositories"
 	service "kasir-api/internal/services"
 	"kasir-api/internal/utils"
@@ -22,6 +27,9 @@ import (
 // @version 1.0
 // @host kasir-api-production-1c80.up.railway.app
 // @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 func main() {
 	// load config
 	cfg := config.GetConfig()
@@ -33,16 +41,127 @@ func main() {
 	}
 	defer closeDB()
 
+	// optionally force clients to send If-Match on writes
+	conditional := func(next http.HandlerFunc) http.HandlerFunc {
+		if cfg.App.RequireIfMatch {
+			return middleware.RequireIfMatch(next)
+		}
+		return next
+	}
+
+	// =================== Auth ===================================
+	userRepository := repository.NewUserRepository(db)
+	sessionRepository := repository.NewSessionRepository(db)
+	terminalRepository := repository.NewTerminalRepository(db)
+	apiKeyRepository := repository.NewAPIKeyRepository(db)
+	approvalRepository := repository.NewApprovalRepository(db)
+	authService := service.NewAuthService(
+		userRepository,
+		sessionRepository,
+		terminalRepository,
+		apiKeyRepository,
+		approvalRepository,
+		&cfg.Auth,
+	)
+	userService := service.NewUserService(userRepository, sessionRepository)
+	authHandler := handler.NewAuthHandler(authService, userService)
+	userHandler := handler.NewUserHandler(userService)
+
+	if err := authService.EnsureAdmin(context.Background(), cfg.Auth.AdminUsername, cfg.Auth.AdminPassword); err != nil {
+		panic("failed to create admin user")
+	}
+
+	// require a logged in user, optionally one whose roles grant a permission
+	authenticated := middleware.Authenticate(authService)
+	// only routes that require a permission accept API keys
+	authorize := middleware.Authorize(authService)
+	// endpoints about the caller's own session are for people, not API keys
+	userOnly := func(next http.HandlerFunc) http.HandlerFunc {
+		return authenticated(middleware.RequireUser(next))
+	}
+	// accept a supervisor's approval for voids, large discounts and price overrides
+	approvable := middleware.Approval(authService)
+
+	http.HandleFunc("POST /api/auth/login", authHandler.Login)
+	http.HandleFunc("POST /api/auth/pin-login", authHandler.PINLogin)
+	http.HandleFunc("POST /api/auth/refresh", authHandler.Refresh)
+	http.HandleFunc("PUT /api/auth/pin", userOnly(authHandler.SetPIN))
+	http.HandleFunc("POST /api/auth/logout", userOnly(authHandler.Logout))
+	http.HandleFunc("GET /api/auth/me", userOnly(authHandler.Me))
+	http.HandleFunc("POST /api/auth/approval-grants", userOnly(authHandler.IssueApprovalGrant))
+	http.Handle("GET /api/users", authorize(domain.PermissionUserManage, userHandler.GetUsers))
+	http.Handle("POST /api/users", authorize(domain.PermissionUserManage, userHandler.CreateUser))
+	http.Handle("POST /api/users/{id}/revoke-sessions", authorize(domain.PermissionUserManage, userHandler.RevokeSessions))
+	http.Handle("PUT /api/users/{id}/pin", authorize(domain.PermissionUserManage, userHandler.SetUserPIN))
+	// =================================================================
+
+	// =================== Terminal ===================================
+	terminalService := service.NewTerminalService(terminalRepository)
+	terminalHandler := handler.NewTerminalHandler(terminalService)
+
+	http.Handle("GET /api/terminals", authorize(domain.PermissionTerminalManage, terminalHandler.GetTerminals))
+	http.Handle("POST /api/terminals", authorize(domain.PermissionTerminalManage, terminalHandler.CreateTerminal))
+	http.Handle("PUT /api/terminals/{id}", authorize(domain.PermissionTerminalManage, terminalHandler.UpdateTerminal))
+	// =================================================================
+
+	// =================== Tenant ===================================
+	tenantRepository := repository.NewTenantRepository(db)
+	tenantService := service.NewTenantService(tenantRepository)
+	tenantHandler := handler.NewTenantHandler(tenantService)
+
+	http.Handle("GET /api/tenants", authorize(domain.PermissionTenantManage, tenantHandler.GetTenants))
+	http.Handle("POST /api/tenants", authorize(domain.PermissionTenantManage, tenantHandler.CreateTenant))
+	// =================================================================
+
+	// =================== API Key ===================================
+	apiKeyService := service.NewAPIKeyService(apiKeyRepository)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+
+	http.Handle("GET /api/api-keys", authorize(domain.PermissionAPIKeyManage, apiKeyHandler.GetAPIKeys))
+	http.Handle("POST /api/api-keys", authorize(domain.PermissionAPIKeyManage, apiKeyHandler.CreateAPIKey))
+	http.Handle("POST /api/api-keys/{id}/rotate", authorize(domain.PermissionAPIKeyManage, apiKeyHandler.RotateAPIKey))
+	http.Handle("POST /api/api-keys/{id}/revoke", authorize(domain.PermissionAPIKeyManage, apiKeyHandler.RevokeAPIKey))
+	// =================================================================
+
+	// =================== Audit ===================================
+	auditRepository := repository.NewAuditRepository(db)
+	auditService := service.NewAuditService(auditRepository)
+	auditHandler := handler.NewAuditHandler(auditService)
+
+	http.Handle("GET /api/audit-logs", authorize(domain.PermissionAuditRead, auditHandler.GetAuditLogs))
+	// =================================================================
+
+	// =================== Role ===================================
+	roleRepository := repository.NewRoleRepository(db)
+	roleService := service.NewRoleService(roleRepository, userRepository)
+	roleHandler := handler.NewRoleHandler(roleService)
+
+	http.Handle("GET /api/permissions", authorize(domain.PermissionRoleManage, roleHandler.GetPermissions))
+	http.Handle("GET /api/roles", authorize(domain.PermissionRoleManage, roleHandler.GetRoles))
+	http.Handle("POST /api/roles", authorize(domain.PermissionRoleManage, roleHandler.CreateRole))
+	http.Handle("GET /api/roles/{id}", authorize(domain.PermissionRoleManage, roleHandler.GetRoleByID))
+	http.Handle("PUT /api/roles/{id}", authorize(domain.PermissionRoleManage, roleHandler.UpdateRole))
+	http.Handle("DELETE /api/roles/{id}", authorize(domain.PermissionRoleManage, roleHandler.DeleteRole))
+	http.Handle("PUT /api/users/{id}/roles", authorize(domain.PermissionRoleManage, roleHandler.SetUserRoles))
+	// =================================================================
+
 	// =================== Product ===================================
 	productRepository := repository.NewProductRepository(db)
-	productService := service.NewProductService(productRepository)
+	variantRepository := repository.NewVariantRepository(db)
+	priceScheduleRepository := repository.NewPriceScheduleRepository(db)
+	productService := service.NewProductService(productRepository, variantRepository, priceScheduleRepository)
 	productHandler := handler.NewProductHandler(productService)
 
-	http.HandleFunc("GET /api/products", productHandler.GetProducts)
-	http.HandleFunc("GET /api/products/", productHandler.GetProductByID)
-	http.HandleFunc("POST /api/products", productHandler.CreateProduct)
-	http.HandleFunc("PUT /api/products/", productHandler.UpdateProduct)
-	http.HandleFunc("DELETE /api/products/", productHandler.DeleteProduct)
+	http.Handle("GET /api/products", authorize(domain.PermissionProductRead, productHandler.GetProducts))
+	http.Handle("GET /api/products/export", authorize(domain.PermissionProductRead, productHandler.ExportProducts))
+	http.Handle("GET /api/products/", authorize(domain.PermissionProductRead, productHandler.GetProductByID))
+	http.Handle("POST /api/products", authorize(domain.PermissionProductWrite, productHandler.CreateProduct))
+	http.Handle("POST /api/products/bulk-update", authorize(domain.PermissionProductWrite, productHandler.BulkUpdateProducts))
+	http.Handle("PUT /api/products/", authorize(domain.PermissionProductWrite, conditional(productHandler.UpdateProduct)))
+	http.Handle("PATCH /api/products/", authorize(domain.PermissionProductWrite, conditional(productHandler.PatchProduct)))
+	http.Handle("DELETE /api/products/", authorize(domain.PermissionProductDelete, conditional(productHandler.DeleteProduct)))
+	http.Handle("GET /api/products/{id}/components", authorize(domain.PermissionProductRead, productHandler.GetComponents))
+	http.Handle("PUT /api/products/{id}/components", authorize(domain.PermissionProductWrite, productHandler.ReplaceComponents))
 	// =================================================================
 
 	// =================== Category ===================================
@@ -50,11 +169,239 @@ func main() {
 	categoryService := service.NewCategoryService(categoryRepository)
 	categoryHandler := handler.NewCategoryHandler(categoryService)
 
-	http.HandleFunc("GET /api/categories", categoryHandler.GetCategories)
-	http.HandleFunc("GET /api/categories/", categoryHandler.GetCategoryByID)
-	http.HandleFunc("POST /api/categories", categoryHandler.CreateCategory)
-	http.HandleFunc("PUT /api/categories/", categoryHandler.UpdateCategory)
-	http.HandleFunc("DELETE /api/categories/", categoryHandler.DeleteCategory)
+	http.Handle("GET /api/categories", authorize(domain.PermissionCategoryRead, categoryHandler.GetCategories))
+	http.Handle("GET /api/categories/", authorize(domain.PermissionCategoryRead, categoryHandler.GetCategoryByID))
+	http.Handle("POST /api/categories", authorize(domain.PermissionCategoryWrite, categoryHandler.CreateCategory))
+	http.Handle("PUT /api/categories/", authorize(domain.PermissionCategoryWrite, conditional(categoryHandler.UpdateCategory)))
+	http.Handle("PATCH /api/categories/", authorize(domain.PermissionCategoryWrite, conditional(categoryHandler.PatchCategory)))
+	http.Handle("DELETE /api/categories/", authorize(domain.PermissionCategoryDelete, conditional(categoryHandler.DeleteCategory)))
+	// =================================================================
+
+	// =================== Variant ===================================
+	variantService := service.NewVariantService(variantRepository, productRepository)
+	variantHandler := handler.NewVariantHandler(variantService)
+
+	http.Handle("GET /api/products/{id}/variants", authorize(domain.PermissionProductRead, variantHandler.GetVariants))
+	http.Handle("PUT /api/products/{id}/variant-options", authorize(domain.PermissionProductWrite, variantHandler.ReplaceVariantOptions))
+	http.Handle("POST /api/products/{id}/variants", authorize(domain.PermissionProductWrite, variantHandler.CreateVariant))
+	http.Handle("PUT /api/variants/{id}", authorize(domain.PermissionProductWrite, variantHandler.UpdateVariant))
+	http.Handle("DELETE /api/variants/{id}", authorize(domain.PermissionProductDelete, variantHandler.DeleteVariant))
+	// =================================================================
+
+	// =================== Stock ===================================
+	stockRepository := repository.NewStockRepository(db)
+	stockService := service.NewStockService(stockRepository, productRepository)
+	stockHandler := handler.NewStockHandler(stockService)
+
+	http.Handle("GET /api/products/{id}/stock-movements", authorize(domain.PermissionProductRead, stockHandler.GetMovements))
+	// =================================================================
+
+	// =================== Modifier ===================================
+	modifierRepository := repository.NewModifierRepository(db)
+	modifierService := service.NewModifierService(modifierRepository, productRepository, categoryRepository)
+	modifierHandler := handler.NewModifierHandler(modifierService)
+
+	http.Handle("GET /api/modifier-groups", authorize(domain.PermissionProductRead, modifierHandler.GetModifierGroups))
+	http.Handle("GET /api/modifier-groups/{id}", authorize(domain.PermissionProductRead, modifierHandler.GetModifierGroupByID))
+	http.Handle("POST /api/modifier-groups", authorize(domain.PermissionProductWrite, modifierHandler.CreateModifierGroup))
+	http.Handle("PUT /api/modifier-groups/{id}", authorize(domain.PermissionProductWrite, modifierHandler.UpdateModifierGroup))
+	http.Handle("DELETE /api/modifier-groups/{id}", authorize(domain.PermissionProductWrite, modifierHandler.DeleteModifierGroup))
+	http.Handle("GET /api/products/{id}/modifier-groups", authorize(domain.PermissionProductRead, modifierHandler.GetProductModifierGroups))
+	http.Handle("PUT /api/products/{id}/modifier-groups", authorize(domain.PermissionProductWrite, modifierHandler.SetProductModifierGroups))
+	http.Handle("PUT /api/categories/{id}/modifier-groups", authorize(domain.PermissionCategoryWrite, modifierHandler.SetCategoryModifierGroups))
+	// =================================================================
+
+	// =================== Unit ===================================
+	unitRepository := repository.NewUnitRepository(db)
+	unitService := service.NewUnitService(unitRepository, productRepository)
+	unitHandler := handler.NewUnitHandler(unitService)
+
+	http.Handle("GET /api/products/{id}/units", authorize(domain.PermissionProductRead, unitHandler.GetUnits))
+	http.Handle("POST /api/products/{id}/units", authorize(domain.PermissionProductWrite, unitHandler.CreateUnit))
+	http.Handle("PUT /api/units/{id}", authorize(domain.PermissionProductWrite, unitHandler.UpdateUnit))
+	http.Handle("DELETE /api/units/{id}", authorize(domain.PermissionProductDelete, unitHandler.DeleteUnit))
+	// =================================================================
+
+	// =================== Purchase ===================================
+	purchaseRepository := repository.NewPurchaseRepository(db)
+	purchaseService := service.NewPurchaseService(
+		purchaseRepository,
+		productRepository,
+		variantRepository,
+		unitRepository,
+	)
+	purchaseHandler := handler.NewPurchaseHandler(purchaseService)
+
+	http.Handle("POST /api/purchases", authorize(domain.PermissionPurchaseWrite, purchaseHandler.CreatePurchase))
+	http.Handle("GET /api/purchases", authorize(domain.PermissionPurchaseRead, purchaseHandler.GetPurchases))
+	http.Handle("GET /api/purchases/{id}", authorize(domain.PermissionPurchaseRead, purchaseHandler.GetPurchaseByID))
+	// =================================================================
+
+	// =================== Promotion ===================================
+	promotionRepository := repository.NewPromotionRepository(db)
+	promotionService := service.NewPromotionService(promotionRepository, productRepository, categoryRepository)
+	promotionHandler := handler.NewPromotionHandler(promotionService)
+
+	http.Handle("GET /api/promotions", authorize(domain.PermissionPromotionRead, promotionHandler.GetPromotions))
+	http.Handle("GET /api/promotions/{id}", authorize(domain.PermissionPromotionRead, promotionHandler.GetPromotionByID))
+	http.Handle("POST /api/promotions", authorize(domain.PermissionPromotionWrite, promotionHandler.CreatePromotion))
+	http.Handle("PUT /api/promotions/{id}", authorize(domain.PermissionPromotionWrite, promotionHandler.UpdatePromotion))
+	http.Handle("DELETE /api/promotions/{id}", authorize(domain.PermissionPromotionWrite, promotionHandler.DeletePromotion))
+	// =================================================================
+
+	// =================== Voucher ===================================
+	voucherRepository := repository.NewVoucherRepository(db)
+	voucherService := service.NewVoucherService(voucherRepository)
+	voucherHandler := handler.NewVoucherHandler(voucherService)
+
+	http.Handle("GET /api/vouchers", authorize(domain.PermissionPromotionRead, voucherHandler.GetVouchers))
+	http.Handle("GET /api/vouchers/{id}", authorize(domain.PermissionPromotionRead, voucherHandler.GetVoucherByID))
+	http.Handle("POST /api/vouchers", authorize(domain.PermissionPromotionWrite, voucherHandler.CreateVoucher))
+	http.Handle("POST /api/vouchers/validate", authorize(domain.PermissionTransactionCreate, voucherHandler.ValidateVoucher))
+	http.Handle("PUT /api/vouchers/{id}", authorize(domain.PermissionPromotionWrite, voucherHandler.UpdateVoucher))
+	http.Handle("DELETE /api/vouchers/{id}", authorize(domain.PermissionPromotionWrite, voucherHandler.DeleteVoucher))
+	// =================================================================
+
+	// =================== Tax ===================================
+	taxRepository := repository.NewTaxRepository(db)
+	taxService := service.NewTaxService(taxRepository, productRepository, categoryRepository)
+	taxHandler := handler.NewTaxHandler(taxService)
+
+	http.Handle("GET /api/tax-rates", authorize(domain.PermissionSettingsRead, taxHandler.GetTaxRates))
+	http.Handle("POST /api/tax-rates", authorize(domain.PermissionSettingsWrite, taxHandler.CreateTaxRate))
+	http.Handle("PUT /api/tax-rates/{id}", authorize(domain.PermissionSettingsWrite, taxHandler.UpdateTaxRate))
+	http.Handle("DELETE /api/tax-rates/{id}", authorize(domain.PermissionSettingsWrite, taxHandler.DeleteTaxRate))
+	http.Handle("GET /api/settings/tax", authorize(domain.PermissionSettingsRead, taxHandler.GetStoreSettings))
+	http.Handle("PUT /api/settings/tax", authorize(domain.PermissionSettingsWrite, taxHandler.UpdateStoreSettings))
+	http.Handle("PUT /api/products/{id}/tax", authorize(domain.PermissionProductWrite, taxHandler.SetProductTax))
+	http.Handle("PUT /api/categories/{id}/tax", authorize(domain.PermissionCategoryWrite, taxHandler.SetCategoryTax))
+	// =================================================================
+
+	// =================== Customer ===================================
+	customerRepository := repository.NewCustomerRepository(db)
+	transactionRepository := repository.NewTransactionRepository(db)
+	customerService := service.NewCustomerService(customerRepository, transactionRepository)
+	customerHandler := handler.NewCustomerHandler(customerService)
+
+	http.Handle("GET /api/customers", authorize(domain.PermissionCustomerRead, customerHandler.GetCustomers))
+	http.Handle("POST /api/customers", authorize(domain.PermissionCustomerWrite, customerHandler.CreateCustomer))
+	http.Handle("GET /api/customers/{id}", authorize(domain.PermissionCustomerRead, customerHandler.GetCustomerByID))
+	http.Handle("PUT /api/customers/{id}", authorize(domain.PermissionCustomerWrite, customerHandler.UpdateCustomer))
+	http.Handle("DELETE /api/customers/{id}", authorize(domain.PermissionCustomerDelete, customerHandler.DeleteCustomer))
+	http.Handle("GET /api/customers/{id}/transactions", authorize(domain.PermissionTransactionRead, customerHandler.GetCustomerTransactions))
+	// =================================================================
+
+	// =================== Price List ===================================
+	priceListRepository := repository.NewPriceListRepository(db)
+	priceListService := service.NewPriceListService(priceListRepository, productRepository, variantRepository, customerRepository)
+	priceListHandler := handler.NewPriceListHandler(priceListService)
+
+	http.Handle("GET /api/price-lists", authorize(domain.PermissionProductRead, priceListHandler.GetPriceLists))
+	http.Handle("POST /api/price-lists", authorize(domain.PermissionPriceWrite, priceListHandler.CreatePriceList))
+	http.Handle("GET /api/price-lists/{id}", authorize(domain.PermissionProductRead, priceListHandler.GetPriceListByID))
+	http.Handle("PUT /api/price-lists/{id}", authorize(domain.PermissionPriceWrite, priceListHandler.UpdatePriceList))
+	http.Handle("DELETE /api/price-lists/{id}", authorize(domain.PermissionPriceWrite, priceListHandler.DeletePriceList))
+	http.Handle("PUT /api/customers/{id}/price-list", authorize(domain.PermissionPriceWrite, priceListHandler.SetCustomerPriceList))
+	// =================================================================
+
+	// =================== Price Schedule ===================================
+	priceScheduleService := service.NewPriceScheduleService(priceScheduleRepository, productRepository, categoryRepository, tenantRepository)
+	priceScheduleHandler := handler.NewPriceScheduleHandler(priceScheduleService)
+
+	http.Handle("GET /api/products/{id}/scheduled-prices", authorize(domain.PermissionProductRead, priceScheduleHandler.GetScheduledPrices))
+	http.Handle("POST /api/products/{id}/scheduled-prices", authorize(domain.PermissionPriceWrite, priceScheduleHandler.CreateScheduledPrice))
+	http.Handle("DELETE /api/scheduled-prices/{id}", authorize(domain.PermissionPriceWrite, priceScheduleHandler.DeleteScheduledPrice))
+	http.Handle("GET /api/price-windows", authorize(domain.PermissionProductRead, priceScheduleHandler.GetPriceWindows))
+	http.Handle("POST /api/price-windows", authorize(domain.PermissionPriceWrite, priceScheduleHandler.CreatePriceWindow))
+	http.Handle("GET /api/price-windows/{id}", authorize(domain.PermissionProductRead, priceScheduleHandler.GetPriceWindowByID))
+	http.Handle("PUT /api/price-windows/{id}", authorize(domain.PermissionPriceWrite, priceScheduleHandler.UpdatePriceWindow))
+	http.Handle("DELETE /api/price-windows/{id}", authorize(domain.PermissionPriceWrite, priceScheduleHandler.DeletePriceWindow))
+
+	// write scheduled prices to their products once they are due
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for ; ; <-ticker.C {
+			if err := priceScheduleService.ApplyDuePrices(context.Background(), time.Now()); err != nil {
+				log.Printf("failed to apply scheduled prices: %v", err)
+			}
+		}
+	}()
+	// =================================================================
+
+	// =================== Price History ===================================
+	priceHistoryRepository := repository.NewPriceHistoryRepository(db)
+	priceHistoryService := service.NewPriceHistoryService(priceHistoryRepository, productRepository)
+	priceHistoryHandler := handler.NewPriceHistoryHandler(priceHistoryService)
+
+	http.Handle("GET /api/products/{id}/price-history", authorize(domain.PermissionProductRead, priceHistoryHandler.GetPriceHistory))
+	http.Handle("GET /api/reports/price-changes", authorize(domain.PermissionReportRead, priceHistoryHandler.GetPriceChanges))
+	// =================================================================
+
+	// =================== Loyalty ===================================
+	loyaltyRepository := repository.NewLoyaltyRepository(db)
+	loyaltyService := service.NewLoyaltyService(loyaltyRepository, productRepository, categoryRepository, customerRepository)
+	loyaltyHandler := handler.NewLoyaltyHandler(loyaltyService)
+
+	http.Handle("GET /api/settings/loyalty", authorize(domain.PermissionSettingsRead, loyaltyHandler.GetLoyaltySettings))
+	http.Handle("PUT /api/settings/loyalty", authorize(domain.PermissionSettingsWrite, loyaltyHandler.UpdateLoyaltySettings))
+	http.Handle("PUT /api/products/{id}/loyalty", authorize(domain.PermissionProductWrite, loyaltyHandler.SetProductLoyalty))
+	http.Handle("PUT /api/categories/{id}/loyalty", authorize(domain.PermissionCategoryWrite, loyaltyHandler.SetCategoryLoyalty))
+	http.Handle("GET /api/customers/{id}/loyalty", authorize(domain.PermissionCustomerRead, loyaltyHandler.GetCustomerLoyalty))
+	// =================================================================
+
+	// =================== Receivable ===================================
+	receivableRepository := repository.NewReceivableRepository(db)
+	receivableService := service.NewReceivableService(receivableRepository, customerRepository)
+	receivableHandler := handler.NewReceivableHandler(receivableService)
+
+	http.Handle("PUT /api/customers/{id}/credit-limit", authorize(domain.PermissionCreditManage, receivableHandler.SetCreditLimit))
+	http.Handle("GET /api/customers/{id}/receivables", authorize(domain.PermissionCustomerRead, receivableHandler.GetReceivableAccount))
+	http.Handle("POST /api/customers/{id}/repayments", authorize(domain.PermissionCreditManage, receivableHandler.RecordRepayment))
+	http.Handle("GET /api/reports/receivables-aging", authorize(domain.PermissionReportRead, receivableHandler.GetAgingReport))
+	// =================================================================
+
+	// =================== Gift Card ===================================
+	giftCardRepository := repository.NewGiftCardRepository(db)
+	giftCardService := service.NewGiftCardService(giftCardRepository)
+	giftCardHandler := handler.NewGiftCardHandler(giftCardService)
+
+	http.Handle("GET /api/gift-cards", authorize(domain.PermissionReportRead, giftCardHandler.GetGiftCards))
+	http.Handle("POST /api/gift-cards/balance", authorize(domain.PermissionTransactionCreate, giftCardHandler.GetGiftCardBalance))
+	// =================================================================
+
+	// =================== Approval ===================================
+	approvalService := service.NewApprovalService(approvalRepository)
+	approvalHandler := handler.NewApprovalHandler(approvalService)
+
+	http.Handle("GET /api/settings/approvals", authorize(domain.PermissionSettingsRead, approvalHandler.GetApprovalSettings))
+	http.Handle("PUT /api/settings/approvals", authorize(domain.PermissionRoleManage, approvalHandler.UpdateApprovalSettings))
+	// =================================================================
+
+	// =================== Transaction ===================================
+	transactionService := service.NewTransactionService(
+		transactionRepository,
+		productRepository,
+		variantRepository,
+		modifierRepository,
+		unitRepository,
+		promotionRepository,
+		voucherRepository,
+		taxRepository,
+		priceListRepository,
+		priceScheduleRepository,
+		customerRepository,
+		loyaltyRepository,
+		giftCardRepository,
+		approvalRepository,
+	)
+	transactionHandler := handler.NewTransactionHandler(transactionService)
+
+	http.Handle("POST /api/transactions/checkout", authorize(domain.PermissionTransactionCreate, approvable(transactionHandler.Checkout)))
+	http.Handle("POST /api/transactions/quote", authorize(domain.PermissionTransactionCreate, transactionHandler.Quote))
+	http.Handle("GET /api/transactions", authorize(domain.PermissionTransactionRead, transactionHandler.GetTransactions))
+	http.Handle("GET /api/transactions/{id}", authorize(domain.PermissionTransactionRead, transactionHandler.GetTransactionByID))
+	http.Handle("POST /api/transactions/{id}/refund", authorize(domain.PermissionTransactionCreate, approvable(transactionHandler.RefundTransaction)))
 	// =================================================================
 
 	// =================== Health ===================================
@@ -77,7 +424,20 @@ func main() {
 	addr := ":" + port
 
 	fmt.Println("server running di", addr)
-	if err = http.ListenAndServe(addr, nil); err != nil {
+	// only login, token refresh, health and the docs are open; every other
+	// route needs a login or API key even if it was registered without one
+	mux := middleware.AuthenticateByDefault(
+		http.DefaultServeMux,
+		authService,
+		"POST /api/auth/login",
+		"POST /api/auth/pin-login",
+		"POST /api/auth/refresh",
+		"GET /health",
+		"/swagger/",
+	)
+	// tag every request with an ID and the client address for the audit log
+	mux = middleware.RequestID(middleware.ClientIP(cfg.App.TrustProxy)(mux))
+	if err = http.ListenAndServe(addr, mux); err != nil {
 		panic("failed running server")
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"log"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -11,11 +12,31 @@ import (
 type Config struct {
 	App      AppConfig      `mapstructure:"app"`
 	Database DatabaseConfig `mapstructure:"database"`
+	Auth     AuthConfig     `mapstructure:"auth"`
 }
 
+// AppConfig configures the server. With TrustProxy the client address is
+// taken from the X-Forwarded-For header set by a reverse proxy.
 type AppConfig struct {
-	Name string `mapstructure:"name"`
-	Port string `mapstructure:"port"`
+	Name           string `mapstructure:"name"`
+	Port           string `mapstructure:"port"`
+	RequireIfMatch bool   `mapstructure:"require_if_match"`
+	TrustProxy     bool   `mapstructure:"trust_proxy"`
+}
+
+// AuthConfig configures login. The admin account is only created when the
+// users table is still empty. A user's PIN is locked for PINLockout after
+// PINMaxAttempts failures in a row. A supervisor's approval grant works for
+// ApprovalGrantTTL.
+type AuthConfig struct {
+	JWTSecret        string        `mapstructure:"jwt_secret"`
+	AccessTokenTTL   time.Duration `mapstructure:"access_token_ttl"`
+	RefreshTokenTTL  time.Duration `mapstructure:"refresh_token_ttl"`
+	AdminUsername    string        `mapstructure:"admin_username"`
+	AdminPassword    string        `mapstructure:"admin_password"`
+	PINMaxAttempts   int           `mapstructure:"pin_max_attempts"`
+	PINLockout       time.Duration `mapstructure:"pin_lockout"`
+	ApprovalGrantTTL time.Duration `mapstructure:"approval_grant_ttl"`
 }
 
 type DatabaseConfig struct {
@@ -41,9 +62,23 @@ func LoadConfig() *Config {
 
 	v.SetDefault("app.name", v.GetString("APP_NAME"))
 	v.SetDefault("app.port", v.GetString("APP_PORT"))
+	v.SetDefault("app.require_if_match", v.GetBool("APP_REQUIRE_IF_MATCH"))
+	v.SetDefault("app.trust_proxy", v.GetBool("APP_TRUST_PROXY"))
 	v.SetDefault("database.url", v.GetString("DATABASE_URL"))
 	v.SetDefault("database.max_open_conns", v.GetInt("DATABASE_MAX_OPEN_CONNS"))
 	v.SetDefault("database.max_idle_conns", v.GetInt("DATABASE_MAX_IDLE_CONNS"))
+	v.SetDefault("auth.jwt_secret", v.GetString("AUTH_JWT_SECRET"))
+	// AUTH_ACCESS_TOKEN_TTL and AUTH_REFRESH_TOKEN_TTL override these through
+	// AutomaticEnv, as Go durations such as 15m or 720h.
+	v.SetDefault("auth.access_token_ttl", 15*time.Minute)
+	v.SetDefault("auth.refresh_token_ttl", 30*24*time.Hour)
+	v.SetDefault("auth.admin_username", v.GetString("AUTH_ADMIN_USERNAME"))
+	v.SetDefault("auth.admin_password", v.GetString("AUTH_ADMIN_PASSWORD"))
+	// likewise AUTH_PIN_MAX_ATTEMPTS and AUTH_PIN_LOCKOUT
+	v.SetDefault("auth.pin_max_attempts", 5)
+	v.SetDefault("auth.pin_lockout", 15*time.Minute)
+	// and AUTH_APPROVAL_GRANT_TTL
+	v.SetDefault("auth.approval_grant_ttl", 5*time.Minute)
 
 	var config Config
 	if err := v.Unmarshal(&config); err != nil {
@@ -7,7 +7,7 @@ import (
 	"log"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 func NewPostgres(config *config.DatabaseConfig) (*sql.DB, func() error, error) {
@@ -16,12 +16,13 @@ func NewPostgres(config *config.DatabaseConfig) (*sql.DB, func() error, error) {
 		return nil, nil, utils.ErrEmptyDatabaseURL
 	}
 
-	// Open connection
-	db, err := sql.Open("postgres", config.URL)
+	// Open connection, scoping every statement to the tenant of its context
+	connector, err := pq.NewConnector(config.URL)
 	if err != nil {
 		log.Println("failed to open database connection", err)
 		return nil, nil, err
 	}
+	db := sql.OpenDB(tenantConnector{Connector: connector})
 
 	// Set connection pool
 	db.SetMaxOpenConns(config.MaxOpenConns)
@@ -35,6 +36,27 @@ func NewPostgres(config *config.DatabaseConfig) (*sql.DB, func() error, error) {
 		return nil, nil, err
 	}
 
+	// Refuse a role that row level security does not apply to, since it
+	// would see every tenant's data. The role that ran the migrations owns
+	// the tenant lookups, which may read every tenant's login rows.
+	var bypassesRLS bool
+	err = db.QueryRow(
+		`SELECT rolsuper OR rolbypassrls OR EXISTS (
+			SELECT 1 FROM pg_proc WHERE proname = 'tenant_of_username' AND proowner = pg_roles.oid
+		)
+		FROM pg_roles WHERE rolname = current_user`,
+	).Scan(&bypassesRLS)
+	if err != nil {
+		log.Printf("failed to check database role: %v", err)
+		db.Close()
+		return nil, nil, err
+	}
+	if bypassesRLS {
+		log.Println("database role bypasses row level security")
+		db.Close()
+		return nil, nil, utils.ErrDatabaseBypassRLS
+	}
+
 	// Close function
 	closeDB := func() error {
 		log.Println("closing database connection")
@@ -0,0 +1,142 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"kasir-api/internal/utils"
+	"strconv"
+)
+
+// tenantConnector opens connections that tell PostgreSQL which tenant a
+// statement runs for. Before each statement the connection sets
+// app.tenant_id to the tenant in the statement's context, or clears it,
+// and the row level security policies only let that tenant's rows through.
+// It also sets who made the request and from where (app.actor,
+// app.actor_id, app.api_key_id, app.ip and app.request_id), which the
+// audit triggers record with every change.
+type tenantConnector struct {
+	driver.Connector
+}
+
+// pgConn is what database/sql uses of a lib/pq connection.
+type pgConn interface {
+	driver.Conn
+	driver.QueryerContext
+	driver.ExecerContext
+	driver.ConnPrepareContext
+	driver.ConnBeginTx
+	driver.Pinger
+	driver.SessionResetter
+	driver.Validator
+}
+
+func (c tenantConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pg, ok := conn.(pgConn)
+	if !ok {
+		conn.Close()
+		return nil, errors.New("database driver does not support contexts")
+	}
+	return &tenantConn{pgConn: pg}, nil
+}
+
+type tenantConn struct {
+	pgConn
+	// session is what the app settings hold, if known is set. Rolling back
+	// a transaction that changed them restores earlier values.
+	session session
+	known   bool
+}
+
+// session is the value of each app setting, in the order set.
+type session [6]string
+
+func sessionFromContext(ctx context.Context) session {
+	var s session
+	if tenantID, ok := utils.TenantIDFromContext(ctx); ok {
+		s[0] = strconv.Itoa(tenantID)
+	}
+	s[1] = utils.ActorFromContext(ctx)
+	if user := utils.AuthUserFromContext(ctx); user != nil {
+		if id := user.UserID(); id != nil {
+			s[2] = strconv.Itoa(*id)
+		}
+		if user.APIKeyID != nil {
+			s[3] = strconv.Itoa(*user.APIKeyID)
+		}
+	}
+	s[4] = utils.ClientIPFromContext(ctx)
+	s[5] = utils.RequestIDFromContext(ctx)
+	return s
+}
+
+func (c *tenantConn) setSession(ctx context.Context) error {
+	s := sessionFromContext(ctx)
+	if c.known && c.session == s {
+		return nil
+	}
+
+	c.known = false
+	args := make([]driver.NamedValue, len(s))
+	for i, value := range s {
+		args[i] = driver.NamedValue{Ordinal: i + 1, Value: value}
+	}
+	_, err := c.pgConn.ExecContext(
+		ctx,
+		`SELECT set_config('app.tenant_id', $1, false), set_config('app.actor', $2, false),
+			set_config('app.actor_id', $3, false), set_config('app.api_key_id', $4, false),
+			set_config('app.ip', $5, false), set_config('app.request_id', $6, false)`,
+		args,
+	)
+	if err != nil {
+		return err
+	}
+	c.session, c.known = s, true
+	return nil
+}
+
+func (c *tenantConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := c.setSession(ctx); err != nil {
+		return nil, err
+	}
+	return c.pgConn.QueryContext(ctx, query, args)
+}
+
+func (c *tenantConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := c.setSession(ctx); err != nil {
+		return nil, err
+	}
+	return c.pgConn.ExecContext(ctx, query, args)
+}
+
+func (c *tenantConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if err := c.setSession(ctx); err != nil {
+		return nil, err
+	}
+	return c.pgConn.PrepareContext(ctx, query)
+}
+
+func (c *tenantConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if err := c.setSession(ctx); err != nil {
+		return nil, err
+	}
+	tx, err := c.pgConn.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &tenantTx{Tx: tx, conn: c}, nil
+}
+
+type tenantTx struct {
+	driver.Tx
+	conn *tenantConn
+}
+
+func (t *tenantTx) Rollback() error {
+	t.conn.known = false
+	return t.Tx.Rollback()
+}
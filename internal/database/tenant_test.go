@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"kasir-api/internal/config"
+	"kasir-api/internal/utils"
+	"os"
+	"testing"
+)
+
+// openTestDB connects to the migrated database in TEST_DATABASE_URL as the
+// API would. NewPostgres refuses roles that bypass row level security, so
+// the tests always run as one it applies to.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, closeDB, err := NewPostgres(&config.DatabaseConfig{URL: url, MaxOpenConns: 2, MaxIdleConns: 2})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { closeDB() })
+	return db
+}
+
+func TestConnectionSetsSessionFromContext(t *testing.T) {
+	db := openTestDB(t)
+
+	ctx := utils.WithActor(utils.WithTenantID(context.Background(), 7), "alice")
+	var tenant, actor string
+	err := db.QueryRowContext(ctx, "SELECT current_setting('app.tenant_id'), current_setting('app.actor')").Scan(&tenant, &actor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tenant != "7" || actor != "alice" {
+		t.Errorf("app.tenant_id = %q, app.actor = %q, want 7 and alice", tenant, actor)
+	}
+
+	err = db.QueryRowContext(context.Background(), "SELECT current_setting('app.tenant_id'), current_setting('app.actor')").Scan(&tenant, &actor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tenant != "" || actor != "" {
+		t.Errorf("without a tenant app.tenant_id = %q, app.actor = %q, want both cleared", tenant, actor)
+	}
+}
+
+func TestNoRowsWithoutTenant(t *testing.T) {
+	db := openTestDB(t)
+
+	for _, table := range []string{
+		"products", "customers", "transactions", "gift_cards", "audit_logs",
+		"users", "roles", "role_permissions", "user_roles", "terminals", "api_keys",
+	} {
+		var count int
+		if err := db.QueryRowContext(context.Background(), "SELECT COUNT(*) FROM "+table).Scan(&count); err != nil {
+			t.Fatalf("%s: %v", table, err)
+		}
+		if count != 0 {
+			t.Errorf("%s: %d rows visible without a tenant, want 0", table, count)
+		}
+	}
+}
+
+func TestRollbackRestoresTenant(t *testing.T) {
+	db := openTestDB(t)
+	db.SetMaxOpenConns(1)
+
+	tx, err := db.BeginTx(utils.WithTenantID(context.Background(), 1), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(utils.WithTenantID(context.Background(), 2), "SELECT 1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	var tenant string
+	err = db.QueryRowContext(utils.WithTenantID(context.Background(), 2), "SELECT current_setting('app.tenant_id')").Scan(&tenant)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tenant != "2" {
+		t.Errorf("app.tenant_id = %q after rollback, want 2", tenant)
+	}
+}
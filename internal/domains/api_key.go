@@ -0,0 +1,24 @@
+package domains
+
+import "time"
+
+// APIKeyPrefix starts every API key, which tells it apart from a user's
+// access token in the Authorization header.
+const APIKeyPrefix = "kasir_"
+
+// APIKey lets an integration call the API with its own permissions. Key is
+// only returned when the key is created or rotated.
+type APIKey struct {
+	ID          int        `json:"id"`
+	TenantID    int        `json:"-"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	Key         string     `json:"key,omitempty"`
+	Permissions []string   `json:"permissions"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	RotatedAt   *time.Time `json:"rotated_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedBy   *int       `json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
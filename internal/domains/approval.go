@@ -0,0 +1,58 @@
+package domains
+
+import "time"
+
+const (
+	ApprovalActionVoid          = "void"
+	ApprovalActionDiscount      = "discount"
+	ApprovalActionPriceOverride = "price_override"
+)
+
+const (
+	ApprovalMethodSelf  = "self"
+	ApprovalMethodPIN   = "pin"
+	ApprovalMethodToken = "token"
+)
+
+// ApprovalPermissions maps every action that needs a supervisor to the
+// permission that lets a user do it alone, or approve it for someone else.
+var ApprovalPermissions = map[string]string{
+	ApprovalActionVoid:          PermissionTransactionVoid,
+	ApprovalActionDiscount:      PermissionDiscountApprove,
+	ApprovalActionPriceOverride: PermissionPriceOverride,
+}
+
+// Approval records who asked for a sensitive operation and who approved
+// it. Method is "self" when the requester was allowed to do it alone.
+type Approval struct {
+	ID            int       `json:"id"`
+	TransactionID int       `json:"transaction_id"`
+	Action        string    `json:"action"`
+	Details       string    `json:"details"`
+	RequestedBy   *int      `json:"requested_by,omitempty"`
+	ApprovedBy    *int      `json:"approved_by,omitempty"`
+	Method        string    `json:"method"`
+	GrantID       *int      `json:"grant_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ApprovalGrant lets a supervisor approve from their own device without
+// handing over their login. It covers Actions on one transaction, or on a
+// new sale when TransactionID is nil, until ExpiresAt, and is used up by the
+// first operation it approves. Token is only returned when it is issued.
+type ApprovalGrant struct {
+	ID            int        `json:"id"`
+	Token         string     `json:"token,omitempty"`
+	ApproverID    int        `json:"approver_id"`
+	Actions       []string   `json:"actions"`
+	TransactionID *int       `json:"transaction_id,omitempty"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	ConsumedAt    *time.Time `json:"consumed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// ApprovalSettings configures when a manual discount needs approval: when
+// it is more than DiscountThresholdPercent of the subtotal.
+type ApprovalSettings struct {
+	DiscountThresholdPercent float64 `json:"discount_threshold_percent"`
+}
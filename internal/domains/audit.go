@@ -0,0 +1,84 @@
+package domains
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// Entity types of the audit log, one per audited table.
+const (
+	AuditEntityProduct           = "product"
+	AuditEntityCategory          = "category"
+	AuditEntityVariant           = "variant"
+	AuditEntityVariantOption     = "variant_option"
+	AuditEntityUnit              = "unit"
+	AuditEntityProductComponent  = "product_component"
+	AuditEntityPurchase          = "purchase"
+	AuditEntityPurchaseItem      = "purchase_item"
+	AuditEntityStockMovement     = "stock_movement"
+	AuditEntityPriceList         = "price_list"
+	AuditEntityPriceListItem     = "price_list_item"
+	AuditEntityCustomerPriceList = "customer_price_list"
+	AuditEntityScheduledPrice    = "scheduled_price"
+	AuditEntityPriceWindow       = "price_window"
+	AuditEntityAPIKey            = "api_key"
+	AuditEntityTenant            = "tenant"
+	AuditEntityRole              = "role"
+	AuditEntityRolePermission    = "role_permission"
+	AuditEntityUserRole          = "user_role"
+	AuditEntityUser              = "user"
+	AuditEntityTerminal          = "terminal"
+	AuditEntityCustomer          = "customer"
+	AuditEntityPromotion         = "promotion"
+	AuditEntityPromotionProduct  = "promotion_product"
+	AuditEntityPromotionCategory = "promotion_category"
+	AuditEntityVoucher           = "voucher"
+	AuditEntityTaxRate           = "tax_rate"
+	AuditEntityStoreSettings     = "store_settings"
+	AuditEntityLoyaltySettings   = "loyalty_settings"
+	AuditEntityApprovalSettings  = "approval_settings"
+	AuditEntityModifierGroup     = "modifier_group"
+	AuditEntityModifier          = "modifier"
+	AuditEntityProductModifier   = "product_modifier_group"
+	AuditEntityCategoryModifier  = "category_modifier_group"
+	AuditEntityGiftCard          = "gift_card"
+	AuditEntityReceivable        = "receivable"
+)
+
+// AuditLog is one change to an entity. Changes holds only the fields that
+// changed: a create has no old values and a delete no new ones.
+type AuditLog struct {
+	ID         int64                  `json:"id"`
+	Actor      string                 `json:"actor"`
+	ActorID    *int                   `json:"actor_id,omitempty"`
+	APIKeyID   *int                   `json:"api_key_id,omitempty"`
+	Action     string                 `json:"action"`
+	EntityType string                 `json:"entity_type"`
+	EntityID   int                    `json:"entity_id"`
+	Changes    map[string]AuditChange `json:"changes"`
+	IP         string                 `json:"ip"`
+	RequestID  string                 `json:"request_id"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+type AuditChange struct {
+	Old json.RawMessage `json:"old,omitempty"`
+	New json.RawMessage `json:"new,omitempty"`
+}
+
+type AuditLogFilter struct {
+	EntityType string
+	EntityID   *int
+	ActorID    *int
+	APIKeyID   *int
+	Action     string
+	RequestID  string
+	From       *time.Time
+	To         *time.Time
+}
@@ -4,4 +4,11 @@ type Category struct {
 	ID          int    `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	TaxRateID   *int   `json:"tax_rate_id"`
+	Version     int    `json:"version"`
+}
+
+type CategoryPatch struct {
+	Name        *string
+	Description *string
 }
@@ -0,0 +1,39 @@
+package domains
+
+import "time"
+
+// Customer is a registered customer. CreditLimit is how much they may owe
+// on account and CreditBalance how much they owe now.
+type Customer struct {
+	ID            int       `json:"id"`
+	Name          string    `json:"name"`
+	Phone         string    `json:"phone"`
+	Email         string    `json:"email"`
+	Address       string    `json:"address"`
+	Notes         string    `json:"notes"`
+	CreditLimit   int       `json:"credit_limit"`
+	CreditBalance int       `json:"credit_balance"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type CustomerFilter struct {
+	Search string
+	Phone  string
+}
+
+// CustomerStats summarises the purchases of a customer. A visit is one
+// transaction.
+type CustomerStats struct {
+	LifetimeSpend int        `json:"lifetime_spend"`
+	VisitCount    int        `json:"visit_count"`
+	FirstVisit    *time.Time `json:"first_visit"`
+	LastVisit     *time.Time `json:"last_visit"`
+}
+
+// CustomerHistory is a page of a customer's transactions with their
+// lifetime totals.
+type CustomerHistory struct {
+	Customer     Customer      `json:"customer"`
+	Stats        CustomerStats `json:"stats"`
+	Transactions []Transaction `json:"transactions"`
+}
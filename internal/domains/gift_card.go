@@ -0,0 +1,62 @@
+package domains
+
+import "time"
+
+const (
+	GiftCardEntryIssue   = "issue"
+	GiftCardEntryTopUp   = "top_up"
+	GiftCardEntryRedeem  = "redeem"
+	GiftCardEntryReverse = "reverse"
+)
+
+type GiftCard struct {
+	ID        int       `json:"id"`
+	Code      string    `json:"code"`
+	Balance   int       `json:"balance"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MaskedCode hides all but the last group of the code, for listings.
+func (g *GiftCard) MaskedCode() string {
+	if len(g.Code) <= 4 {
+		return g.Code
+	}
+	masked := []byte(g.Code)
+	for i := 0; i < len(masked)-4; i++ {
+		if masked[i] != '-' {
+			masked[i] = '*'
+		}
+	}
+	return string(masked)
+}
+
+// GiftCardEntry is a signed movement of a gift card balance.
+type GiftCardEntry struct {
+	ID            int       `json:"id"`
+	GiftCardID    int       `json:"gift_card_id"`
+	TransactionID *int      `json:"transaction_id,omitempty"`
+	Type          string    `json:"type"`
+	Amount        int       `json:"amount"`
+	BalanceAfter  int       `json:"balance_after"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// GiftCardSale is a gift card issued, or topped up when GiftCardID is set,
+// as part of a transaction.
+type GiftCardSale struct {
+	GiftCardID *int   `json:"gift_card_id,omitempty"`
+	Code       string `json:"code"`
+	Amount     int    `json:"amount"`
+	Balance    int    `json:"balance"`
+}
+
+type GiftCardStatement struct {
+	GiftCard GiftCard        `json:"gift_card"`
+	Entries  []GiftCardEntry `json:"entries"`
+}
+
+type CheckoutGiftCard struct {
+	Code   string
+	Amount int
+}
@@ -0,0 +1,49 @@
+package domains
+
+import "time"
+
+const (
+	LoyaltyEntryEarn    = "earn"
+	LoyaltyEntryRedeem  = "redeem"
+	LoyaltyEntryExpire  = "expire"
+	LoyaltyEntryReverse = "reverse"
+)
+
+// LoyaltySettings configures the loyalty program. Customers earn one point
+// per SpendPerPoint Rupiah paid and redeem each point for PointValue Rupiah.
+// Points expire ExpiryDays after they are earned, or never when it is 0.
+type LoyaltySettings struct {
+	Enabled         bool `json:"enabled"`
+	SpendPerPoint   int  `json:"spend_per_point"`
+	PointValue      int  `json:"point_value"`
+	ExpiryDays      int  `json:"expiry_days"`
+	MinRedeemPoints int  `json:"min_redeem_points"`
+}
+
+// ExpiresAt returns when points earned at t expire, or nil if they never do.
+func (s *LoyaltySettings) ExpiresAt(t time.Time) *time.Time {
+	if s.ExpiryDays == 0 {
+		return nil
+	}
+	expiresAt := t.AddDate(0, 0, s.ExpiryDays)
+	return &expiresAt
+}
+
+// LoyaltyEntry is a signed entry in a customer's points ledger. Remaining
+// is the unspent part of a positive entry.
+type LoyaltyEntry struct {
+	ID            int        `json:"id"`
+	CustomerID    int        `json:"customer_id"`
+	TransactionID *int       `json:"transaction_id,omitempty"`
+	Type          string     `json:"type"`
+	Points        int        `json:"points"`
+	Remaining     int        `json:"remaining"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type LoyaltyAccount struct {
+	CustomerID int            `json:"customer_id"`
+	Points     int            `json:"points"`
+	Entries    []LoyaltyEntry `json:"entries"`
+}
@@ -0,0 +1,27 @@
+package domains
+
+// ModifierGroup holds options such as "Sugar level" or "Add-ons". MaxSelect
+// of 0 means any number of modifiers may be picked from the group.
+type ModifierGroup struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	MinSelect int        `json:"min_select"`
+	MaxSelect int        `json:"max_select"`
+	Modifiers []Modifier `json:"modifiers"`
+}
+
+type Modifier struct {
+	ID                  int    `json:"id"`
+	GroupID             int    `json:"group_id"`
+	Name                string `json:"name"`
+	PriceDelta          int    `json:"price_delta"`
+	IngredientProductID *int   `json:"ingredient_product_id,omitempty"`
+	IngredientQuantity  int    `json:"ingredient_quantity,omitempty"`
+}
+
+type TransactionItemModifier struct {
+	ID         int    `json:"id"`
+	ModifierID int    `json:"modifier_id"`
+	Name       string `json:"name"`
+	PriceDelta int    `json:"price_delta"`
+}
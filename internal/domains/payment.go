@@ -0,0 +1,27 @@
+package domains
+
+const (
+	PaymentMethodCash      = "cash"
+	PaymentMethodPoints    = "points"
+	PaymentMethodOnAccount = "on_account"
+	PaymentMethodGiftCard  = "gift_card"
+)
+
+// TransactionPayment is one tender used to pay a transaction. For points,
+// Points is the number redeemed and Amount their value in Rupiah.
+type TransactionPayment struct {
+	ID            int    `json:"id"`
+	TransactionID int    `json:"transaction_id"`
+	Method        string `json:"method"`
+	Amount        int    `json:"amount"`
+	Points        int    `json:"points,omitempty"`
+	GiftCardID    *int   `json:"gift_card_id,omitempty"`
+}
+
+type CheckoutPayment struct {
+	Method     string
+	Amount     int
+	Points     int
+	Code       string
+	GiftCardID *int
+}
@@ -0,0 +1,34 @@
+package domains
+
+import "time"
+
+const (
+	PriceSourceManual   = "manual"
+	PriceSourceBulk     = "bulk"
+	PriceSourceImport   = "import"
+	PriceSourceSchedule = "schedule"
+)
+
+// PriceChangedBySystem attributes changes made without a user, such as
+// scheduled prices.
+const PriceChangedBySystem = "system"
+
+type PriceHistory struct {
+	ID          int       `json:"id"`
+	ProductID   int       `json:"product_id"`
+	VariantID   *int      `json:"variant_id,omitempty"`
+	ProductName string    `json:"product_name,omitempty"`
+	OldPrice    int       `json:"old_price"`
+	NewPrice    int       `json:"new_price"`
+	Source      string    `json:"source"`
+	ChangedBy   string    `json:"changed_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// PriceHistoryFilter selects price changes made in [From, To). An empty
+// Source matches every source.
+type PriceHistoryFilter struct {
+	From   time.Time
+	To     time.Time
+	Source string
+}
@@ -0,0 +1,30 @@
+package domains
+
+// PriceList holds alternative prices such as member or wholesale. Each item
+// is a price per base unit that applies from MinQuantity base units upward,
+// so several items for the same product form quantity breaks. An item
+// without a variant applies to every variant of the product.
+type PriceList struct {
+	ID          int             `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	IsDefault   bool            `json:"is_default"`
+	Items       []PriceListItem `json:"items"`
+}
+
+type PriceListItem struct {
+	ID          int  `json:"id"`
+	ProductID   int  `json:"product_id"`
+	VariantID   *int `json:"variant_id,omitempty"`
+	MinQuantity int  `json:"min_quantity"`
+	Price       int  `json:"price"`
+}
+
+// PriceQuote is the priced cart returned before checkout, without promotions,
+// vouchers or tax.
+type PriceQuote struct {
+	CustomerID  *int              `json:"customer_id,omitempty"`
+	PriceListID *int              `json:"price_list_id,omitempty"`
+	Items       []TransactionItem `json:"items"`
+	Subtotal    int               `json:"subtotal"`
+}
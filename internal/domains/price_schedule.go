@@ -0,0 +1,86 @@
+package domains
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	PriceWindowPercentage = "percentage"
+	PriceWindowPrice      = "price"
+)
+
+// ScheduledPrice is a future product price. Once StartsAt has passed it is
+// written to the product price and AppliedAt is set.
+type ScheduledPrice struct {
+	ID        int        `json:"id"`
+	ProductID int        `json:"product_id"`
+	Price     int        `json:"price"`
+	StartsAt  time.Time  `json:"starts_at"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// PriceWindow is a recurring time-of-day price for a product or a whole
+// category, such as happy hour. Percentage windows take Value percent off
+// the price; price windows sell a product at Value. A window never raises a
+// price.
+//
+// StartTime and EndTime use "15:04" in server time. DaysOfWeek holds
+// time.Weekday values and is empty for every day. A window that ends before
+// it starts runs past midnight and belongs to the day it started.
+type PriceWindow struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	ProductID      *int   `json:"product_id"`
+	CategoryID     *int   `json:"category_id"`
+	DaysOfWeek     []int  `json:"days_of_week"`
+	StartTime      string `json:"start_time"`
+	EndTime        string `json:"end_time"`
+	AdjustmentType string `json:"adjustment_type"`
+	Value          int    `json:"value"`
+	Active         bool   `json:"active"`
+}
+
+// Covers reports whether the window is running at t.
+func (w *PriceWindow) Covers(t time.Time) bool {
+	start, err := time.Parse("15:04", w.StartTime)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse("15:04", w.EndTime)
+	if err != nil {
+		return false
+	}
+
+	from := start.Hour()*60 + start.Minute()
+	to := end.Hour()*60 + end.Minute()
+	now := t.Hour()*60 + t.Minute()
+	day := t.Weekday()
+
+	switch {
+	case from == to:
+		return w.runsOn(day)
+	case from < to:
+		return now >= from && now < to && w.runsOn(day)
+	case now >= from:
+		return w.runsOn(day)
+	case now < to:
+		return w.runsOn((day + 6) % 7)
+	}
+	return false
+}
+
+func (w *PriceWindow) runsOn(day time.Weekday) bool {
+	return len(w.DaysOfWeek) == 0 || slices.Contains(w.DaysOfWeek, int(day))
+}
+
+// Apply returns the price during the window.
+func (w *PriceWindow) Apply(price int) int {
+	switch w.AdjustmentType {
+	case PriceWindowPercentage:
+		return price - price*w.Value/100
+	case PriceWindowPrice:
+		return min(price, w.Value)
+	}
+	return price
+}
@@ -1,9 +1,100 @@
 package domains
 
+const (
+	ProductTypeStandard = "standard"
+	ProductTypeBundle   = "bundle"
+	ProductTypeRecipe   = "recipe"
+)
+
+// DefaultBaseUnit is used when a product is created without a base unit.
+const DefaultBaseUnit = "pcs"
+
 type Product struct {
-	ID       int      `json:"id"`
-	Name     string   `json:"name"`
-	Price    int      `json:"price"`
-	Stock    int      `json:"stock"`
-	Category Category `json:"category"`
+	ID        int      `json:"id"`
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Price     int      `json:"price"`
+	Cost      int      `json:"cost"`
+	Stock     int      `json:"stock"`
+	BaseUnit  string   `json:"base_unit"`
+	TaxRateID *int     `json:"tax_rate_id"`
+	TaxExempt bool     `json:"tax_exempt"`
+	Version   int      `json:"version"`
+	Category  Category `json:"category"`
+
+	VariantOptions []VariantOption    `json:"variant_options,omitempty"`
+	Variants       []ProductVariant   `json:"variants,omitempty"`
+	Components     []ProductComponent `json:"components,omitempty"`
+
+	// CurrentPrice is Price after any running price window, and
+	// UpcomingPrices lists scheduled prices that are not due yet.
+	// TimedPrice is set when any window or scheduled price applies, so
+	// these prices can change without the product version.
+	CurrentPrice   *int             `json:"current_price,omitempty"`
+	UpcomingPrices []ScheduledPrice `json:"upcoming_prices,omitempty"`
+	TimedPrice     bool             `json:"-"`
+}
+
+// IsComposite reports whether the product is made of other products. The
+// stock of a composite product is derived from its components.
+func (p *Product) IsComposite() bool {
+	return p.Type == ProductTypeBundle || p.Type == ProductTypeRecipe
+}
+
+type ProductComponent struct {
+	ProductID   int    `json:"-"`
+	ComponentID int    `json:"component_id"`
+	Name        string `json:"name"`
+	Quantity    int    `json:"quantity"`
+	Stock       int    `json:"stock"`
+}
+
+type ProductPatch struct {
+	Name  *string
+	Type  *string
+	Price *int
+	Cost  *int
+	Stock *int
+
+	BaseUnit *string
+}
+
+type ProductFilter struct {
+	IDs        []int
+	Search     string
+	CategoryID int
+
+	IncludeVariants bool
+}
+
+const (
+	PriceChangePercentage = "percentage"
+	PriceChangeFixed      = "fixed"
+
+	StockChangeSet    = "set"
+	StockChangeAdjust = "adjust"
+)
+
+type BulkProductUpdate struct {
+	Filter          ProductFilter
+	PriceChangeType string
+	PriceValue      float64
+	StockChangeType string
+	StockValue      int
+	Preview         bool
+}
+
+type BulkProductUpdateItem struct {
+	ProductID int    `json:"product_id"`
+	Name      string `json:"name"`
+	OldPrice  int    `json:"old_price"`
+	NewPrice  int    `json:"new_price"`
+	OldStock  int    `json:"old_stock"`
+	NewStock  int    `json:"new_stock"`
+}
+
+type BulkProductUpdateResult struct {
+	Preview  bool                    `json:"preview"`
+	Affected int                     `json:"affected"`
+	Items    []BulkProductUpdateItem `json:"items"`
 }
@@ -0,0 +1,61 @@
+package domains
+
+import "time"
+
+const (
+	PromotionTypePercentage = "percentage"
+	PromotionTypeFixed      = "fixed"
+	PromotionTypeBuyXGetY   = "buy_x_get_y"
+	PromotionTypeBundle     = "bundle_price"
+)
+
+const (
+	PromotionScopeLine = "line"
+	PromotionScopeCart = "cart"
+)
+
+// Promotion is a discount rule evaluated automatically at checkout.
+//
+// Percentage and fixed promotions apply either to each targeted line or to
+// the cart as a whole, depending on Scope. Buy-X-get-Y gives GetQuantity free
+// units for every BuyQuantity paid units of the same line. Bundle price sells
+// every BundleQuantity targeted units together for BundlePrice. MinSpend, when
+// set, is the cart subtotal required before the promotion applies.
+//
+// A promotion without products or categories targets every product.
+// Promotions run in descending Priority; a promotion that is not Stackable
+// only applies where no other discount has been given, and stops later
+// promotions from applying there.
+type Promotion struct {
+	ID             int        `json:"id"`
+	Name           string     `json:"name"`
+	Type           string     `json:"type"`
+	Scope          string     `json:"scope"`
+	Value          int        `json:"value"`
+	BuyQuantity    int        `json:"buy_quantity,omitempty"`
+	GetQuantity    int        `json:"get_quantity,omitempty"`
+	BundleQuantity int        `json:"bundle_quantity,omitempty"`
+	BundlePrice    int        `json:"bundle_price,omitempty"`
+	MinSpend       int        `json:"min_spend"`
+	StartsAt       *time.Time `json:"starts_at,omitempty"`
+	EndsAt         *time.Time `json:"ends_at,omitempty"`
+	Priority       int        `json:"priority"`
+	Stackable      bool       `json:"stackable"`
+	Active         bool       `json:"active"`
+	ProductIDs     []int      `json:"product_ids"`
+	CategoryIDs    []int      `json:"category_ids"`
+}
+
+// TransactionDiscount records a discount given on a transaction by a
+// promotion or a voucher. A nil TransactionItemID means the discount applies
+// to the whole cart.
+type TransactionDiscount struct {
+	ID                int    `json:"id"`
+	TransactionItemID *int   `json:"transaction_item_id,omitempty"`
+	PromotionID       *int   `json:"promotion_id,omitempty"`
+	VoucherID         *int   `json:"voucher_id,omitempty"`
+	Name              string `json:"name"`
+	Amount            int    `json:"amount"`
+
+	ItemIndex int `json:"-"`
+}
@@ -0,0 +1,34 @@
+package domains
+
+import "time"
+
+type Purchase struct {
+	ID        int            `json:"id"`
+	Supplier  string         `json:"supplier"`
+	TotalCost int            `json:"total_cost"`
+	CreatedAt time.Time      `json:"created_at"`
+	Items     []PurchaseItem `json:"items,omitempty"`
+
+	StockMovements []StockMovement `json:"-"`
+}
+
+type PurchaseItem struct {
+	ID           int    `json:"id"`
+	PurchaseID   int    `json:"purchase_id"`
+	ProductID    int    `json:"product_id"`
+	VariantID    *int   `json:"variant_id,omitempty"`
+	UnitID       *int   `json:"unit_id,omitempty"`
+	UnitName     string `json:"unit_name"`
+	Quantity     int    `json:"quantity"`
+	BaseQuantity int    `json:"base_quantity"`
+	UnitCost     int    `json:"unit_cost"`
+	Subtotal     int    `json:"subtotal"`
+}
+
+type PurchaseLine struct {
+	ProductID int
+	VariantID *int
+	UnitID    *int
+	Quantity  int
+	UnitCost  int
+}
@@ -0,0 +1,84 @@
+package domains
+
+import "time"
+
+const (
+	ReceivableEntryCharge    = "charge"
+	ReceivableEntryRepayment = "repayment"
+	ReceivableEntryReverse   = "reverse"
+)
+
+// ReceivableEntry is a signed entry in a customer's store credit ledger.
+// Charges are positive; repayments and refund reversals are negative.
+type ReceivableEntry struct {
+	ID            int       `json:"id"`
+	CustomerID    int       `json:"customer_id"`
+	TransactionID int       `json:"transaction_id"`
+	Type          string    `json:"type"`
+	Amount        int       `json:"amount"`
+	Note          string    `json:"note"`
+	CreatedBy     string    `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// OpenReceivable is a transaction bought on account that is not fully
+// repaid. Age is counted from the transaction date.
+type OpenReceivable struct {
+	TransactionID int       `json:"transaction_id"`
+	CustomerID    int       `json:"customer_id"`
+	CustomerName  string    `json:"customer_name"`
+	Amount        int       `json:"amount"`
+	Outstanding   int       `json:"outstanding"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type ReceivableAccount struct {
+	CustomerID      int               `json:"customer_id"`
+	CreditLimit     int               `json:"credit_limit"`
+	Balance         int               `json:"balance"`
+	AvailableCredit int               `json:"available_credit"`
+	Open            []OpenReceivable  `json:"open"`
+	Entries         []ReceivableEntry `json:"entries"`
+}
+
+// Repayment is money received from a customer towards what they owe.
+// Without a TransactionID it settles their oldest transactions first.
+type Repayment struct {
+	CustomerID    int
+	TransactionID *int
+	Amount        int
+	Note          string
+}
+
+// AgingRow splits an outstanding balance by how many days old the
+// transactions behind it are.
+type AgingRow struct {
+	CustomerID   int    `json:"customer_id,omitempty"`
+	CustomerName string `json:"customer_name,omitempty"`
+	Days0To30    int    `json:"days_0_30"`
+	Days31To60   int    `json:"days_31_60"`
+	Days61To90   int    `json:"days_61_90"`
+	DaysOver90   int    `json:"days_over_90"`
+	Total        int    `json:"total"`
+}
+
+// Add puts an outstanding amount that is ageDays old into its bucket.
+func (r *AgingRow) Add(ageDays int, amount int) {
+	switch {
+	case ageDays <= 30:
+		r.Days0To30 += amount
+	case ageDays <= 60:
+		r.Days31To60 += amount
+	case ageDays <= 90:
+		r.Days61To90 += amount
+	default:
+		r.DaysOver90 += amount
+	}
+	r.Total += amount
+}
+
+type AgingReport struct {
+	AsOf      time.Time  `json:"as_of"`
+	Customers []AgingRow `json:"customers"`
+	Totals    AgingRow   `json:"totals"`
+}
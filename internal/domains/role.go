@@ -0,0 +1,88 @@
+package domains
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	PermissionProductRead       = "product:read"
+	PermissionProductWrite      = "product:write"
+	PermissionProductDelete     = "product:delete"
+	PermissionCategoryRead      = "category:read"
+	PermissionCategoryWrite     = "category:write"
+	PermissionCategoryDelete    = "category:delete"
+	PermissionPriceWrite        = "price:write"
+	PermissionPriceOverride     = "price:override"
+	PermissionDiscountApprove   = "discount:approve"
+	PermissionTransactionRead   = "transaction:read"
+	PermissionTransactionCreate = "transaction:create"
+	PermissionTransactionVoid   = "transaction:void"
+	PermissionCustomerRead      = "customer:read"
+	PermissionCustomerWrite     = "customer:write"
+	PermissionCustomerDelete    = "customer:delete"
+	PermissionCreditManage      = "credit:manage"
+	PermissionPurchaseRead      = "purchase:read"
+	PermissionPurchaseWrite     = "purchase:write"
+	PermissionPromotionRead     = "promotion:read"
+	PermissionPromotionWrite    = "promotion:write"
+	PermissionSettingsRead      = "settings:read"
+	PermissionSettingsWrite     = "settings:write"
+	PermissionReportRead        = "report:read"
+	PermissionTerminalManage    = "terminal:manage"
+	PermissionUserManage        = "user:manage"
+	PermissionRoleManage        = "role:manage"
+	PermissionAuditRead         = "audit:read"
+	PermissionAPIKeyManage      = "apikey:manage"
+	PermissionTenantManage      = "tenant:manage"
+)
+
+// Permissions lists every permission a role can be granted.
+var Permissions = []string{
+	PermissionProductRead,
+	PermissionProductWrite,
+	PermissionProductDelete,
+	PermissionCategoryRead,
+	PermissionCategoryWrite,
+	PermissionCategoryDelete,
+	PermissionPriceWrite,
+	PermissionPriceOverride,
+	PermissionDiscountApprove,
+	PermissionTransactionRead,
+	PermissionTransactionCreate,
+	PermissionTransactionVoid,
+	PermissionCustomerRead,
+	PermissionCustomerWrite,
+	PermissionCustomerDelete,
+	PermissionCreditManage,
+	PermissionPurchaseRead,
+	PermissionPurchaseWrite,
+	PermissionPromotionRead,
+	PermissionPromotionWrite,
+	PermissionSettingsRead,
+	PermissionSettingsWrite,
+	PermissionReportRead,
+	PermissionTerminalManage,
+	PermissionUserManage,
+	PermissionRoleManage,
+	PermissionAuditRead,
+	PermissionAPIKeyManage,
+	PermissionTenantManage,
+}
+
+func IsPermission(permission string) bool {
+	return slices.Contains(Permissions, permission)
+}
+
+// RoleOwner is the seeded role with every permission, given to the first
+// admin user.
+const RoleOwner = "owner"
+
+type Role struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Permissions []string  `json:"permissions"`
+	Users       int       `json:"users"`
+	CreatedAt   time.Time `json:"created_at"`
+}
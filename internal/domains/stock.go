@@ -0,0 +1,23 @@
+package domains
+
+import "time"
+
+const (
+	StockReasonSale       = "sale"
+	StockReasonPurchase   = "purchase"
+	StockReasonAdjustment = "adjustment"
+	StockReasonRefund     = "refund"
+)
+
+// StockMovement is a signed entry in the stock ledger: negative quantities
+// take stock out, positive ones put it back.
+type StockMovement struct {
+	ID            int       `json:"id"`
+	ProductID     int       `json:"product_id"`
+	VariantID     *int      `json:"variant_id,omitempty"`
+	Quantity      int       `json:"quantity"`
+	Reason        string    `json:"reason"`
+	ReferenceType string    `json:"reference_type,omitempty"`
+	ReferenceID   *int      `json:"reference_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
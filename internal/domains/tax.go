@@ -0,0 +1,31 @@
+package domains
+
+const (
+	ServiceChargeBeforeTax = "before_tax"
+	ServiceChargeAfterTax  = "after_tax"
+)
+
+// TaxRate is a tax such as PPN 11%. Rate is a percentage.
+type TaxRate struct {
+	ID   int     `json:"id"`
+	Name string  `json:"name"`
+	Rate float64 `json:"rate"`
+}
+
+// StoreSettings configures how tax and service charge are calculated.
+//
+// With TaxInclusive, selling prices already contain the tax and it is only
+// broken out on the receipt. ServiceChargeOrder decides whether the service
+// charge is added before tax (and is taxed) or after tax (and is charged on
+// the taxed amount).
+type StoreSettings struct {
+	DefaultTaxRateID   *int    `json:"default_tax_rate_id"`
+	TaxInclusive       bool    `json:"tax_inclusive"`
+	ServiceChargeRate  float64 `json:"service_charge_rate"`
+	ServiceChargeOrder string  `json:"service_charge_order"`
+}
+
+type ProductTax struct {
+	TaxRateID *int `json:"tax_rate_id"`
+	TaxExempt bool `json:"tax_exempt"`
+}
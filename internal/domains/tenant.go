@@ -0,0 +1,15 @@
+package domains
+
+import "time"
+
+// DefaultTenantID is the store that existed before tenants. Its users run
+// the deployment and are the only ones who can create tenants.
+const DefaultTenantID = 1
+
+// Tenant is one store sharing the deployment. Everything it sells, and
+// everyone who works there, belongs to exactly one tenant.
+type Tenant struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
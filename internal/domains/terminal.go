@@ -0,0 +1,15 @@
+package domains
+
+import "time"
+
+// Terminal is a registered counter device that cashiers can switch on
+// with their PIN. Key is only returned when the terminal is registered.
+type Terminal struct {
+	ID         int        `json:"id"`
+	TenantID   int        `json:"-"`
+	Name       string     `json:"name"`
+	Active     bool       `json:"active"`
+	Key        string     `json:"key,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,88 @@
+package domains
+
+import "time"
+
+const (
+	TransactionStatusCompleted = "completed"
+	TransactionStatusRefunded  = "refunded"
+)
+
+type Transaction struct {
+	ID              int                   `json:"id"`
+	CustomerID      *int                  `json:"customer_id,omitempty"`
+	CashierID       *int                  `json:"cashier_id,omitempty"`
+	TerminalID      *int                  `json:"terminal_id,omitempty"`
+	Status          string                `json:"status"`
+	Subtotal        int                   `json:"subtotal"`
+	DiscountAmount  int                   `json:"discount_amount"`
+	ServiceCharge   int                   `json:"service_charge"`
+	TaxAmount       int                   `json:"tax_amount"`
+	TaxInclusive    bool                  `json:"tax_inclusive"`
+	GiftCardAmount  int                   `json:"gift_card_amount"`
+	TotalAmount     int                   `json:"total_amount"`
+	ChangeAmount    int                   `json:"change_amount"`
+	OnAccountAmount int                   `json:"on_account_amount"`
+	PointsEarned    int                   `json:"points_earned"`
+	PointsRedeemed  int                   `json:"points_redeemed"`
+	CreatedAt       time.Time             `json:"created_at"`
+	RefundedAt      *time.Time            `json:"refunded_at,omitempty"`
+	Items           []TransactionItem     `json:"items,omitempty"`
+	Discounts       []TransactionDiscount `json:"discounts,omitempty"`
+	Payments        []TransactionPayment  `json:"payments,omitempty"`
+	GiftCards       []GiftCardSale        `json:"gift_cards,omitempty"`
+	Approvals       []Approval            `json:"approvals,omitempty"`
+
+	StockMovements []StockMovement `json:"-"`
+	// PointsExpireAt is when the points earned on this transaction expire.
+	PointsExpireAt *time.Time `json:"-"`
+}
+
+type TransactionFilter struct {
+	CustomerID *int
+	CashierID  *int
+}
+
+type TransactionItem struct {
+	ID            int    `json:"id"`
+	TransactionID int    `json:"transaction_id"`
+	ProductID     int    `json:"product_id"`
+	VariantID     *int   `json:"variant_id,omitempty"`
+	Name          string `json:"name"`
+	UnitID        *int   `json:"unit_id,omitempty"`
+	UnitName      string `json:"unit_name"`
+	Quantity      int    `json:"quantity"`
+	BaseQuantity  int    `json:"base_quantity"`
+	UnitPrice     int    `json:"unit_price"`
+	PriceListID   *int   `json:"price_list_id,omitempty"`
+	Subtotal      int    `json:"subtotal"`
+
+	// OriginalUnitPrice is the unit price before the cashier overrode it.
+	OriginalUnitPrice *int `json:"original_unit_price,omitempty"`
+
+	DiscountAmount int     `json:"discount_amount"`
+	TaxRate        float64 `json:"tax_rate"`
+	ServiceCharge  int     `json:"service_charge"`
+	TaxAmount      int     `json:"tax_amount"`
+
+	Modifiers []TransactionItemModifier `json:"modifiers,omitempty"`
+}
+
+type Checkout struct {
+	Items       []CheckoutItem
+	VoucherCode string
+	CustomerID  *int
+	Payments    []CheckoutPayment
+	GiftCards   []CheckoutGiftCard
+	// ManualDiscount is a cart discount in Rupiah given by the cashier.
+	ManualDiscount int
+}
+
+type CheckoutItem struct {
+	ProductID   int
+	VariantID   *int
+	UnitID      *int
+	Quantity    int
+	ModifierIDs []int
+	// PriceOverride replaces the unit price, modifiers included.
+	PriceOverride *int
+}
@@ -0,0 +1,23 @@
+package domains
+
+// ProductUnit is an alternative unit such as "pack" or "carton". Stock is
+// always kept in the product's base unit; ConversionFactor says how many
+// base units one of this unit holds.
+type ProductUnit struct {
+	ID               int    `json:"id"`
+	ProductID        int    `json:"product_id"`
+	Name             string `json:"name"`
+	ConversionFactor int    `json:"conversion_factor"`
+	Price            int    `json:"price"`
+	Barcode          string `json:"barcode,omitempty"`
+}
+
+// PriceFor returns the regular selling price of one of this unit. A unit
+// without its own price is sold at the base price times its conversion
+// factor.
+func (u *ProductUnit) PriceFor(basePrice int) int {
+	if u.Price > 0 {
+		return u.Price
+	}
+	return basePrice * u.ConversionFactor
+}
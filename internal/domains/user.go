@@ -0,0 +1,40 @@
+package domains
+
+import "time"
+
+type User struct {
+	ID             int        `json:"id"`
+	TenantID       int        `json:"tenant_id"`
+	Username       string     `json:"username"`
+	Name           string     `json:"name"`
+	Active         bool       `json:"active"`
+	Roles          []string   `json:"roles"`
+	Permissions    []string   `json:"permissions,omitempty"`
+	PINSet         bool       `json:"pin_set"`
+	PINLockedUntil *time.Time `json:"pin_locked_until,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	PasswordHash   string     `json:"-"`
+	PINHash        string     `json:"-"`
+}
+
+// Session is one login of a user, kept alive by rotating refresh tokens.
+type Session struct {
+	ID         string     `json:"id"`
+	TenantID   int        `json:"tenant_id"`
+	UserID     int        `json:"user_id"`
+	TerminalID *int       `json:"terminal_id,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+
+	// TerminalKeyHash is the key hash of the terminal of a PIN session,
+	// which every request of the session has to present.
+	TerminalKeyHash string `json:"-"`
+}
+
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	User         *User  `json:"user,omitempty"`
+}
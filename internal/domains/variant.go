@@ -0,0 +1,19 @@
+package domains
+
+type VariantOption struct {
+	ID        int      `json:"id"`
+	ProductID int      `json:"product_id"`
+	Name      string   `json:"name"`
+	Values    []string `json:"values"`
+}
+
+type ProductVariant struct {
+	ID        int               `json:"id"`
+	ProductID int               `json:"product_id"`
+	SKU       string            `json:"sku"`
+	Barcode   string            `json:"barcode,omitempty"`
+	Name      string            `json:"name"`
+	Options   map[string]string `json:"options"`
+	Price     int               `json:"price"`
+	Stock     int               `json:"stock"`
+}
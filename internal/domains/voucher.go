@@ -0,0 +1,40 @@
+package domains
+
+import "time"
+
+// Voucher is a discount code entered at checkout. Percentage vouchers may be
+// capped by MaxDiscount. A UsageLimit or PerCustomerLimit of 0 means
+// unlimited.
+type Voucher struct {
+	ID               int        `json:"id"`
+	Code             string     `json:"code"`
+	Name             string     `json:"name"`
+	DiscountType     string     `json:"discount_type"`
+	Value            int        `json:"value"`
+	MaxDiscount      int        `json:"max_discount"`
+	MinPurchase      int        `json:"min_purchase"`
+	StartsAt         *time.Time `json:"starts_at,omitempty"`
+	EndsAt           *time.Time `json:"ends_at,omitempty"`
+	UsageLimit       int        `json:"usage_limit"`
+	PerCustomerLimit int        `json:"per_customer_limit"`
+	UsedCount        int        `json:"used_count"`
+	Active           bool       `json:"active"`
+}
+
+// Discount returns the discount the voucher gives on the given amount.
+func (v *Voucher) Discount(amount int) int {
+	if v.DiscountType == PromotionTypePercentage {
+		discount := amount * v.Value / 100
+		if v.MaxDiscount > 0 {
+			discount = min(discount, v.MaxDiscount)
+		}
+		return discount
+	}
+	return min(v.Value, amount)
+}
+
+type VoucherValidation struct {
+	Voucher  *Voucher `json:"voucher"`
+	Amount   int      `json:"amount"`
+	Discount int      `json:"discount"`
+}
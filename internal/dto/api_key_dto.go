@@ -0,0 +1,20 @@
+package dto
+
+import (
+	domain "kasir-api/internal/domains"
+	"time"
+)
+
+type APIKeyRequest struct {
+	Name        string     `json:"name" validate:"required,min=1,max=100"`
+	Permissions []string   `json:"permissions" validate:"required,min=1,dive,required,max=50"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+func APIKeyReqToDomain(req *APIKeyRequest) *domain.APIKey {
+	return &domain.APIKey{
+		Name:        req.Name,
+		Permissions: req.Permissions,
+		ExpiresAt:   req.ExpiresAt,
+	}
+}
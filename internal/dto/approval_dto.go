@@ -0,0 +1,13 @@
+package dto
+
+import domain "kasir-api/internal/domains"
+
+type ApprovalSettingsRequest struct {
+	DiscountThresholdPercent float64 `json:"discount_threshold_percent" validate:"min=0,max=100"`
+}
+
+func ApprovalSettingsReqToDomain(req *ApprovalSettingsRequest) *domain.ApprovalSettings {
+	return &domain.ApprovalSettings{
+		DiscountThresholdPercent: req.DiscountThresholdPercent,
+	}
+}
@@ -0,0 +1,26 @@
+package dto
+
+type LoginRequest struct {
+	Username string `json:"username" validate:"required,max=100"`
+	Password string `json:"password" validate:"required,max=72"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required,max=128"`
+}
+
+type PINLoginRequest struct {
+	Username string `json:"username" validate:"required,max=100"`
+	PIN      string `json:"pin" validate:"required,number,min=4,max=8"`
+}
+
+type PINRequest struct {
+	PIN string `json:"pin" validate:"required,number,min=4,max=8"`
+}
+
+// ApprovalGrantRequest lists the actions a supervisor approves, on an
+// existing transaction or, without transaction_id, on a new sale.
+type ApprovalGrantRequest struct {
+	Actions       []string `json:"actions" validate:"required,min=1,dive,oneof=void discount price_override"`
+	TransactionID *int     `json:"transaction_id" validate:"omitempty,gt=0"`
+}
@@ -13,3 +13,20 @@ func CategoryReqToDomain(req *CategoryRequest) *domain.Category {
 		Description: req.Description,
 	}
 }
+
+type CategoryPatchRequest struct {
+	Name        *string `json:"name" validate:"omitempty,min=1"`
+	Description *string `json:"description" validate:"omitempty,max=255"`
+}
+
+func CategoryPatchReqToDomain(req *CategoryPatchRequest, nulls map[string]bool) *domain.CategoryPatch {
+	patch := &domain.CategoryPatch{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if nulls["description"] {
+		empty := ""
+		patch.Description = &empty
+	}
+	return patch
+}
@@ -0,0 +1,21 @@
+package dto
+
+import domain "kasir-api/internal/domains"
+
+type CustomerRequest struct {
+	Name    string `json:"name" validate:"required,min=1,max=255"`
+	Phone   string `json:"phone" validate:"max=20"`
+	Email   string `json:"email" validate:"omitempty,email,max=255"`
+	Address string `json:"address" validate:"max=1000"`
+	Notes   string `json:"notes" validate:"max=1000"`
+}
+
+func CustomerReqToDomain(req *CustomerRequest) *domain.Customer {
+	return &domain.Customer{
+		Name:    req.Name,
+		Phone:   req.Phone,
+		Email:   req.Email,
+		Address: req.Address,
+		Notes:   req.Notes,
+	}
+}
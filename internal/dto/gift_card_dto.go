@@ -0,0 +1,5 @@
+package dto
+
+type GiftCardBalanceRequest struct {
+	Code string `json:"code" validate:"required,max=32"`
+}
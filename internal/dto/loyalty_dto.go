@@ -0,0 +1,29 @@
+package dto
+
+import domain "kasir-api/internal/domains"
+
+type LoyaltySettingsRequest struct {
+	Enabled         bool `json:"enabled"`
+	SpendPerPoint   int  `json:"spend_per_point" validate:"required,gt=0"`
+	PointValue      int  `json:"point_value" validate:"required,gt=0"`
+	ExpiryDays      int  `json:"expiry_days" validate:"min=0"`
+	MinRedeemPoints int  `json:"min_redeem_points" validate:"min=0"`
+}
+
+func LoyaltySettingsReqToDomain(req *LoyaltySettingsRequest) *domain.LoyaltySettings {
+	return &domain.LoyaltySettings{
+		Enabled:         req.Enabled,
+		SpendPerPoint:   req.SpendPerPoint,
+		PointValue:      req.PointValue,
+		ExpiryDays:      req.ExpiryDays,
+		MinRedeemPoints: req.MinRedeemPoints,
+	}
+}
+
+type ProductLoyaltyRequest struct {
+	BonusPoints *int `json:"bonus_points" validate:"omitempty,min=0"`
+}
+
+type CategoryLoyaltyRequest struct {
+	BonusPoints int `json:"bonus_points" validate:"min=0"`
+}
@@ -0,0 +1,39 @@
+package dto
+
+import domain "kasir-api/internal/domains"
+
+type ModifierRequest struct {
+	Name                string `json:"name" validate:"required,min=1,max=100"`
+	PriceDelta          int    `json:"price_delta"`
+	IngredientProductID *int   `json:"ingredient_product_id" validate:"omitempty,gt=0"`
+	IngredientQuantity  int    `json:"ingredient_quantity" validate:"min=0"`
+}
+
+type ModifierGroupRequest struct {
+	Name      string            `json:"name" validate:"required,min=1,max=100"`
+	MinSelect int               `json:"min_select" validate:"min=0"`
+	MaxSelect int               `json:"max_select" validate:"min=0"`
+	Modifiers []ModifierRequest `json:"modifiers" validate:"required,min=1,dive"`
+}
+
+func ModifierGroupReqToDomain(req *ModifierGroupRequest) *domain.ModifierGroup {
+	modifiers := make([]domain.Modifier, len(req.Modifiers))
+	for i, modifier := range req.Modifiers {
+		modifiers[i] = domain.Modifier{
+			Name:                modifier.Name,
+			PriceDelta:          modifier.PriceDelta,
+			IngredientProductID: modifier.IngredientProductID,
+			IngredientQuantity:  modifier.IngredientQuantity,
+		}
+	}
+	return &domain.ModifierGroup{
+		Name:      req.Name,
+		MinSelect: req.MinSelect,
+		MaxSelect: req.MaxSelect,
+		Modifiers: modifiers,
+	}
+}
+
+type ModifierGroupLinkRequest struct {
+	GroupIDs []int `json:"group_ids" validate:"dive,gt=0"`
+}
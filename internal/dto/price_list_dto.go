@@ -0,0 +1,43 @@
+package dto
+
+import domain "kasir-api/internal/domains"
+
+type PriceListItemRequest struct {
+	ProductID   int  `json:"product_id" validate:"required,gt=0"`
+	VariantID   *int `json:"variant_id" validate:"omitempty,gt=0"`
+	MinQuantity int  `json:"min_quantity" validate:"omitempty,gt=0"`
+	Price       int  `json:"price" validate:"min=0"`
+}
+
+type PriceListRequest struct {
+	Name        string                 `json:"name" validate:"required,min=1,max=100"`
+	Description string                 `json:"description" validate:"max=1000"`
+	IsDefault   bool                   `json:"is_default"`
+	Items       []PriceListItemRequest `json:"items" validate:"dive"`
+}
+
+func PriceListReqToDomain(req *PriceListRequest) *domain.PriceList {
+	items := make([]domain.PriceListItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		minQuantity := item.MinQuantity
+		if minQuantity == 0 {
+			minQuantity = 1
+		}
+		items = append(items, domain.PriceListItem{
+			ProductID:   item.ProductID,
+			VariantID:   item.VariantID,
+			MinQuantity: minQuantity,
+			Price:       item.Price,
+		})
+	}
+	return &domain.PriceList{
+		Name:        req.Name,
+		Description: req.Description,
+		IsDefault:   req.IsDefault,
+		Items:       items,
+	}
+}
+
+type CustomerPriceListRequest struct {
+	PriceListID *int `json:"price_list_id" validate:"omitempty,gt=0"`
+}
@@ -0,0 +1,53 @@
+package dto
+
+import (
+	domain "kasir-api/internal/domains"
+	"time"
+)
+
+type ScheduledPriceRequest struct {
+	Price    int       `json:"price" validate:"min=0"`
+	StartsAt time.Time `json:"starts_at" validate:"required"`
+}
+
+func ScheduledPriceReqToDomain(productID int, req *ScheduledPriceRequest) *domain.ScheduledPrice {
+	return &domain.ScheduledPrice{
+		ProductID: productID,
+		Price:     req.Price,
+		StartsAt:  req.StartsAt,
+	}
+}
+
+type PriceWindowRequest struct {
+	Name           string `json:"name" validate:"required,min=1,max=100"`
+	ProductID      *int   `json:"product_id" validate:"omitempty,gt=0"`
+	CategoryID     *int   `json:"category_id" validate:"omitempty,gt=0"`
+	DaysOfWeek     []int  `json:"days_of_week" validate:"dive,min=0,max=6"`
+	StartTime      string `json:"start_time" validate:"required,datetime=15:04"`
+	EndTime        string `json:"end_time" validate:"required,datetime=15:04"`
+	AdjustmentType string `json:"adjustment_type" validate:"required,oneof=percentage price"`
+	Value          int    `json:"value" validate:"min=0"`
+	Active         *bool  `json:"active"`
+}
+
+func PriceWindowReqToDomain(req *PriceWindowRequest) *domain.PriceWindow {
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+	days := req.DaysOfWeek
+	if days == nil {
+		days = []int{}
+	}
+	return &domain.PriceWindow{
+		Name:           req.Name,
+		ProductID:      req.ProductID,
+		CategoryID:     req.CategoryID,
+		DaysOfWeek:     days,
+		StartTime:      req.StartTime,
+		EndTime:        req.EndTime,
+		AdjustmentType: req.AdjustmentType,
+		Value:          req.Value,
+		Active:         active,
+	}
+}
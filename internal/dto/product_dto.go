@@ -4,14 +4,104 @@ import domain "kasir-api/internal/domains"
 
 type ProductRequest struct {
 	Name  string `json:"name" validate:"required,min=1"`
-	Price int    `json:"price" validate:"required,number"`
-	Stock int    `json:"stock" validate:"required,number"`
+	Type  string `json:"type" validate:"omitempty,oneof=standard bundle recipe"`
+	Price int    `json:"price" validate:"required,number,min=0"`
+	Cost  int    `json:"cost" validate:"number,min=0"`
+	Stock int    `json:"stock" validate:"number,min=0"`
+
+	BaseUnit string `json:"base_unit" validate:"omitempty,max=20"`
 }
 
+// ProductReqToDomain leaves Type and BaseUnit empty when the request omits
+// them: a new product is a standard product counted in the default unit,
+// an updated one keeps what it had.
 func ProductReqToDomain(req *ProductRequest) *domain.Product {
 	return &domain.Product{
-		Name:  req.Name,
-		Price: req.Price,
-		Stock: req.Stock,
+		Name:     req.Name,
+		Type:     req.Type,
+		Price:    req.Price,
+		Cost:     req.Cost,
+		Stock:    req.Stock,
+		BaseUnit: req.BaseUnit,
+	}
+}
+
+type ProductPatchRequest struct {
+	Name  *string `json:"name" validate:"omitempty,min=1"`
+	Type  *string `json:"type" validate:"omitempty,oneof=standard bundle recipe"`
+	Price *int    `json:"price" validate:"omitempty,min=0"`
+	Cost  *int    `json:"cost" validate:"omitempty,min=0"`
+	Stock *int    `json:"stock" validate:"omitempty,min=0"`
+
+	BaseUnit *string `json:"base_unit" validate:"omitempty,min=1,max=20"`
+}
+
+func ProductPatchReqToDomain(req *ProductPatchRequest) *domain.ProductPatch {
+	return &domain.ProductPatch{
+		Name:     req.Name,
+		Type:     req.Type,
+		Price:    req.Price,
+		Cost:     req.Cost,
+		Stock:    req.Stock,
+		BaseUnit: req.BaseUnit,
+	}
+}
+
+type ProductComponentRequest struct {
+	ComponentID int `json:"component_id" validate:"required,gt=0"`
+	Quantity    int `json:"quantity" validate:"required,gt=0"`
+}
+
+type ProductComponentsRequest struct {
+	Components []ProductComponentRequest `json:"components" validate:"dive"`
+}
+
+func ProductComponentsReqToDomain(req *ProductComponentsRequest) []domain.ProductComponent {
+	components := make([]domain.ProductComponent, len(req.Components))
+	for i, component := range req.Components {
+		components[i] = domain.ProductComponent{
+			ComponentID: component.ComponentID,
+			Quantity:    component.Quantity,
+		}
+	}
+	return components
+}
+
+type BulkPriceChange struct {
+	Type  string  `json:"type" validate:"required,oneof=percentage fixed"`
+	Value float64 `json:"value"`
+}
+
+type BulkStockChange struct {
+	Type  string `json:"type" validate:"required,oneof=set adjust"`
+	Value int    `json:"value"`
+}
+
+type BulkProductUpdateRequest struct {
+	ProductIDs []int            `json:"product_ids" validate:"omitempty,dive,gt=0"`
+	CategoryID int              `json:"category_id" validate:"min=0"`
+	Search     string           `json:"search"`
+	Price      *BulkPriceChange `json:"price"`
+	Stock      *BulkStockChange `json:"stock"`
+	Preview    bool             `json:"preview"`
+}
+
+func BulkProductUpdateReqToDomain(req *BulkProductUpdateRequest) domain.BulkProductUpdate {
+	update := domain.BulkProductUpdate{
+		Filter: domain.ProductFilter{
+			IDs:        req.ProductIDs,
+			Search:     req.Search,
+			CategoryID: req.CategoryID,
+		},
+		Preview: req.Preview,
+	}
+	if req.Price != nil {
+		update.PriceChangeType = req.Price.Type
+		update.PriceValue = req.Price.Value
+	}
+	if req.Stock != nil {
+		update.StockChangeType = req.Stock.Type
+		update.StockValue = req.Stock.Value
 	}
+	return update
 }
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	domain "kasir-api/internal/domains"
+	"time"
+)
+
+type PromotionRequest struct {
+	Name           string     `json:"name" validate:"required,min=1,max=255"`
+	Type           string     `json:"type" validate:"required,oneof=percentage fixed buy_x_get_y bundle_price"`
+	Scope          string     `json:"scope" validate:"omitempty,oneof=line cart"`
+	Value          int        `json:"value" validate:"min=0"`
+	BuyQuantity    int        `json:"buy_quantity" validate:"min=0"`
+	GetQuantity    int        `json:"get_quantity" validate:"min=0"`
+	BundleQuantity int        `json:"bundle_quantity" validate:"min=0"`
+	BundlePrice    int        `json:"bundle_price" validate:"min=0"`
+	MinSpend       int        `json:"min_spend" validate:"min=0"`
+	StartsAt       *time.Time `json:"starts_at"`
+	EndsAt         *time.Time `json:"ends_at"`
+	Priority       int        `json:"priority"`
+	Stackable      bool       `json:"stackable"`
+	Active         *bool      `json:"active"`
+	ProductIDs     []int      `json:"product_ids" validate:"omitempty,dive,gt=0"`
+	CategoryIDs    []int      `json:"category_ids" validate:"omitempty,dive,gt=0"`
+}
+
+func PromotionReqToDomain(req *PromotionRequest) *domain.Promotion {
+	scope := req.Scope
+	if scope == "" {
+		scope = domain.PromotionScopeLine
+	}
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+	productIDs := req.ProductIDs
+	if productIDs == nil {
+		productIDs = []int{}
+	}
+	categoryIDs := req.CategoryIDs
+	if categoryIDs == nil {
+		categoryIDs = []int{}
+	}
+	return &domain.Promotion{
+		Name:           req.Name,
+		Type:           req.Type,
+		Scope:          scope,
+		Value:          req.Value,
+		BuyQuantity:    req.BuyQuantity,
+		GetQuantity:    req.GetQuantity,
+		BundleQuantity: req.BundleQuantity,
+		BundlePrice:    req.BundlePrice,
+		MinSpend:       req.MinSpend,
+		StartsAt:       req.StartsAt,
+		EndsAt:         req.EndsAt,
+		Priority:       req.Priority,
+		Stackable:      req.Stackable,
+		Active:         active,
+		ProductIDs:     productIDs,
+		CategoryIDs:    categoryIDs,
+	}
+}
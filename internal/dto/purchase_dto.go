@@ -0,0 +1,30 @@
+package dto
+
+import domain "kasir-api/internal/domains"
+
+type PurchaseItemRequest struct {
+	ProductID int  `json:"product_id" validate:"required,gt=0"`
+	VariantID *int `json:"variant_id" validate:"omitempty,gt=0"`
+	UnitID    *int `json:"unit_id" validate:"omitempty,gt=0"`
+	Quantity  int  `json:"quantity" validate:"required,gt=0"`
+	UnitCost  int  `json:"unit_cost" validate:"number,min=0"`
+}
+
+type PurchaseRequest struct {
+	Supplier string                `json:"supplier" validate:"max=255"`
+	Items    []PurchaseItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+func PurchaseReqToDomain(req *PurchaseRequest) []domain.PurchaseLine {
+	lines := make([]domain.PurchaseLine, len(req.Items))
+	for i, item := range req.Items {
+		lines[i] = domain.PurchaseLine{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			UnitID:    item.UnitID,
+			Quantity:  item.Quantity,
+			UnitCost:  item.UnitCost,
+		}
+	}
+	return lines
+}
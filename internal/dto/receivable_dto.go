@@ -0,0 +1,22 @@
+package dto
+
+import domain "kasir-api/internal/domains"
+
+type CreditLimitRequest struct {
+	CreditLimit int `json:"credit_limit" validate:"min=0"`
+}
+
+type RepaymentRequest struct {
+	TransactionID *int   `json:"transaction_id" validate:"omitempty,gt=0"`
+	Amount        int    `json:"amount" validate:"required,gt=0"`
+	Note          string `json:"note" validate:"max=1000"`
+}
+
+func RepaymentReqToDomain(customerID int, req *RepaymentRequest) *domain.Repayment {
+	return &domain.Repayment{
+		CustomerID:    customerID,
+		TransactionID: req.TransactionID,
+		Amount:        req.Amount,
+		Note:          req.Note,
+	}
+}
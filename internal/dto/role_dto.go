@@ -0,0 +1,17 @@
+package dto
+
+import domain "kasir-api/internal/domains"
+
+type RoleRequest struct {
+	Name        string   `json:"name" validate:"required,min=1,max=50"`
+	Description string   `json:"description" validate:"max=255"`
+	Permissions []string `json:"permissions" validate:"dive,required,max=50"`
+}
+
+func RoleReqToDomain(req *RoleRequest) *domain.Role {
+	return &domain.Role{
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: req.Permissions,
+	}
+}
@@ -0,0 +1,51 @@
+package dto
+
+import domain "kasir-api/internal/domains"
+
+type TaxRateRequest struct {
+	Name string  `json:"name" validate:"required,min=1,max=100"`
+	Rate float64 `json:"rate" validate:"min=0,max=100"`
+}
+
+func TaxRateReqToDomain(req *TaxRateRequest) *domain.TaxRate {
+	return &domain.TaxRate{
+		Name: req.Name,
+		Rate: req.Rate,
+	}
+}
+
+type StoreSettingsRequest struct {
+	DefaultTaxRateID   *int    `json:"default_tax_rate_id" validate:"omitempty,gt=0"`
+	TaxInclusive       bool    `json:"tax_inclusive"`
+	ServiceChargeRate  float64 `json:"service_charge_rate" validate:"min=0,max=100"`
+	ServiceChargeOrder string  `json:"service_charge_order" validate:"omitempty,oneof=before_tax after_tax"`
+}
+
+func StoreSettingsReqToDomain(req *StoreSettingsRequest) *domain.StoreSettings {
+	order := req.ServiceChargeOrder
+	if order == "" {
+		order = domain.ServiceChargeBeforeTax
+	}
+	return &domain.StoreSettings{
+		DefaultTaxRateID:   req.DefaultTaxRateID,
+		TaxInclusive:       req.TaxInclusive,
+		ServiceChargeRate:  req.ServiceChargeRate,
+		ServiceChargeOrder: order,
+	}
+}
+
+type ProductTaxRequest struct {
+	TaxRateID *int `json:"tax_rate_id" validate:"omitempty,gt=0"`
+	TaxExempt bool `json:"tax_exempt"`
+}
+
+func ProductTaxReqToDomain(req *ProductTaxRequest) *domain.ProductTax {
+	return &domain.ProductTax{
+		TaxRateID: req.TaxRateID,
+		TaxExempt: req.TaxExempt,
+	}
+}
+
+type CategoryTaxRequest struct {
+	TaxRateID *int `json:"tax_rate_id" validate:"omitempty,gt=0"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+import domain "kasir-api/internal/domains"
+
+type TenantRequest struct {
+	Name          string `json:"name" validate:"required,min=1,max=100"`
+	OwnerUsername string `json:"owner_username" validate:"required,min=3,max=100"`
+	OwnerName     string `json:"owner_name" validate:"required,min=1,max=255"`
+	OwnerPassword string `json:"owner_password" validate:"required,min=8,max=72"`
+}
+
+func TenantReqToDomain(req *TenantRequest) (*domain.Tenant, *domain.User) {
+	return &domain.Tenant{Name: req.Name}, &domain.User{
+		Username: req.OwnerUsername,
+		Name:     req.OwnerName,
+	}
+}
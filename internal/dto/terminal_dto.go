@@ -0,0 +1,19 @@
+package dto
+
+import domain "kasir-api/internal/domains"
+
+type TerminalRequest struct {
+	Name   string `json:"name" validate:"required,min=1,max=100"`
+	Active *bool  `json:"active"`
+}
+
+func TerminalReqToDomain(req *TerminalRequest) *domain.Terminal {
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+	return &domain.Terminal{
+		Name:   req.Name,
+		Active: active,
+	}
+}
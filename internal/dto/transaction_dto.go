@@ -0,0 +1,79 @@
+package dto
+
+import domain "kasir-api/internal/domains"
+
+type CheckoutItemRequest struct {
+	ProductID int  `json:"product_id" validate:"required,gt=0"`
+	VariantID *int `json:"variant_id" validate:"omitempty,gt=0"`
+	UnitID    *int `json:"unit_id" validate:"omitempty,gt=0"`
+	Quantity  int  `json:"quantity" validate:"required,gt=0"`
+
+	ModifierIDs []int `json:"modifier_ids" validate:"omitempty,dive,gt=0"`
+	// PriceOverride sells the item at this unit price, which needs approval
+	// unless the cashier may override prices.
+	PriceOverride *int `json:"price_override" validate:"omitempty,min=0"`
+}
+
+type CheckoutPaymentRequest struct {
+	Method string `json:"method" validate:"required,oneof=cash points on_account gift_card"`
+	Amount int    `json:"amount" validate:"min=0"`
+	Points int    `json:"points" validate:"min=0"`
+	Code   string `json:"code" validate:"required_if=Method gift_card,max=32"`
+}
+
+// CheckoutGiftCardRequest sells a new gift card, or tops up the card with
+// the given code.
+type CheckoutGiftCardRequest struct {
+	Code   string `json:"code" validate:"max=32"`
+	Amount int    `json:"amount" validate:"required,gt=0"`
+}
+
+type CheckoutRequest struct {
+	Items       []CheckoutItemRequest     `json:"items" validate:"omitempty,dive"`
+	VoucherCode string                    `json:"voucher_code" validate:"max=50"`
+	CustomerID  *int                      `json:"customer_id" validate:"omitempty,gt=0"`
+	Payments    []CheckoutPaymentRequest  `json:"payments" validate:"omitempty,dive"`
+	GiftCards   []CheckoutGiftCardRequest `json:"gift_cards" validate:"omitempty,dive"`
+	// ManualDiscount is taken off the cart after promotions and vouchers.
+	ManualDiscount int `json:"manual_discount" validate:"min=0"`
+}
+
+func CheckoutReqToDomain(req *CheckoutRequest) *domain.Checkout {
+	items := make([]domain.CheckoutItem, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = domain.CheckoutItem{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			UnitID:    item.UnitID,
+			Quantity:  item.Quantity,
+
+			ModifierIDs:   item.ModifierIDs,
+			PriceOverride: item.PriceOverride,
+		}
+	}
+	payments := make([]domain.CheckoutPayment, len(req.Payments))
+	for i, payment := range req.Payments {
+		payments[i] = domain.CheckoutPayment{
+			Method: payment.Method,
+			Amount: payment.Amount,
+			Points: payment.Points,
+			Code:   payment.Code,
+		}
+	}
+	giftCards := make([]domain.CheckoutGiftCard, len(req.GiftCards))
+	for i, giftCard := range req.GiftCards {
+		giftCards[i] = domain.CheckoutGiftCard{
+			Code:   giftCard.Code,
+			Amount: giftCard.Amount,
+		}
+	}
+	return &domain.Checkout{
+		Items:       items,
+		VoucherCode: req.VoucherCode,
+		CustomerID:  req.CustomerID,
+		Payments:    payments,
+		GiftCards:   giftCards,
+
+		ManualDiscount: req.ManualDiscount,
+	}
+}
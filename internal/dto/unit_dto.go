@@ -0,0 +1,19 @@
+package dto
+
+import domain "kasir-api/internal/domains"
+
+type UnitRequest struct {
+	Name             string `json:"name" validate:"required,min=1,max=20"`
+	ConversionFactor int    `json:"conversion_factor" validate:"required,gt=0"`
+	Price            int    `json:"price" validate:"number,min=0"`
+	Barcode          string `json:"barcode" validate:"max=64"`
+}
+
+func UnitReqToDomain(req *UnitRequest) *domain.ProductUnit {
+	return &domain.ProductUnit{
+		Name:             req.Name,
+		ConversionFactor: req.ConversionFactor,
+		Price:            req.Price,
+		Barcode:          req.Barcode,
+	}
+}
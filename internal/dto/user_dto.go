@@ -0,0 +1,22 @@
+package dto
+
+import domain "kasir-api/internal/domains"
+
+type UserRequest struct {
+	Username string   `json:"username" validate:"required,min=3,max=100"`
+	Name     string   `json:"name" validate:"required,min=1,max=255"`
+	Password string   `json:"password" validate:"required,min=8,max=72"`
+	Roles    []string `json:"roles" validate:"required,min=1,dive,required,max=50"`
+}
+
+type UserRolesRequest struct {
+	Roles []string `json:"roles" validate:"required,min=1,dive,required,max=50"`
+}
+
+func UserReqToDomain(req *UserRequest) *domain.User {
+	return &domain.User{
+		Username: req.Username,
+		Name:     req.Name,
+		Roles:    req.Roles,
+	}
+}
@@ -0,0 +1,47 @@
+package dto
+
+import domain "kasir-api/internal/domains"
+
+type VariantOptionRequest struct {
+	Name   string   `json:"name" validate:"required,min=1,max=50"`
+	Values []string `json:"values" validate:"required,min=1,dive,required"`
+}
+
+type VariantOptionsRequest struct {
+	Options []VariantOptionRequest `json:"options" validate:"dive"`
+}
+
+func VariantOptionsReqToDomain(req *VariantOptionsRequest) []domain.VariantOption {
+	options := make([]domain.VariantOption, len(req.Options))
+	for i, option := range req.Options {
+		options[i] = domain.VariantOption{
+			Name:   option.Name,
+			Values: option.Values,
+		}
+	}
+	return options
+}
+
+type VariantRequest struct {
+	SKU     string            `json:"sku" validate:"required,min=1,max=64"`
+	Barcode string            `json:"barcode" validate:"max=64"`
+	Name    string            `json:"name" validate:"required,min=1"`
+	Options map[string]string `json:"options"`
+	Price   int               `json:"price" validate:"number,min=0"`
+	Stock   int               `json:"stock" validate:"number,min=0"`
+}
+
+func VariantReqToDomain(req *VariantRequest) *domain.ProductVariant {
+	options := req.Options
+	if options == nil {
+		options = map[string]string{}
+	}
+	return &domain.ProductVariant{
+		SKU:     req.SKU,
+		Barcode: req.Barcode,
+		Name:    req.Name,
+		Options: options,
+		Price:   req.Price,
+		Stock:   req.Stock,
+	}
+}
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	domain "kasir-api/internal/domains"
+	"time"
+)
+
+type VoucherRequest struct {
+	Code             string     `json:"code" validate:"required,min=3,max=50"`
+	Name             string     `json:"name" validate:"required,min=1,max=255"`
+	DiscountType     string     `json:"discount_type" validate:"required,oneof=percentage fixed"`
+	Value            int        `json:"value" validate:"required,gt=0"`
+	MaxDiscount      int        `json:"max_discount" validate:"min=0"`
+	MinPurchase      int        `json:"min_purchase" validate:"min=0"`
+	StartsAt         *time.Time `json:"starts_at"`
+	EndsAt           *time.Time `json:"ends_at"`
+	UsageLimit       int        `json:"usage_limit" validate:"min=0"`
+	PerCustomerLimit int        `json:"per_customer_limit" validate:"min=0"`
+	Active           *bool      `json:"active"`
+}
+
+func VoucherReqToDomain(req *VoucherRequest) *domain.Voucher {
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+	return &domain.Voucher{
+		Code:             req.Code,
+		Name:             req.Name,
+		DiscountType:     req.DiscountType,
+		Value:            req.Value,
+		MaxDiscount:      req.MaxDiscount,
+		MinPurchase:      req.MinPurchase,
+		StartsAt:         req.StartsAt,
+		EndsAt:           req.EndsAt,
+		UsageLimit:       req.UsageLimit,
+		PerCustomerLimit: req.PerCustomerLimit,
+		Active:           active,
+	}
+}
+
+type VoucherValidateRequest struct {
+	Code       string `json:"code" validate:"required,max=50"`
+	CustomerID *int   `json:"customer_id" validate:"omitempty,gt=0"`
+	Amount     int    `json:"amount" validate:"min=0"`
+}
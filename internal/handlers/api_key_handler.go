@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/dto"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+	"strconv"
+)
+
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+// GetAPIKeys godoc
+// @Summary Get all API keys
+// @Description Mengambil semua API key beserta permission, masa berlaku dan waktu terakhir dipakai. Key aslinya tidak pernah ditampilkan lagi
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/api-keys [get]
+func (h *APIKeyHandler) GetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.apiKeyService.GetAPIKeys(r.Context())
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get api keys")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "API keys found", keys)
+}
+
+// CreateAPIKey godoc
+// @Summary Create API key
+// @Description Membuat API key untuk integrasi antar sistem. Permission harus dimiliki pembuatnya. Key hanya ditampilkan sekali di response ini, kirim sebagai "Authorization: Bearer <key>"
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param api_key body dto.APIKeyRequest true "API Key Data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req dto.APIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	key, err := h.apiKeyService.CreateAPIKey(r.Context(), dto.APIKeyReqToDomain(&req))
+	if err != nil {
+		h.writeError(w, err, "Failed to create api key")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "API key created successfully", key)
+}
+
+// RotateAPIKey godoc
+// @Summary Rotate API key
+// @Description Mengganti key dari API key dengan nama dan permission yang sama. Key lama langsung tidak berlaku, key baru hanya ditampilkan sekali
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "API Key ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/api-keys/{id}/rotate [post]
+func (h *APIKeyHandler) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	key, err := h.apiKeyService.RotateAPIKey(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err, "Failed to rotate api key")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "API key rotated successfully", key)
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke API key
+// @Description Menonaktifkan API key secara permanen. Data key tetap disimpan agar riwayat perubahannya bisa ditelusuri
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "API Key ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/api-keys/{id}/revoke [post]
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	key, err := h.apiKeyService.RevokeAPIKey(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err, "Failed to revoke api key")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "API key revoked successfully", key)
+}
+
+func (h *APIKeyHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, utils.ErrAPIKeyNotFound):
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrUnknownPermission), errors.Is(err, utils.ErrInvalidAPIKeyExpiry):
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, utils.ErrAPIKeyScope):
+		utils.ErrorResponse(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, utils.ErrDuplicateAPIKey), errors.Is(err, utils.ErrAPIKeyRevoked):
+		utils.ErrorResponse(w, http.StatusConflict, err.Error())
+	default:
+		utils.ErrorResponse(w, http.StatusInternalServerError, message)
+	}
+}
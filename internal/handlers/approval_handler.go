@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/internal/dto"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+)
+
+type ApprovalHandler struct {
+	approvalService service.ApprovalService
+}
+
+func NewApprovalHandler(approvalService service.ApprovalService) *ApprovalHandler {
+	return &ApprovalHandler{approvalService: approvalService}
+}
+
+// GetApprovalSettings godoc
+// @Summary Get approval settings
+// @Description Mengambil pengaturan persetujuan supervisor: batas persentase diskon manual dari subtotal yang masih boleh diberikan kasir tanpa persetujuan
+// @Tags approvals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/settings/approvals [get]
+func (h *ApprovalHandler) GetApprovalSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.approvalService.GetApprovalSettings(r.Context())
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get settings")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Settings found", settings)
+}
+
+// UpdateApprovalSettings godoc
+// @Summary Update approval settings
+// @Description Update pengaturan persetujuan supervisor. discount_threshold_percent 0 berarti setiap diskon manual butuh persetujuan
+// @Tags approvals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param settings body dto.ApprovalSettingsRequest true "Approval Settings"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/settings/approvals [put]
+func (h *ApprovalHandler) UpdateApprovalSettings(w http.ResponseWriter, r *http.Request) {
+	var req dto.ApprovalSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	settings, err := h.approvalService.UpdateApprovalSettings(r.Context(), dto.ApprovalSettingsReqToDomain(&req))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to update settings")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Settings updated successfully", settings)
+}
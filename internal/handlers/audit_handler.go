@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	domain "kasir-api/internal/domains"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// GetAuditLogs godoc
+// @Summary Get audit logs
+// @Description Mengambil log audit setiap create, update dan delete: pelaku, aksi, jenis dan ID data, perubahan per field (old/new), IP dan request ID. from dan to memakai format YYYY-MM-DD (to termasuk)
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param entity_type query string false "Filter by entity type" Enums(product, category, variant, variant_option, unit, product_component, purchase, purchase_item, stock_movement, price_list, price_list_item, customer_price_list, scheduled_price, price_window, api_key, tenant, role, role_permission, user_role, user, terminal, customer, promotion, promotion_product, promotion_category, voucher, tax_rate, store_settings, loyalty_settings, approval_settings, modifier_group, modifier, product_modifier_group, category_modifier_group, gift_card, receivable)
+// @Param entity_id query int false "Filter by entity ID"
+// @Param actor_id query int false "Filter by user ID of the actor"
+// @Param api_key_id query int false "Filter by API key ID"
+// @Param action query string false "Filter by action" Enums(create, update, delete)
+// @Param request_id query string false "Filter by request ID"
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/audit-logs [get]
+func (h *AuditHandler) GetAuditLogs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := domain.AuditLogFilter{
+		EntityType: query.Get("entity_type"),
+		Action:     query.Get("action"),
+		RequestID:  query.Get("request_id"),
+	}
+	switch filter.Action {
+	case "", domain.AuditActionCreate, domain.AuditActionUpdate, domain.AuditActionDelete:
+	default:
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+	if entityID := query.Get("entity_id"); entityID != "" {
+		id, err := strconv.Atoi(entityID)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+			return
+		}
+		filter.EntityID = &id
+	}
+	if actorID := query.Get("actor_id"); actorID != "" {
+		id, err := strconv.Atoi(actorID)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+			return
+		}
+		filter.ActorID = &id
+	}
+	if apiKeyID := query.Get("api_key_id"); apiKeyID != "" {
+		id, err := strconv.Atoi(apiKeyID)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+			return
+		}
+		filter.APIKeyID = &id
+	}
+	if from := query.Get("from"); from != "" {
+		date, err := time.ParseInLocation(time.DateOnly, from, time.Local)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+			return
+		}
+		filter.From = &date
+	}
+	if to := query.Get("to"); to != "" {
+		date, err := time.ParseInLocation(time.DateOnly, to, time.Local)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+			return
+		}
+		date = date.AddDate(0, 0, 1)
+		filter.To = &date
+	}
+
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(query.Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = 10
+	}
+
+	logs, total, err := h.auditService.GetAuditLogs(r.Context(), filter, page, pageSize)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get audit logs")
+		return
+	}
+
+	utils.SuccessResponse(
+		w,
+		http.StatusOK,
+		"Audit logs found",
+		logs,
+		utils.WithPagination(total, page, pageSize),
+	)
+}
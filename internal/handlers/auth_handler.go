@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/dto"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+)
+
+type AuthHandler struct {
+	authService service.AuthService
+	userService service.UserService
+}
+
+func NewAuthHandler(authService service.AuthService, userService service.UserService) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+		userService: userService,
+	}
+}
+
+// Login godoc
+// @Summary Login
+// @Description Login dengan username dan password. Menghasilkan access token berumur pendek dan refresh token untuk memperpanjang sesi
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body dto.LoginRequest true "Credentials"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/auth/login [post]
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req dto.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	tokens, err := h.authService.Login(r.Context(), req.Username, req.Password)
+	if err != nil {
+		h.writeError(w, err, "Failed to login")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Login successful", tokens)
+}
+
+// Refresh godoc
+// @Summary Refresh tokens
+// @Description Menukar refresh token dengan access token dan refresh token baru. Refresh token hanya bisa dipakai sekali; memakai ulang token lama mencabut sesinya. Sesi PIN wajib mengirim header X-Terminal-Key
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param X-Terminal-Key header string false "Terminal key, for PIN sessions"
+// @Param token body dto.RefreshRequest true "Refresh Token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/auth/refresh [post]
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req dto.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	tokens, err := h.authService.Refresh(r.Context(), req.RefreshToken, r.Header.Get(utils.TerminalKeyHeader))
+	if err != nil {
+		h.writeError(w, err, "Failed to refresh token")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Token refreshed successfully", tokens)
+}
+
+// PINLogin godoc
+// @Summary Switch cashier with PIN
+// @Description Login cepat dengan PIN angka, hanya dari terminal terdaftar (header X-Terminal-Key). Kasir sebelumnya di terminal itu otomatis logout. PIN terkunci sementara setelah beberapa kali salah
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param X-Terminal-Key header string true "Terminal key"
+// @Param credentials body dto.PINLoginRequest true "Username and PIN"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 423 {object} map[string]string
+// @Router /api/auth/pin-login [post]
+func (h *AuthHandler) PINLogin(w http.ResponseWriter, r *http.Request) {
+	var req dto.PINLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	tokens, err := h.authService.PINLogin(r.Context(), r.Header.Get(utils.TerminalKeyHeader), req.Username, req.PIN)
+	if err != nil {
+		h.writeError(w, err, "Failed to login")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Login successful", tokens)
+}
+
+// SetPIN godoc
+// @Summary Set own PIN
+// @Description Mengatur PIN angka (4-8 digit) user yang sedang login untuk login cepat di terminal
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param pin body dto.PINRequest true "PIN"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/auth/pin [put]
+func (h *AuthHandler) SetPIN(w http.ResponseWriter, r *http.Request) {
+	var req dto.PINRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	if err := h.userService.SetPIN(r.Context(), utils.AuthUserFromContext(r.Context()).ID, req.PIN); err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to set PIN")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "PIN updated successfully", nil)
+}
+
+// Logout godoc
+// @Summary Logout
+// @Description Mengakhiri sesi saat ini. Access token dan refresh token sesi ini langsung tidak berlaku
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Router /api/auth/logout [post]
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	user := utils.AuthUserFromContext(r.Context())
+	if err := h.authService.Logout(r.Context(), user.SessionID); err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to logout")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Logout successful", nil)
+}
+
+// Me godoc
+// @Summary Current user
+// @Description Mengambil data user yang sedang login beserta role dan permission-nya
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Router /api/auth/me [get]
+func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
+	authUser := utils.AuthUserFromContext(r.Context())
+	user, err := h.userService.GetUserByID(r.Context(), authUser.ID)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+	user.Permissions = authUser.Permissions
+
+	utils.SuccessResponse(w, http.StatusOK, "User found", user)
+}
+
+// IssueApprovalGrant godoc
+// @Summary Issue approval grant
+// @Description Supervisor yang login menyetujui aksi (void, discount, price_override) untuk kasir tanpa memberikan token login-nya. Grant berlaku untuk satu transaksi (transaction_id) atau penjualan baru, hanya beberapa menit (AUTH_APPROVAL_GRANT_TTL) dan hanya sekali pakai. Kasir mengirim token grant di header X-Approval-Token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param grant body dto.ApprovalGrantRequest true "Approved actions"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/auth/approval-grants [post]
+func (h *AuthHandler) IssueApprovalGrant(w http.ResponseWriter, r *http.Request) {
+	var req dto.ApprovalGrantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	grant, err := h.authService.IssueApprovalGrant(r.Context(), req.Actions, req.TransactionID)
+	if errors.Is(err, utils.ErrApprovalGrantScope) {
+		utils.ErrorResponse(w, http.StatusForbidden, err.Error())
+		return
+	}
+	if errors.Is(err, utils.ErrTransactionNotFound) {
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to issue approval grant")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Approval grant issued successfully", grant)
+}
+
+func (h *AuthHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, utils.ErrInvalidCredentials),
+		errors.Is(err, utils.ErrInvalidRefreshToken),
+		errors.Is(err, utils.ErrInvalidPIN),
+		errors.Is(err, utils.ErrInvalidTerminal):
+		utils.ErrorResponse(w, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, utils.ErrPINLocked):
+		utils.ErrorResponse(w, http.StatusLocked, err.Error())
+	default:
+		utils.ErrorResponse(w, http.StatusInternalServerError, message)
+	}
+}
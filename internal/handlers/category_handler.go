@@ -25,6 +25,7 @@ func NewCategoryHandler(categoryService service.CategoryService) *CategoryHandle
 // @Tags categories
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Page size" default(10)
 // @Success 200 {object} map[string]interface{}
@@ -59,8 +60,11 @@ func (h *CategoryHandler) GetCategories(w http.ResponseWriter, r *http.Request)
 // @Tags categories
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path int true "Category ID"
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} map[string]interface{}
+// @Success 304 "Not Modified"
 // @Failure 404 {object} map[string]string
 // @Router /api/categories/{id} [get]
 func (h *CategoryHandler) GetCategoryByID(w http.ResponseWriter, r *http.Request) {
@@ -76,6 +80,10 @@ func (h *CategoryHandler) GetCategoryByID(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if utils.NotModified(w, r, category.Version) {
+		return
+	}
+
 	utils.SuccessResponse(w, http.StatusOK, "Category found", category)
 }
 
@@ -85,6 +93,7 @@ func (h *CategoryHandler) GetCategoryByID(w http.ResponseWriter, r *http.Request
 // @Tags categories
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param category body dto.CategoryRequest true "Category Data"
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
@@ -117,14 +126,23 @@ func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request)
 // @Tags categories
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path int true "Category ID"
+// @Param If-Match header string false "ETag of the version being updated"
 // @Param category body dto.CategoryRequest true "Category Data"
 // @Success 200 {object} map[string]interface{}
+// @Failure 412 {object} map[string]string
 // @Router /api/categories/{id} [put]
 func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
 	id := strings.TrimPrefix(r.URL.Path, "/api/categories/")
 	idInt, _ := strconv.Atoi(id)
 
+	versions, err := utils.IfMatchVersions(r)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	var req dto.CategoryRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
@@ -137,29 +155,106 @@ func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request)
 	}
 
 	category := dto.CategoryReqToDomain(&req)
-	updatedCategory, err := h.categoryService.UpdateCategory(r.Context(), idInt, category)
+	updatedCategory, err := h.categoryService.UpdateCategory(r.Context(), idInt, category, versions)
 	if err != nil {
 		if errors.Is(err, utils.ErrCategoryNotFound) {
 			utils.ErrorResponse(w, http.StatusNotFound, utils.ErrCategoryNotFound.Error())
 			return
 		}
+		if errors.Is(err, utils.ErrVersionMismatch) {
+			utils.ErrorResponse(w, http.StatusPreconditionFailed, utils.ErrVersionMismatch.Error())
+			return
+		}
 		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to update category")
 		return
 	}
 
 	updatedCategory.ID = idInt
+	utils.SetETag(w, updatedCategory.Version)
 
 	utils.SuccessResponse(w, http.StatusOK, "Category updated successfully", updatedCategory)
 }
 
+// PatchCategory godoc
+// @Summary Partially update category
+// @Description Update sebagian field kategori menggunakan JSON Merge Patch (RFC 7396). Description bernilai null akan dikosongkan
+// @Tags categories
+// @Accept application/merge-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Category ID"
+// @Param If-Match header string false "ETag of the version being updated"
+// @Param category body dto.CategoryPatchRequest true "Category Merge Patch"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Router /api/categories/{id} [patch]
+func (h *CategoryHandler) PatchCategory(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/api/categories/")
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	versions, err := utils.IfMatchVersions(r)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req dto.CategoryPatchRequest
+	nulls, err := utils.DecodeMergePatch(r, &req)
+	if err != nil {
+		if errors.Is(err, utils.ErrUnsupportedMediaType) {
+			utils.ErrorResponse(w, http.StatusUnsupportedMediaType, err.Error())
+			return
+		}
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if fieldErrors := utils.NullFieldErrors(nulls, "name"); len(fieldErrors) > 0 {
+		utils.FieldErrorsResponse(w, fieldErrors)
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	category, err := h.categoryService.PatchCategory(r.Context(), idInt, dto.CategoryPatchReqToDomain(&req, nulls), versions)
+	if err != nil {
+		if errors.Is(err, utils.ErrCategoryNotFound) {
+			utils.ErrorResponse(w, http.StatusNotFound, utils.ErrCategoryNotFound.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrVersionMismatch) {
+			utils.ErrorResponse(w, http.StatusPreconditionFailed, utils.ErrVersionMismatch.Error())
+			return
+		}
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to update category")
+		return
+	}
+
+	utils.SetETag(w, category.Version)
+	utils.SuccessResponse(w, http.StatusOK, "Category updated successfully", category)
+}
+
 // DeleteCategory godoc
 // @Summary Delete category
 // @Description Menghapus kategori berdasarkan ID
 // @Tags categories
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path int true "Category ID"
+// @Param If-Match header string false "ETag of the version being deleted"
 // @Success 200 {object} map[string]interface{}
+// @Failure 412 {object} map[string]string
 // @Router /api/categories/{id} [delete]
 func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
 	id := strings.TrimPrefix(r.URL.Path, "/api/categories/")
@@ -168,11 +263,22 @@ func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request)
 		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
 		return
 	}
-	if err := h.categoryService.DeleteCategory(r.Context(), idInt); err != nil {
+
+	versions, err := utils.IfMatchVersions(r)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.categoryService.DeleteCategory(r.Context(), idInt, versions); err != nil {
 		if errors.Is(err, utils.ErrCategoryNotFound) {
 			utils.ErrorResponse(w, http.StatusNotFound, utils.ErrCategoryNotFound.Error())
 			return
 		}
+		if errors.Is(err, utils.ErrVersionMismatch) {
+			utils.ErrorResponse(w, http.StatusPreconditionFailed, utils.ErrVersionMismatch.Error())
+			return
+		}
 		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to delete category")
 		return
 	}
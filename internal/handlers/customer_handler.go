@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/dto"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type CustomerHandler struct {
+	customerService service.CustomerService
+}
+
+func NewCustomerHandler(customerService service.CustomerService) *CustomerHandler {
+	return &CustomerHandler{customerService: customerService}
+}
+
+// GetCustomers godoc
+// @Summary Get all customers
+// @Description Mengambil data pelanggan, bisa dicari berdasarkan nama atau nomor telepon
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Param search query string false "Search by customer name"
+// @Param phone query string false "Search by phone number"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/customers [get]
+func (h *CustomerHandler) GetCustomers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(query.Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = 10
+	}
+
+	filter := domain.CustomerFilter{
+		Search: strings.TrimSpace(query.Get("search")),
+		Phone:  query.Get("phone"),
+	}
+
+	customers, total, err := h.customerService.GetCustomers(r.Context(), filter, page, pageSize)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get customers")
+		return
+	}
+
+	utils.SuccessResponse(
+		w,
+		http.StatusOK,
+		"Customers found",
+		customers,
+		utils.WithPagination(total, page, pageSize),
+	)
+}
+
+// GetCustomerByID godoc
+// @Summary Get customer by ID
+// @Description Mengambil pelanggan berdasarkan ID
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Customer ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/customers/{id} [get]
+func (h *CustomerHandler) GetCustomerByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	customer, err := h.customerService.GetCustomerByID(r.Context(), id)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, utils.ErrCustomerNotFound.Error())
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Customer found", customer)
+}
+
+// CreateCustomer godoc
+// @Summary Create customer
+// @Description Mendaftarkan pelanggan baru. Nomor telepon disimpan tanpa spasi atau tanda baca dan harus unik
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param customer body dto.CustomerRequest true "Customer Data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/customers [post]
+func (h *CustomerHandler) CreateCustomer(w http.ResponseWriter, r *http.Request) {
+	var req dto.CustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	customer, err := h.customerService.CreateCustomer(r.Context(), dto.CustomerReqToDomain(&req))
+	if err != nil {
+		h.writeError(w, err, "Failed to create customer")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Customer created successfully", customer)
+}
+
+// UpdateCustomer godoc
+// @Summary Update customer
+// @Description Update pelanggan berdasarkan ID
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Customer ID"
+// @Param customer body dto.CustomerRequest true "Customer Data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/customers/{id} [put]
+func (h *CustomerHandler) UpdateCustomer(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.CustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	customer, err := h.customerService.UpdateCustomer(r.Context(), id, dto.CustomerReqToDomain(&req))
+	if err != nil {
+		h.writeError(w, err, "Failed to update customer")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Customer updated successfully", customer)
+}
+
+// DeleteCustomer godoc
+// @Summary Delete customer
+// @Description Menghapus pelanggan berdasarkan ID. Transaksi pelanggan tetap disimpan tanpa pelanggan
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Customer ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/customers/{id} [delete]
+func (h *CustomerHandler) DeleteCustomer(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	if err := h.customerService.DeleteCustomer(r.Context(), id); err != nil {
+		h.writeError(w, err, "failed to delete customer")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Customer deleted successfully", nil)
+}
+
+// GetCustomerTransactions godoc
+// @Summary Get customer purchase history
+// @Description Mengambil riwayat transaksi pelanggan beserta total belanja sepanjang waktu dan jumlah kunjungan
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Customer ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/customers/{id}/transactions [get]
+func (h *CustomerHandler) GetCustomerTransactions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = 10
+	}
+
+	history, total, err := h.customerService.GetCustomerHistory(r.Context(), id, page, pageSize)
+	if err != nil {
+		h.writeError(w, err, "failed to get customer transactions")
+		return
+	}
+
+	utils.SuccessResponse(
+		w,
+		http.StatusOK,
+		"Customer transactions found",
+		history,
+		utils.WithPagination(total, page, pageSize),
+	)
+}
+
+func (h *CustomerHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, utils.ErrCustomerNotFound):
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrDuplicateCustomer), errors.Is(err, utils.ErrCustomerHasCredit):
+		utils.ErrorResponse(w, http.StatusConflict, err.Error())
+	default:
+		utils.ErrorResponse(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/dto"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+	"strconv"
+)
+
+type GiftCardHandler struct {
+	giftCardService service.GiftCardService
+}
+
+func NewGiftCardHandler(giftCardService service.GiftCardService) *GiftCardHandler {
+	return &GiftCardHandler{giftCardService: giftCardService}
+}
+
+// GetGiftCards godoc
+// @Summary Get all gift cards
+// @Description Mengambil semua gift card beserta saldonya. Kode disamarkan kecuali 4 karakter terakhir
+// @Tags gift-cards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/gift-cards [get]
+func (h *GiftCardHandler) GetGiftCards(w http.ResponseWriter, r *http.Request) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = 10
+	}
+
+	cards, total, err := h.giftCardService.GetGiftCards(r.Context(), page, pageSize)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get gift cards")
+		return
+	}
+
+	utils.SuccessResponse(
+		w,
+		http.StatusOK,
+		"Gift cards found",
+		cards,
+		utils.WithPagination(total, page, pageSize),
+	)
+}
+
+// GetGiftCardBalance godoc
+// @Summary Gift card balance inquiry
+// @Description Cek saldo gift card beserta riwayat mutasinya, terbaru di atas. Kode dikirim di body agar tidak tercatat di URL
+// @Tags gift-cards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param card body dto.GiftCardBalanceRequest true "Gift Card Code"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/gift-cards/balance [post]
+func (h *GiftCardHandler) GetGiftCardBalance(w http.ResponseWriter, r *http.Request) {
+	var req dto.GiftCardBalanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = 10
+	}
+
+	statement, total, err := h.giftCardService.GetGiftCardStatement(r.Context(), req.Code, page, pageSize)
+	if err != nil {
+		if errors.Is(err, utils.ErrGiftCardNotFound) {
+			utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get gift card")
+		return
+	}
+
+	utils.SuccessResponse(
+		w,
+		http.StatusOK,
+		"Gift card found",
+		statement,
+		utils.WithPagination(total, page, pageSize),
+	)
+}
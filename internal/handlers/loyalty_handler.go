@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/dto"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+	"strconv"
+)
+
+type LoyaltyHandler struct {
+	loyaltyService service.LoyaltyService
+}
+
+func NewLoyaltyHandler(loyaltyService service.LoyaltyService) *LoyaltyHandler {
+	return &LoyaltyHandler{loyaltyService: loyaltyService}
+}
+
+// GetLoyaltySettings godoc
+// @Summary Get loyalty settings
+// @Description Mengambil pengaturan program loyalitas: nilai belanja per poin, nilai tukar poin, masa berlaku dan minimal penukaran
+// @Tags loyalty
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/settings/loyalty [get]
+func (h *LoyaltyHandler) GetLoyaltySettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.loyaltyService.GetLoyaltySettings(r.Context())
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get settings")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Settings found", settings)
+}
+
+// UpdateLoyaltySettings godoc
+// @Summary Update loyalty settings
+// @Description Update pengaturan program loyalitas. expiry_days 0 berarti poin tidak pernah kedaluwarsa
+// @Tags loyalty
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param settings body dto.LoyaltySettingsRequest true "Loyalty Settings"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/settings/loyalty [put]
+func (h *LoyaltyHandler) UpdateLoyaltySettings(w http.ResponseWriter, r *http.Request) {
+	var req dto.LoyaltySettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	settings, err := h.loyaltyService.UpdateLoyaltySettings(r.Context(), dto.LoyaltySettingsReqToDomain(&req))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to update settings")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Settings updated successfully", settings)
+}
+
+// SetProductLoyalty godoc
+// @Summary Set product bonus points
+// @Description Mengatur bonus poin per unit dasar produk. Tanpa bonus_points, produk memakai bonus kategori
+// @Tags loyalty
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param loyalty body dto.ProductLoyaltyRequest true "Product Bonus Points"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/products/{id}/loyalty [put]
+func (h *LoyaltyHandler) SetProductLoyalty(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.ProductLoyaltyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	if err := h.loyaltyService.SetProductBonus(r.Context(), productID, req.BonusPoints); err != nil {
+		h.writeError(w, err, "Failed to set product bonus points")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Product bonus points updated successfully", req)
+}
+
+// SetCategoryLoyalty godoc
+// @Summary Set category bonus points
+// @Description Mengatur bonus poin per unit dasar untuk semua produk dalam kategori yang tidak punya bonus sendiri
+// @Tags loyalty
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Category ID"
+// @Param loyalty body dto.CategoryLoyaltyRequest true "Category Bonus Points"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/categories/{id}/loyalty [put]
+func (h *LoyaltyHandler) SetCategoryLoyalty(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.CategoryLoyaltyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	if err := h.loyaltyService.SetCategoryBonus(r.Context(), categoryID, req.BonusPoints); err != nil {
+		h.writeError(w, err, "Failed to set category bonus points")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Category bonus points updated successfully", req)
+}
+
+// GetCustomerLoyalty godoc
+// @Summary Get customer loyalty points
+// @Description Mengambil saldo poin pelanggan beserta riwayat poin (dapat, tukar, kedaluwarsa, refund), terbaru di atas
+// @Tags loyalty
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Customer ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/customers/{id}/loyalty [get]
+func (h *LoyaltyHandler) GetCustomerLoyalty(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = 10
+	}
+
+	account, total, err := h.loyaltyService.GetLoyaltyAccount(r.Context(), customerID, page, pageSize)
+	if err != nil {
+		h.writeError(w, err, "failed to get loyalty points")
+		return
+	}
+
+	utils.SuccessResponse(
+		w,
+		http.StatusOK,
+		"Loyalty points found",
+		account,
+		utils.WithPagination(total, page, pageSize),
+	)
+}
+
+func (h *LoyaltyHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, utils.ErrProductNotFound),
+		errors.Is(err, utils.ErrCategoryNotFound),
+		errors.Is(err, utils.ErrCustomerNotFound):
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+	default:
+		utils.ErrorResponse(w, http.StatusInternalServerError, message)
+	}
+}
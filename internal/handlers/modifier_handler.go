@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/dto"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+	"strconv"
+)
+
+type ModifierHandler struct {
+	modifierService service.ModifierService
+}
+
+func NewModifierHandler(modifierService service.ModifierService) *ModifierHandler {
+	return &ModifierHandler{modifierService: modifierService}
+}
+
+// GetModifierGroups godoc
+// @Summary Get all modifier groups
+// @Description Mengambil semua grup modifier beserta pilihan modifiernya
+// @Tags modifiers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/modifier-groups [get]
+func (h *ModifierHandler) GetModifierGroups(w http.ResponseWriter, r *http.Request) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = 10
+	}
+
+	groups, total, err := h.modifierService.GetModifierGroups(r.Context(), page, pageSize)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get modifier groups")
+		return
+	}
+
+	utils.SuccessResponse(
+		w,
+		http.StatusOK,
+		"Modifier groups found",
+		groups,
+		utils.WithPagination(total, page, pageSize),
+	)
+}
+
+// GetModifierGroupByID godoc
+// @Summary Get modifier group by ID
+// @Description Mengambil grup modifier berdasarkan ID
+// @Tags modifiers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Modifier Group ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/modifier-groups/{id} [get]
+func (h *ModifierHandler) GetModifierGroupByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	group, err := h.modifierService.GetModifierGroupByID(r.Context(), id)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, utils.ErrModifierGroupNotFound.Error())
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Modifier group found", group)
+}
+
+// CreateModifierGroup godoc
+// @Summary Create modifier group
+// @Description Membuat grup modifier dengan aturan minimal/maksimal pilihan dan selisih harga tiap modifier
+// @Tags modifiers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param group body dto.ModifierGroupRequest true "Modifier Group Data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/modifier-groups [post]
+func (h *ModifierHandler) CreateModifierGroup(w http.ResponseWriter, r *http.Request) {
+	var req dto.ModifierGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	group, err := h.modifierService.CreateModifierGroup(r.Context(), dto.ModifierGroupReqToDomain(&req))
+	if err != nil {
+		h.writeError(w, err, "Failed to create modifier group")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Modifier group created successfully", group)
+}
+
+// UpdateModifierGroup godoc
+// @Summary Update modifier group
+// @Description Update grup modifier dan mengganti seluruh daftar modifiernya
+// @Tags modifiers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Modifier Group ID"
+// @Param group body dto.ModifierGroupRequest true "Modifier Group Data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/modifier-groups/{id} [put]
+func (h *ModifierHandler) UpdateModifierGroup(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.ModifierGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	group, err := h.modifierService.UpdateModifierGroup(r.Context(), id, dto.ModifierGroupReqToDomain(&req))
+	if err != nil {
+		h.writeError(w, err, "Failed to update modifier group")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Modifier group updated successfully", group)
+}
+
+// DeleteModifierGroup godoc
+// @Summary Delete modifier group
+// @Description Menghapus grup modifier berdasarkan ID
+// @Tags modifiers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Modifier Group ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/modifier-groups/{id} [delete]
+func (h *ModifierHandler) DeleteModifierGroup(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	if err := h.modifierService.DeleteModifierGroup(r.Context(), id); err != nil {
+		h.writeError(w, err, "failed to delete modifier group")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Modifier group deleted successfully", nil)
+}
+
+// GetProductModifierGroups godoc
+// @Summary Get product modifier groups
+// @Description Mengambil grup modifier yang berlaku untuk produk, baik dari produk maupun kategorinya
+// @Tags modifiers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/products/{id}/modifier-groups [get]
+func (h *ModifierHandler) GetProductModifierGroups(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	groups, err := h.modifierService.GetProductModifierGroups(r.Context(), productID)
+	if err != nil {
+		h.writeError(w, err, "failed to get modifier groups")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Modifier groups found", groups)
+}
+
+// SetProductModifierGroups godoc
+// @Summary Link modifier groups to product
+// @Description Mengganti grup modifier yang terhubung langsung ke produk
+// @Tags modifiers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param groups body dto.ModifierGroupLinkRequest true "Modifier Group IDs"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/products/{id}/modifier-groups [put]
+func (h *ModifierHandler) SetProductModifierGroups(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.ModifierGroupLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	groups, err := h.modifierService.SetProductModifierGroups(r.Context(), productID, req.GroupIDs)
+	if err != nil {
+		h.writeError(w, err, "Failed to link modifier groups")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Modifier groups linked successfully", groups)
+}
+
+// SetCategoryModifierGroups godoc
+// @Summary Link modifier groups to category
+// @Description Mengganti grup modifier yang berlaku untuk semua produk dalam kategori
+// @Tags modifiers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Category ID"
+// @Param groups body dto.ModifierGroupLinkRequest true "Modifier Group IDs"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/categories/{id}/modifier-groups [put]
+func (h *ModifierHandler) SetCategoryModifierGroups(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.ModifierGroupLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	if err := h.modifierService.SetCategoryModifierGroups(r.Context(), categoryID, req.GroupIDs); err != nil {
+		h.writeError(w, err, "Failed to link modifier groups")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Modifier groups linked successfully", nil)
+}
+
+func (h *ModifierHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, utils.ErrModifierGroupNotFound),
+		errors.Is(err, utils.ErrProductNotFound),
+		errors.Is(err, utils.ErrCategoryNotFound):
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrInvalidModifierGroup), errors.Is(err, utils.ErrInvalidIngredient):
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(w, http.StatusInternalServerError, message)
+	}
+}
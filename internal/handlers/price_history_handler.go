@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"errors"
+	domain "kasir-api/internal/domains"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type PriceHistoryHandler struct {
+	priceHistoryService service.PriceHistoryService
+}
+
+func NewPriceHistoryHandler(priceHistoryService service.PriceHistoryService) *PriceHistoryHandler {
+	return &PriceHistoryHandler{priceHistoryService: priceHistoryService}
+}
+
+// GetPriceHistory godoc
+// @Summary Get product price history
+// @Description Mengambil riwayat perubahan harga produk (harga lama, harga baru, oleh siapa, kapan dan sumbernya), terbaru lebih dulu
+// @Tags price-history
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/products/{id}/price-history [get]
+func (h *PriceHistoryHandler) GetPriceHistory(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = 10
+	}
+
+	history, total, err := h.priceHistoryService.GetPriceHistory(r.Context(), productID, page, pageSize)
+	if err != nil {
+		if errors.Is(err, utils.ErrProductNotFound) {
+			utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get price history")
+		return
+	}
+
+	utils.SuccessResponse(
+		w,
+		http.StatusOK,
+		"Price history found",
+		history,
+		utils.WithPagination(total, page, pageSize),
+	)
+}
+
+// GetPriceChanges godoc
+// @Summary Price change report
+// @Description Laporan semua perubahan harga dalam periode tertentu. from dan to memakai format YYYY-MM-DD (to termasuk), default 30 hari terakhir
+// @Tags price-history
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param source query string false "Filter by source" Enums(manual, bulk, import, schedule)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/reports/price-changes [get]
+func (h *PriceHistoryHandler) GetPriceChanges(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	filter := domain.PriceHistoryFilter{
+		From:   today.AddDate(0, 0, -30),
+		To:     today.AddDate(0, 0, 1),
+		Source: query.Get("source"),
+	}
+	if from := query.Get("from"); from != "" {
+		date, err := time.ParseInLocation(time.DateOnly, from, time.Local)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+			return
+		}
+		filter.From = date
+	}
+	if to := query.Get("to"); to != "" {
+		date, err := time.ParseInLocation(time.DateOnly, to, time.Local)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+			return
+		}
+		filter.To = date.AddDate(0, 0, 1)
+	}
+	switch filter.Source {
+	case "", domain.PriceSourceManual, domain.PriceSourceBulk, domain.PriceSourceImport, domain.PriceSourceSchedule:
+	default:
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(query.Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = 10
+	}
+
+	changes, total, err := h.priceHistoryService.GetPriceChanges(r.Context(), filter, page, pageSize)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get price changes")
+		return
+	}
+
+	utils.SuccessResponse(
+		w,
+		http.StatusOK,
+		"Price changes found",
+		changes,
+		utils.WithPagination(total, page, pageSize),
+	)
+}
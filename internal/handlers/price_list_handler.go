@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/dto"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+	"strconv"
+)
+
+type PriceListHandler struct {
+	priceListService service.PriceListService
+}
+
+func NewPriceListHandler(priceListService service.PriceListService) *PriceListHandler {
+	return &PriceListHandler{priceListService: priceListService}
+}
+
+// GetPriceLists godoc
+// @Summary Get all price lists
+// @Description Mengambil semua daftar harga (misalnya eceran, member, grosir)
+// @Tags price-lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/price-lists [get]
+func (h *PriceListHandler) GetPriceLists(w http.ResponseWriter, r *http.Request) {
+	priceLists, err := h.priceListService.GetPriceLists(r.Context())
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get price lists")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Price lists found", priceLists)
+}
+
+// GetPriceListByID godoc
+// @Summary Get price list by ID
+// @Description Mengambil daftar harga beserta harga per produk dan potongan jumlahnya
+// @Tags price-lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Price List ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/price-lists/{id} [get]
+func (h *PriceListHandler) GetPriceListByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	priceList, err := h.priceListService.GetPriceListByID(r.Context(), id)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, utils.ErrPriceListNotFound.Error())
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Price list found", priceList)
+}
+
+// CreatePriceList godoc
+// @Summary Create price list
+// @Description Membuat daftar harga. Setiap item bisa punya min_quantity untuk harga grosir, misalnya 1-11 pcs 3500 dan 12+ pcs 3200
+// @Tags price-lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param priceList body dto.PriceListRequest true "Price List Data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/price-lists [post]
+func (h *PriceListHandler) CreatePriceList(w http.ResponseWriter, r *http.Request) {
+	var req dto.PriceListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	priceList, err := h.priceListService.CreatePriceList(r.Context(), dto.PriceListReqToDomain(&req))
+	if err != nil {
+		h.writeError(w, err, "Failed to create price list")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Price list created successfully", priceList)
+}
+
+// UpdatePriceList godoc
+// @Summary Update price list
+// @Description Update daftar harga berdasarkan ID. Item yang dikirim menggantikan semua item sebelumnya
+// @Tags price-lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Price List ID"
+// @Param priceList body dto.PriceListRequest true "Price List Data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/price-lists/{id} [put]
+func (h *PriceListHandler) UpdatePriceList(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.PriceListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	priceList, err := h.priceListService.UpdatePriceList(r.Context(), id, dto.PriceListReqToDomain(&req))
+	if err != nil {
+		h.writeError(w, err, "Failed to update price list")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Price list updated successfully", priceList)
+}
+
+// DeletePriceList godoc
+// @Summary Delete price list
+// @Description Menghapus daftar harga berdasarkan ID
+// @Tags price-lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Price List ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/price-lists/{id} [delete]
+func (h *PriceListHandler) DeletePriceList(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	if err := h.priceListService.DeletePriceList(r.Context(), id); err != nil {
+		h.writeError(w, err, "failed to delete price list")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Price list deleted successfully", nil)
+}
+
+// SetCustomerPriceList godoc
+// @Summary Assign price list to customer
+// @Description Mengatur daftar harga untuk pelanggan. Kirim price_list_id null untuk kembali ke daftar harga default
+// @Tags price-lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Customer ID"
+// @Param priceList body dto.CustomerPriceListRequest true "Customer Price List"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/customers/{id}/price-list [put]
+func (h *PriceListHandler) SetCustomerPriceList(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.CustomerPriceListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	if err := h.priceListService.SetCustomerPriceList(r.Context(), customerID, req.PriceListID); err != nil {
+		h.writeError(w, err, "Failed to set customer price list")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Customer price list updated successfully", req)
+}
+
+func (h *PriceListHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, utils.ErrPriceListNotFound),
+		errors.Is(err, utils.ErrCustomerNotFound),
+		errors.Is(err, utils.ErrProductNotFound),
+		errors.Is(err, utils.ErrVariantNotFound):
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrDuplicatePriceList):
+		utils.ErrorResponse(w, http.StatusConflict, err.Error())
+	default:
+		utils.ErrorResponse(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/dto"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+	"strconv"
+)
+
+type PriceScheduleHandler struct {
+	priceScheduleService service.PriceScheduleService
+}
+
+func NewPriceScheduleHandler(priceScheduleService service.PriceScheduleService) *PriceScheduleHandler {
+	return &PriceScheduleHandler{priceScheduleService: priceScheduleService}
+}
+
+// GetScheduledPrices godoc
+// @Summary Get scheduled prices of a product
+// @Description Mengambil semua harga terjadwal produk, termasuk yang sudah diterapkan
+// @Tags price-schedules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/products/{id}/scheduled-prices [get]
+func (h *PriceScheduleHandler) GetScheduledPrices(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	prices, err := h.priceScheduleService.GetScheduledPrices(r.Context(), productID)
+	if err != nil {
+		h.writeError(w, err, "failed to get scheduled prices")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Scheduled prices found", prices)
+}
+
+// CreateScheduledPrice godoc
+// @Summary Schedule a price change
+// @Description Menjadwalkan harga baru produk yang berlaku mulai starts_at, misalnya tanggal 1 bulan depan
+// @Tags price-schedules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param price body dto.ScheduledPriceRequest true "Scheduled Price Data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/products/{id}/scheduled-prices [post]
+func (h *PriceScheduleHandler) CreateScheduledPrice(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.ScheduledPriceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	price, err := h.priceScheduleService.CreateScheduledPrice(r.Context(), dto.ScheduledPriceReqToDomain(productID, &req))
+	if err != nil {
+		h.writeError(w, err, "Failed to schedule price")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Scheduled price created successfully", price)
+}
+
+// DeleteScheduledPrice godoc
+// @Summary Cancel a scheduled price
+// @Description Membatalkan harga terjadwal yang belum diterapkan
+// @Tags price-schedules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Scheduled Price ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/scheduled-prices/{id} [delete]
+func (h *PriceScheduleHandler) DeleteScheduledPrice(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	if err := h.priceScheduleService.DeleteScheduledPrice(r.Context(), id); err != nil {
+		h.writeError(w, err, "failed to delete scheduled price")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Scheduled price deleted successfully", nil)
+}
+
+// GetPriceWindows godoc
+// @Summary Get all price windows
+// @Description Mengambil semua harga berdasarkan jam (misalnya happy hour)
+// @Tags price-schedules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/price-windows [get]
+func (h *PriceScheduleHandler) GetPriceWindows(w http.ResponseWriter, r *http.Request) {
+	windows, err := h.priceScheduleService.GetPriceWindows(r.Context())
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get price windows")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Price windows found", windows)
+}
+
+// GetPriceWindowByID godoc
+// @Summary Get price window by ID
+// @Description Mengambil harga berdasarkan jam berdasarkan ID
+// @Tags price-schedules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Price Window ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/price-windows/{id} [get]
+func (h *PriceScheduleHandler) GetPriceWindowByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	window, err := h.priceScheduleService.GetPriceWindowByID(r.Context(), id)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, utils.ErrPriceWindowNotFound.Error())
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Price window found", window)
+}
+
+// CreatePriceWindow godoc
+// @Summary Create price window
+// @Description Membuat harga berulang per jam untuk produk atau kategori. Tipe percentage memberi potongan persen, tipe price (khusus produk) menjual dengan harga tetap. days_of_week memakai 0 = Minggu sampai 6 = Sabtu, kosong berarti setiap hari
+// @Tags price-schedules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param window body dto.PriceWindowRequest true "Price Window Data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/price-windows [post]
+func (h *PriceScheduleHandler) CreatePriceWindow(w http.ResponseWriter, r *http.Request) {
+	var req dto.PriceWindowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	window, err := h.priceScheduleService.CreatePriceWindow(r.Context(), dto.PriceWindowReqToDomain(&req))
+	if err != nil {
+		h.writeError(w, err, "Failed to create price window")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Price window created successfully", window)
+}
+
+// UpdatePriceWindow godoc
+// @Summary Update price window
+// @Description Update harga berdasarkan jam berdasarkan ID
+// @Tags price-schedules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Price Window ID"
+// @Param window body dto.PriceWindowRequest true "Price Window Data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/price-windows/{id} [put]
+func (h *PriceScheduleHandler) UpdatePriceWindow(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.PriceWindowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	window, err := h.priceScheduleService.UpdatePriceWindow(r.Context(), id, dto.PriceWindowReqToDomain(&req))
+	if err != nil {
+		h.writeError(w, err, "Failed to update price window")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Price window updated successfully", window)
+}
+
+// DeletePriceWindow godoc
+// @Summary Delete price window
+// @Description Menghapus harga berdasarkan jam berdasarkan ID
+// @Tags price-schedules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Price Window ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/price-windows/{id} [delete]
+func (h *PriceScheduleHandler) DeletePriceWindow(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	if err := h.priceScheduleService.DeletePriceWindow(r.Context(), id); err != nil {
+		h.writeError(w, err, "failed to delete price window")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Price window deleted successfully", nil)
+}
+
+func (h *PriceScheduleHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, utils.ErrScheduledPriceNotFound),
+		errors.Is(err, utils.ErrPriceWindowNotFound),
+		errors.Is(err, utils.ErrProductNotFound),
+		errors.Is(err, utils.ErrCategoryNotFound):
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrInvalidPriceWindow):
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var productExportColumns = []string{"id", "name", "category_id", "category_name", "price", "cost", "stock"}
+
+type productExportWriter interface {
+	Write(product *domain.Product) error
+	Close() error
+}
+
+// ExportProducts godoc
+// @Summary Export products
+// @Description Export semua produk ke CSV, XLSX atau JSON dengan filter yang sama seperti list produk
+// @Tags products
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce json
+// @Security BearerAuth
+// @Param format query string false "Export format" Enums(csv, xlsx, json) default(csv)
+// @Param search query string false "Search by product name"
+// @Param category_id query int false "Filter by category ID"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Router /api/products/export [get]
+func (h *ProductHandler) ExportProducts(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+
+	var contentType string
+	switch format {
+	case "csv":
+		contentType = "text/csv"
+	case "xlsx":
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case "json":
+		contentType = "application/json"
+	default:
+		utils.ErrorResponse(w, http.StatusBadRequest, "format must be one of csv, xlsx, json")
+		return
+	}
+
+	writer, err := newProductExportWriter(format, w)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to export products")
+		return
+	}
+
+	filename := fmt.Sprintf("products-%s.%s", time.Now().Format("20060102-150405"), format)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	// Rows are written as they are read from the database, so once the first
+	// row is out the status is already 200 and errors can only be logged.
+	err = h.productService.ExportProducts(r.Context(), productFilterFromQuery(r), writer.Write)
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		log.Printf("failed to export products: %v", err)
+	}
+}
+
+func newProductExportWriter(format string, w io.Writer) (productExportWriter, error) {
+	switch format {
+	case "xlsx":
+		return newXLSXProductWriter(w)
+	case "json":
+		return newJSONProductWriter(w), nil
+	default:
+		return newCSVProductWriter(w)
+	}
+}
+
+func productExportRow(product *domain.Product) []interface{} {
+	return []interface{}{
+		product.ID,
+		product.Name,
+		product.Category.ID,
+		product.Category.Name,
+		product.Price,
+		product.Cost,
+		product.Stock,
+	}
+}
+
+type csvProductWriter struct {
+	w     *csv.Writer
+	flush http.Flusher
+}
+
+func newCSVProductWriter(w io.Writer) (*csvProductWriter, error) {
+	cw := &csvProductWriter{w: csv.NewWriter(w)}
+	cw.flush, _ = w.(http.Flusher)
+	return cw, cw.w.Write(productExportColumns)
+}
+
+func (c *csvProductWriter) Write(product *domain.Product) error {
+	row := productExportRow(product)
+	record := make([]string, len(row))
+	for i, value := range row {
+		record[i] = fmt.Sprint(value)
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvProductWriter) Close() error {
+	c.w.Flush()
+	if c.flush != nil {
+		c.flush.Flush()
+	}
+	return c.w.Error()
+}
+
+type jsonProductWriter struct {
+	w     io.Writer
+	enc   *json.Encoder
+	count int
+}
+
+func newJSONProductWriter(w io.Writer) *jsonProductWriter {
+	return &jsonProductWriter{w: w, enc: json.NewEncoder(w)}
+}
+
+func (j *jsonProductWriter) Write(product *domain.Product) error {
+	sep := ","
+	if j.count == 0 {
+		sep = "["
+	}
+	if _, err := io.WriteString(j.w, sep); err != nil {
+		return err
+	}
+	j.count++
+	return j.enc.Encode(product)
+}
+
+func (j *jsonProductWriter) Close() error {
+	closing := "]\n"
+	if j.count == 0 {
+		closing = "[]\n"
+	}
+	_, err := io.WriteString(j.w, closing)
+	return err
+}
+
+type xlsxProductWriter struct {
+	w      io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXProductWriter(w io.Writer) (*xlsxProductWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter("Sheet1")
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	header := make([]interface{}, len(productExportColumns))
+	for i, column := range productExportColumns {
+		header[i] = column
+	}
+	if err := stream.SetRow("A1", header); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &xlsxProductWriter{w: w, file: file, stream: stream, row: 1}, nil
+}
+
+func (x *xlsxProductWriter) Write(product *domain.Product) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	return x.stream.SetRow(cell, productExportRow(product))
+}
+
+func (x *xlsxProductWriter) Close() error {
+	defer x.file.Close()
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	_, err := x.file.WriteTo(x.w)
+	return err
+}
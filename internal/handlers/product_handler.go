@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
+	domain "kasir-api/internal/domains"
 	"kasir-api/internal/dto"
 	service "kasir-api/internal/services"
 	"kasir-api/internal/utils"
@@ -21,12 +22,16 @@ func NewProductHandler(productService service.ProductService) *ProductHandler {
 
 // GetAllProducts godoc
 // @Summary Get all products
-// @Description Mengambil semua data produk
+// @Description Mengambil semua data produk beserta harga yang berlaku saat ini (current_price) dan harga terjadwal berikutnya (upcoming_prices)
 // @Tags products
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Page size" default(10)
+// @Param search query string false "Search by product name"
+// @Param category_id query int false "Filter by category ID"
+// @Param include query string false "Set to variants to nest product variants" Enums(variants)
 // @Success 200 {object} map[string]interface{}
 // @Router /api/products [get]
 func (h *ProductHandler) GetProducts(w http.ResponseWriter, r *http.Request) {
@@ -39,7 +44,10 @@ func (h *ProductHandler) GetProducts(w http.ResponseWriter, r *http.Request) {
 		pageSize = 10
 	}
 
-	products, total, err := h.productService.GetProducts(r.Context(), page, pageSize)
+	filter := productFilterFromQuery(r)
+	filter.IncludeVariants = r.URL.Query().Get("include") == "variants"
+
+	products, total, err := h.productService.GetProducts(r.Context(), filter, page, pageSize)
 	if err != nil {
 		utils.ErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -53,14 +61,29 @@ func (h *ProductHandler) GetProducts(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+func productFilterFromQuery(r *http.Request) domain.ProductFilter {
+	query := r.URL.Query()
+	filter := domain.ProductFilter{
+		Search: strings.TrimSpace(query.Get("search")),
+	}
+	if categoryID, err := strconv.Atoi(query.Get("category_id")); err == nil && categoryID > 0 {
+		filter.CategoryID = categoryID
+	}
+	return filter
+}
+
 // GetProductByID godoc
 // @Summary Get product by ID
 // @Description Mengambil produk berdasarkan ID
 // @Tags products
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path int true "Product ID"
+// @Param include query string false "Set to variants to nest variant options and variants" Enums(variants)
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} map[string]interface{}
+// @Success 304 "Not Modified"
 // @Failure 404 {object} map[string]string
 // @Router /api/products/{id} [get]
 func (h *ProductHandler) GetProductByID(w http.ResponseWriter, r *http.Request) {
@@ -70,12 +93,21 @@ func (h *ProductHandler) GetProductByID(w http.ResponseWriter, r *http.Request)
 		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
 		return
 	}
-	product, err := h.productService.GetProductByID(r.Context(), idInt)
+	includeVariants := r.URL.Query().Get("include") == "variants"
+	product, err := h.productService.GetProductByID(r.Context(), idInt, includeVariants)
 	if err != nil {
 		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get product")
 		return
 	}
 
+	// the product version does not cover its variants, the stock of its
+	// components, nor prices that change with price windows or scheduled
+	// prices, so those responses are never served conditionally
+	conditional := !includeVariants && !product.IsComposite() && !product.TimedPrice
+	if conditional && utils.NotModified(w, r, product.Version) {
+		return
+	}
+
 	utils.SuccessResponse(w, http.StatusOK, "Product found", product)
 }
 
@@ -85,6 +117,7 @@ func (h *ProductHandler) GetProductByID(w http.ResponseWriter, r *http.Request)
 // @Tags products
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param product body dto.ProductRequest true "Product Data"
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
@@ -117,14 +150,24 @@ func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 // @Tags products
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path int true "Product ID"
+// @Param If-Match header string false "ETag of the version being updated"
 // @Param product body dto.ProductRequest true "Product Data"
 // @Success 200 {object} map[string]interface{}
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
 // @Router /api/products/{id} [put]
 func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 	id := strings.TrimPrefix(r.URL.Path, "/api/products/")
 	idInt, _ := strconv.Atoi(id)
 
+	versions, err := utils.IfMatchVersions(r)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	var req dto.ProductRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
@@ -137,29 +180,116 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 	}
 
 	product := dto.ProductReqToDomain(&req)
-	updatedProduct, err := h.productService.UpdateProduct(r.Context(), idInt, product)
+	updatedProduct, err := h.productService.UpdateProduct(r.Context(), idInt, product, versions)
 	if err != nil {
 		if errors.Is(err, utils.ErrProductNotFound) {
 			utils.ErrorResponse(w, http.StatusNotFound, utils.ErrProductNotFound.Error())
 			return
 		}
+		if errors.Is(err, utils.ErrNestedComposite) || errors.Is(err, utils.ErrCompositeVariants) {
+			utils.ErrorResponse(w, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrVersionMismatch) {
+			utils.ErrorResponse(w, http.StatusPreconditionFailed, utils.ErrVersionMismatch.Error())
+			return
+		}
 		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to update product")
 		return
 	}
 
 	updatedProduct.ID = idInt
+	utils.SetETag(w, updatedProduct.Version)
 
 	utils.SuccessResponse(w, http.StatusOK, "Product updated successfully", updatedProduct)
 }
 
+// PatchProduct godoc
+// @Summary Partially update product
+// @Description Update sebagian field produk menggunakan JSON Merge Patch (RFC 7396). Hanya field yang dikirim yang divalidasi dan diubah
+// @Tags products
+// @Accept application/merge-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param If-Match header string false "ETag of the version being updated"
+// @Param product body dto.ProductPatchRequest true "Product Merge Patch"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Router /api/products/{id} [patch]
+func (h *ProductHandler) PatchProduct(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/api/products/")
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	versions, err := utils.IfMatchVersions(r)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req dto.ProductPatchRequest
+	nulls, err := utils.DecodeMergePatch(r, &req)
+	if err != nil {
+		if errors.Is(err, utils.ErrUnsupportedMediaType) {
+			utils.ErrorResponse(w, http.StatusUnsupportedMediaType, err.Error())
+			return
+		}
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if fieldErrors := utils.NullFieldErrors(nulls, "name", "type", "price", "cost", "stock", "base_unit"); len(fieldErrors) > 0 {
+		utils.FieldErrorsResponse(w, fieldErrors)
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	product, err := h.productService.PatchProduct(r.Context(), idInt, dto.ProductPatchReqToDomain(&req), versions)
+	if err != nil {
+		if errors.Is(err, utils.ErrProductNotFound) {
+			utils.ErrorResponse(w, http.StatusNotFound, utils.ErrProductNotFound.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrNestedComposite) || errors.Is(err, utils.ErrCompositeVariants) {
+			utils.ErrorResponse(w, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrVersionMismatch) {
+			utils.ErrorResponse(w, http.StatusPreconditionFailed, utils.ErrVersionMismatch.Error())
+			return
+		}
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to update product")
+		return
+	}
+
+	utils.SetETag(w, product.Version)
+	utils.SuccessResponse(w, http.StatusOK, "Product updated successfully", product)
+}
+
 // DeleteProduct godoc
 // @Summary Delete product
 // @Description Menghapus produk berdasarkan ID
 // @Tags products
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path int true "Product ID"
+// @Param If-Match header string false "ETag of the version being deleted"
 // @Success 200 {object} map[string]interface{}
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
 // @Router /api/products/{id} [delete]
 func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 	id := strings.TrimPrefix(r.URL.Path, "/api/products/")
@@ -168,14 +298,152 @@ func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
 		return
 	}
-	if err := h.productService.DeleteProduct(r.Context(), idInt); err != nil {
+
+	versions, err := utils.IfMatchVersions(r)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.productService.DeleteProduct(r.Context(), idInt, versions); err != nil {
 		if errors.Is(err, utils.ErrProductNotFound) {
 			utils.ErrorResponse(w, http.StatusNotFound, utils.ErrProductNotFound.Error())
 			return
 		}
+		if errors.Is(err, utils.ErrProductInUse) {
+			utils.ErrorResponse(w, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.Is(err, utils.ErrVersionMismatch) {
+			utils.ErrorResponse(w, http.StatusPreconditionFailed, utils.ErrVersionMismatch.Error())
+			return
+		}
 		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to delete product")
 		return
 	}
 
 	utils.SuccessResponse(w, http.StatusOK, "Product deleted successfully", nil)
 }
+
+// BulkUpdateProducts godoc
+// @Summary Bulk update product price and stock
+// @Description Mengubah harga (persentase atau nominal) dan stok banyak produk sekaligus berdasarkan ID, kategori atau pencarian. Gunakan preview untuk melihat hasil tanpa menyimpan
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.BulkProductUpdateRequest true "Bulk Update Data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /api/products/bulk-update [post]
+func (h *ProductHandler) BulkUpdateProducts(w http.ResponseWriter, r *http.Request) {
+	var req dto.BulkProductUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	result, err := h.productService.BulkUpdateProducts(r.Context(), dto.BulkProductUpdateReqToDomain(&req))
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrBulkUpdateNoSelector),
+			errors.Is(err, utils.ErrBulkUpdateNoChange),
+			errors.Is(err, utils.ErrNegativePrice),
+			errors.Is(err, utils.ErrNegativeStock):
+			utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		default:
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to bulk update products")
+		}
+		return
+	}
+
+	message := "Products updated successfully"
+	if result.Preview {
+		message = "Bulk update preview"
+	}
+	utils.SuccessResponse(w, http.StatusOK, message, result)
+}
+
+// GetComponents godoc
+// @Summary Get product components
+// @Description Mengambil daftar komponen (bundle atau resep) beserta jumlah dan stoknya
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/products/{id}/components [get]
+func (h *ProductHandler) GetComponents(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	components, err := h.productService.GetComponents(r.Context(), id)
+	if err != nil {
+		h.writeComponentError(w, err, "failed to get components")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Components found", components)
+}
+
+// ReplaceComponents godoc
+// @Summary Replace product components
+// @Description Mengganti daftar komponen produk bundle atau resep. Komponen harus produk standard
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param components body dto.ProductComponentsRequest true "Components"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/products/{id}/components [put]
+func (h *ProductHandler) ReplaceComponents(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.ProductComponentsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	components, err := h.productService.ReplaceComponents(r.Context(), id, dto.ProductComponentsReqToDomain(&req))
+	if err != nil {
+		h.writeComponentError(w, err, "Failed to update components")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Components updated successfully", components)
+}
+
+func (h *ProductHandler) writeComponentError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, utils.ErrProductNotFound):
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrNotComposite), errors.Is(err, utils.ErrInvalidComponent):
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(w, http.StatusInternalServerError, message)
+	}
+}
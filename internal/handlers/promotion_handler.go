@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/dto"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+	"strconv"
+)
+
+type PromotionHandler struct {
+	promotionService service.PromotionService
+}
+
+func NewPromotionHandler(promotionService service.PromotionService) *PromotionHandler {
+	return &PromotionHandler{promotionService: promotionService}
+}
+
+// GetPromotions godoc
+// @Summary Get all promotions
+// @Description Mengambil semua aturan promo
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/promotions [get]
+func (h *PromotionHandler) GetPromotions(w http.ResponseWriter, r *http.Request) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = 10
+	}
+
+	promotions, total, err := h.promotionService.GetPromotions(r.Context(), page, pageSize)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get promotions")
+		return
+	}
+
+	utils.SuccessResponse(
+		w,
+		http.StatusOK,
+		"Promotions found",
+		promotions,
+		utils.WithPagination(total, page, pageSize),
+	)
+}
+
+// GetPromotionByID godoc
+// @Summary Get promotion by ID
+// @Description Mengambil aturan promo berdasarkan ID
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/promotions/{id} [get]
+func (h *PromotionHandler) GetPromotionByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	promotion, err := h.promotionService.GetPromotionByID(r.Context(), id)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, utils.ErrPromotionNotFound.Error())
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Promotion found", promotion)
+}
+
+// CreatePromotion godoc
+// @Summary Create promotion
+// @Description Membuat promo diskon persentase/nominal per item atau keranjang, beli X gratis Y, harga paket, dan minimal belanja
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param promotion body dto.PromotionRequest true "Promotion Data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/promotions [post]
+func (h *PromotionHandler) CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	var req dto.PromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	promotion, err := h.promotionService.CreatePromotion(r.Context(), dto.PromotionReqToDomain(&req))
+	if err != nil {
+		h.writeError(w, err, "Failed to create promotion")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Promotion created successfully", promotion)
+}
+
+// UpdatePromotion godoc
+// @Summary Update promotion
+// @Description Update aturan promo berdasarkan ID
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Promotion ID"
+// @Param promotion body dto.PromotionRequest true "Promotion Data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/promotions/{id} [put]
+func (h *PromotionHandler) UpdatePromotion(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.PromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	promotion, err := h.promotionService.UpdatePromotion(r.Context(), id, dto.PromotionReqToDomain(&req))
+	if err != nil {
+		h.writeError(w, err, "Failed to update promotion")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Promotion updated successfully", promotion)
+}
+
+// DeletePromotion godoc
+// @Summary Delete promotion
+// @Description Menghapus promo berdasarkan ID. Diskon pada transaksi lama tetap tersimpan
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/promotions/{id} [delete]
+func (h *PromotionHandler) DeletePromotion(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	if err := h.promotionService.DeletePromotion(r.Context(), id); err != nil {
+		h.writeError(w, err, "failed to delete promotion")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Promotion deleted successfully", nil)
+}
+
+func (h *PromotionHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, utils.ErrPromotionNotFound),
+		errors.Is(err, utils.ErrProductNotFound),
+		errors.Is(err, utils.ErrCategoryNotFound):
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrInvalidPromotion):
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/dto"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+	"strconv"
+)
+
+type PurchaseHandler struct {
+	purchaseService service.PurchaseService
+}
+
+func NewPurchaseHandler(purchaseService service.PurchaseService) *PurchaseHandler {
+	return &PurchaseHandler{purchaseService: purchaseService}
+}
+
+// CreatePurchase godoc
+// @Summary Create purchase
+// @Description Mencatat pembelian dari supplier dan menambah stok dalam satuan dasar produk
+// @Tags purchases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param purchase body dto.PurchaseRequest true "Purchase Data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/purchases [post]
+func (h *PurchaseHandler) CreatePurchase(w http.ResponseWriter, r *http.Request) {
+	var req dto.PurchaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	purchase, err := h.purchaseService.CreatePurchase(r.Context(), req.Supplier, dto.PurchaseReqToDomain(&req))
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrProductNotFound),
+			errors.Is(err, utils.ErrVariantNotFound),
+			errors.Is(err, utils.ErrUnitNotFound):
+			utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, utils.ErrVariantRequired), errors.Is(err, utils.ErrCompositeStock):
+			utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		default:
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to create purchase")
+		}
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Purchase created successfully", purchase)
+}
+
+// GetPurchases godoc
+// @Summary Get all purchases
+// @Description Mengambil semua data pembelian
+// @Tags purchases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/purchases [get]
+func (h *PurchaseHandler) GetPurchases(w http.ResponseWriter, r *http.Request) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = 10
+	}
+
+	purchases, total, err := h.purchaseService.GetPurchases(r.Context(), page, pageSize)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get purchases")
+		return
+	}
+
+	utils.SuccessResponse(
+		w,
+		http.StatusOK,
+		"Purchases found",
+		purchases,
+		utils.WithPagination(total, page, pageSize),
+	)
+}
+
+// GetPurchaseByID godoc
+// @Summary Get purchase by ID
+// @Description Mengambil pembelian beserta item berdasarkan ID
+// @Tags purchases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Purchase ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/purchases/{id} [get]
+func (h *PurchaseHandler) GetPurchaseByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	purchase, err := h.purchaseService.GetPurchaseByID(r.Context(), id)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, utils.ErrPurchaseNotFound.Error())
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Purchase found", purchase)
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/dto"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type ReceivableHandler struct {
+	receivableService service.ReceivableService
+}
+
+func NewReceivableHandler(receivableService service.ReceivableService) *ReceivableHandler {
+	return &ReceivableHandler{receivableService: receivableService}
+}
+
+// SetCreditLimit godoc
+// @Summary Set customer credit limit
+// @Description Mengatur batas kasbon pelanggan. 0 berarti pelanggan tidak boleh belanja dengan kasbon
+// @Tags receivables
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Customer ID"
+// @Param limit body dto.CreditLimitRequest true "Credit Limit"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/customers/{id}/credit-limit [put]
+func (h *ReceivableHandler) SetCreditLimit(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.CreditLimitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	if err := h.receivableService.SetCreditLimit(r.Context(), customerID, req.CreditLimit); err != nil {
+		h.writeError(w, err, "Failed to set credit limit")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Credit limit updated successfully", req)
+}
+
+// GetReceivableAccount godoc
+// @Summary Get customer receivables
+// @Description Mengambil saldo kasbon pelanggan, sisa limit, transaksi yang belum lunas dan riwayat kasbon, terbaru di atas
+// @Tags receivables
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Customer ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/customers/{id}/receivables [get]
+func (h *ReceivableHandler) GetReceivableAccount(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = 10
+	}
+
+	account, total, err := h.receivableService.GetReceivableAccount(r.Context(), customerID, page, pageSize)
+	if err != nil {
+		h.writeError(w, err, "failed to get receivables")
+		return
+	}
+
+	utils.SuccessResponse(
+		w,
+		http.StatusOK,
+		"Receivables found",
+		account,
+		utils.WithPagination(total, page, pageSize),
+	)
+}
+
+// RecordRepayment godoc
+// @Summary Record a repayment
+// @Description Mencatat pembayaran kasbon pelanggan untuk transaksi tertentu. Tanpa transaction_id, pembayaran melunasi transaksi terlama lebih dulu
+// @Tags receivables
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Customer ID"
+// @Param repayment body dto.RepaymentRequest true "Repayment Data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/customers/{id}/repayments [post]
+func (h *ReceivableHandler) RecordRepayment(w http.ResponseWriter, r *http.Request) {
+	customerID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.RepaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	entries, err := h.receivableService.RecordRepayment(r.Context(), dto.RepaymentReqToDomain(customerID, &req))
+	if err != nil {
+		h.writeError(w, err, "Failed to record repayment")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Repayment created successfully", entries)
+}
+
+// GetAgingReport godoc
+// @Summary Receivables aging report
+// @Description Laporan umur piutang kasbon per pelanggan (0-30, 31-60, 61-90 dan lebih dari 90 hari) per akhir tanggal as_of (YYYY-MM-DD), default hari ini
+// @Tags receivables
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param as_of query string false "Report date (YYYY-MM-DD)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/reports/receivables-aging [get]
+func (h *ReceivableHandler) GetAgingReport(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	asOf := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if value := r.URL.Query().Get("as_of"); value != "" {
+		date, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+		if err != nil {
+			utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+			return
+		}
+		asOf = date
+	}
+
+	report, err := h.receivableService.GetAgingReport(r.Context(), asOf)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get aging report")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Aging report found", report)
+}
+
+func (h *ReceivableHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, utils.ErrCustomerNotFound), errors.Is(err, utils.ErrReceivableNotFound):
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrRepaymentTooLarge):
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(w, http.StatusInternalServerError, message)
+	}
+}
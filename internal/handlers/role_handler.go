@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/dto"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+	"strconv"
+)
+
+type RoleHandler struct {
+	roleService service.RoleService
+}
+
+func NewRoleHandler(roleService service.RoleService) *RoleHandler {
+	return &RoleHandler{roleService: roleService}
+}
+
+// GetPermissions godoc
+// @Summary Get all permissions
+// @Description Mengambil daftar permission yang bisa diberikan ke role
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/permissions [get]
+func (h *RoleHandler) GetPermissions(w http.ResponseWriter, r *http.Request) {
+	utils.SuccessResponse(w, http.StatusOK, "Permissions found", h.roleService.GetPermissions())
+}
+
+// GetRoles godoc
+// @Summary Get all roles
+// @Description Mengambil semua role beserta permission dan jumlah user yang memakainya
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/roles [get]
+func (h *RoleHandler) GetRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.roleService.GetRoles(r.Context())
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get roles")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Roles found", roles)
+}
+
+// GetRoleByID godoc
+// @Summary Get role by ID
+// @Description Mengambil role berdasarkan ID
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Role ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/roles/{id} [get]
+func (h *RoleHandler) GetRoleByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	role, err := h.roleService.GetRoleByID(r.Context(), id)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, utils.ErrRoleNotFound.Error())
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Role found", role)
+}
+
+// CreateRole godoc
+// @Summary Create role
+// @Description Membuat role baru dengan daftar permission
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param role body dto.RoleRequest true "Role Data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/roles [post]
+func (h *RoleHandler) CreateRole(w http.ResponseWriter, r *http.Request) {
+	var req dto.RoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	role, err := h.roleService.CreateRole(r.Context(), dto.RoleReqToDomain(&req))
+	if err != nil {
+		h.writeError(w, err, "Failed to create role")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Role created successfully", role)
+}
+
+// UpdateRole godoc
+// @Summary Update role
+// @Description Update nama, deskripsi dan permission role. Berlaku langsung untuk semua user yang memiliki role ini
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Role ID"
+// @Param role body dto.RoleRequest true "Role Data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/roles/{id} [put]
+func (h *RoleHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.RoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	role, err := h.roleService.UpdateRole(r.Context(), id, dto.RoleReqToDomain(&req))
+	if err != nil {
+		h.writeError(w, err, "Failed to update role")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Role updated successfully", role)
+}
+
+// DeleteRole godoc
+// @Summary Delete role
+// @Description Menghapus role. Role yang masih dimiliki user tidak bisa dihapus
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Role ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/roles/{id} [delete]
+func (h *RoleHandler) DeleteRole(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	if err := h.roleService.DeleteRole(r.Context(), id); err != nil {
+		h.writeError(w, err, "failed to delete role")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Role deleted successfully", nil)
+}
+
+// SetUserRoles godoc
+// @Summary Set user roles
+// @Description Mengganti semua role user dengan role yang disebutkan namanya
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param roles body dto.UserRolesRequest true "Role Names"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/users/{id}/roles [put]
+func (h *RoleHandler) SetUserRoles(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.UserRolesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	user, err := h.roleService.SetUserRoles(r.Context(), id, req.Roles)
+	if err != nil {
+		h.writeError(w, err, "Failed to set user roles")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "User roles updated successfully", user)
+}
+
+func (h *RoleHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, utils.ErrRoleNotFound), errors.Is(err, utils.ErrUserNotFound):
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrUnknownPermission):
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, utils.ErrDuplicateRole), errors.Is(err, utils.ErrRoleInUse):
+		utils.ErrorResponse(w, http.StatusConflict, err.Error())
+	default:
+		utils.ErrorResponse(w, http.StatusInternalServerError, message)
+	}
+}
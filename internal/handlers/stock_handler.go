@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+	"strconv"
+)
+
+type StockHandler struct {
+	stockService service.StockService
+}
+
+func NewStockHandler(stockService service.StockService) *StockHandler {
+	return &StockHandler{stockService: stockService}
+}
+
+// GetMovements godoc
+// @Summary Get stock movements
+// @Description Mengambil riwayat mutasi stok (ledger) sebuah produk
+// @Tags stock
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/products/{id}/stock-movements [get]
+func (h *StockHandler) GetMovements(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = 10
+	}
+
+	movements, total, err := h.stockService.GetMovements(r.Context(), productID, page, pageSize)
+	if err != nil {
+		if errors.Is(err, utils.ErrProductNotFound) {
+			utils.ErrorResponse(w, http.StatusNotFound, utils.ErrProductNotFound.Error())
+			return
+		}
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get stock movements")
+		return
+	}
+
+	utils.SuccessResponse(
+		w,
+		http.StatusOK,
+		"Stock movements found",
+		movements,
+		utils.WithPagination(total, page, pageSize),
+	)
+}
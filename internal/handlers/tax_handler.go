@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/dto"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+	"strconv"
+)
+
+type TaxHandler struct {
+	taxService service.TaxService
+}
+
+func NewTaxHandler(taxService service.TaxService) *TaxHandler {
+	return &TaxHandler{taxService: taxService}
+}
+
+// GetTaxRates godoc
+// @Summary Get all tax rates
+// @Description Mengambil semua tarif pajak, misalnya PPN 11%
+// @Tags tax
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/tax-rates [get]
+func (h *TaxHandler) GetTaxRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.taxService.GetTaxRates(r.Context())
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get tax rates")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Tax rates found", rates)
+}
+
+// CreateTaxRate godoc
+// @Summary Create tax rate
+// @Description Membuat tarif pajak baru (dalam persen)
+// @Tags tax
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param rate body dto.TaxRateRequest true "Tax Rate Data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/tax-rates [post]
+func (h *TaxHandler) CreateTaxRate(w http.ResponseWriter, r *http.Request) {
+	var req dto.TaxRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	rate, err := h.taxService.CreateTaxRate(r.Context(), dto.TaxRateReqToDomain(&req))
+	if err != nil {
+		h.writeError(w, err, "Failed to create tax rate")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Tax rate created successfully", rate)
+}
+
+// UpdateTaxRate godoc
+// @Summary Update tax rate
+// @Description Update tarif pajak. Transaksi lama tetap menyimpan tarif saat transaksi terjadi
+// @Tags tax
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Tax Rate ID"
+// @Param rate body dto.TaxRateRequest true "Tax Rate Data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/tax-rates/{id} [put]
+func (h *TaxHandler) UpdateTaxRate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.TaxRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	rate, err := h.taxService.UpdateTaxRate(r.Context(), id, dto.TaxRateReqToDomain(&req))
+	if err != nil {
+		h.writeError(w, err, "Failed to update tax rate")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Tax rate updated successfully", rate)
+}
+
+// DeleteTaxRate godoc
+// @Summary Delete tax rate
+// @Description Menghapus tarif pajak. Produk dan kategori yang memakainya kembali ke tarif default
+// @Tags tax
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Tax Rate ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/tax-rates/{id} [delete]
+func (h *TaxHandler) DeleteTaxRate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	if err := h.taxService.DeleteTaxRate(r.Context(), id); err != nil {
+		h.writeError(w, err, "failed to delete tax rate")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Tax rate deleted successfully", nil)
+}
+
+// GetStoreSettings godoc
+// @Summary Get tax settings
+// @Description Mengambil pengaturan pajak toko: harga termasuk/belum termasuk pajak, tarif default dan service charge
+// @Tags tax
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/settings/tax [get]
+func (h *TaxHandler) GetStoreSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.taxService.GetStoreSettings(r.Context())
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get settings")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Settings found", settings)
+}
+
+// UpdateStoreSettings godoc
+// @Summary Update tax settings
+// @Description Update pengaturan pajak toko. service_charge_order before_tax berarti service charge ikut dikenai pajak
+// @Tags tax
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param settings body dto.StoreSettingsRequest true "Tax Settings"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/settings/tax [put]
+func (h *TaxHandler) UpdateStoreSettings(w http.ResponseWriter, r *http.Request) {
+	var req dto.StoreSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	settings, err := h.taxService.UpdateStoreSettings(r.Context(), dto.StoreSettingsReqToDomain(&req))
+	if err != nil {
+		h.writeError(w, err, "Failed to update settings")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Settings updated successfully", settings)
+}
+
+// SetProductTax godoc
+// @Summary Set product tax
+// @Description Mengatur tarif pajak produk atau menandainya bebas pajak. Tanpa tarif, produk memakai tarif kategori lalu tarif default toko
+// @Tags tax
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param tax body dto.ProductTaxRequest true "Product Tax"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/products/{id}/tax [put]
+func (h *TaxHandler) SetProductTax(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.ProductTaxRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	tax := dto.ProductTaxReqToDomain(&req)
+	if err := h.taxService.SetProductTax(r.Context(), productID, tax); err != nil {
+		h.writeError(w, err, "Failed to set product tax")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Product tax updated successfully", tax)
+}
+
+// SetCategoryTax godoc
+// @Summary Set category tax
+// @Description Mengatur tarif pajak untuk semua produk dalam kategori yang tidak punya tarif sendiri
+// @Tags tax
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Category ID"
+// @Param tax body dto.CategoryTaxRequest true "Category Tax"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/categories/{id}/tax [put]
+func (h *TaxHandler) SetCategoryTax(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.CategoryTaxRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	if err := h.taxService.SetCategoryTax(r.Context(), categoryID, req.TaxRateID); err != nil {
+		h.writeError(w, err, "Failed to set category tax")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Category tax updated successfully", req)
+}
+
+func (h *TaxHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, utils.ErrTaxRateNotFound),
+		errors.Is(err, utils.ErrProductNotFound),
+		errors.Is(err, utils.ErrCategoryNotFound):
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+	default:
+		utils.ErrorResponse(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/dto"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+)
+
+type TenantHandler struct {
+	tenantService service.TenantService
+}
+
+func NewTenantHandler(tenantService service.TenantService) *TenantHandler {
+	return &TenantHandler{tenantService: tenantService}
+}
+
+// GetTenants godoc
+// @Summary Get all tenants
+// @Description Mengambil semua tenant (toko) di deployment ini. Hanya bisa dari tenant default
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/tenants [get]
+func (h *TenantHandler) GetTenants(w http.ResponseWriter, r *http.Request) {
+	tenants, err := h.tenantService.GetTenants(r.Context())
+	if err != nil {
+		h.writeError(w, err, "failed to get tenants")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Tenants found", tenants)
+}
+
+// CreateTenant godoc
+// @Summary Create tenant
+// @Description Membuat tenant (toko) baru beserta role bawaan, pengaturan default dan user owner pertamanya. Data setiap tenant terpisah dan tidak bisa dibaca tenant lain. Hanya bisa dari tenant default
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tenant body dto.TenantRequest true "Tenant Data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/tenants [post]
+func (h *TenantHandler) CreateTenant(w http.ResponseWriter, r *http.Request) {
+	var req dto.TenantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	tenant, owner := dto.TenantReqToDomain(&req)
+	tenant, err := h.tenantService.CreateTenant(r.Context(), tenant, owner, req.OwnerPassword)
+	if err != nil {
+		h.writeError(w, err, "Failed to create tenant")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Tenant created successfully", tenant)
+}
+
+func (h *TenantHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, utils.ErrDefaultTenantOnly):
+		utils.ErrorResponse(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, utils.ErrDuplicateTenant), errors.Is(err, utils.ErrDuplicateUser):
+		utils.ErrorResponse(w, http.StatusConflict, err.Error())
+	default:
+		utils.ErrorResponse(w, http.StatusInternalServerError, message)
+	}
+}
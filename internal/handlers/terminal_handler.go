@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/dto"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+	"strconv"
+)
+
+type TerminalHandler struct {
+	terminalService service.TerminalService
+}
+
+func NewTerminalHandler(terminalService service.TerminalService) *TerminalHandler {
+	return &TerminalHandler{terminalService: terminalService}
+}
+
+// GetTerminals godoc
+// @Summary Get all terminals
+// @Description Mengambil semua terminal kasir yang terdaftar beserta waktu terakhir dipakai login PIN
+// @Tags terminals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/terminals [get]
+func (h *TerminalHandler) GetTerminals(w http.ResponseWriter, r *http.Request) {
+	terminals, err := h.terminalService.GetTerminals(r.Context())
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get terminals")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Terminals found", terminals)
+}
+
+// CreateTerminal godoc
+// @Summary Register terminal
+// @Description Mendaftarkan terminal kasir. Key terminal hanya ditampilkan sekali di response ini dan harus disimpan di terminal untuk dikirim lewat header X-Terminal-Key
+// @Tags terminals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param terminal body dto.TerminalRequest true "Terminal Data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/terminals [post]
+func (h *TerminalHandler) CreateTerminal(w http.ResponseWriter, r *http.Request) {
+	var req dto.TerminalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	terminal, err := h.terminalService.CreateTerminal(r.Context(), dto.TerminalReqToDomain(&req))
+	if err != nil {
+		h.writeError(w, err, "Failed to create terminal")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Terminal created successfully", terminal)
+}
+
+// UpdateTerminal godoc
+// @Summary Update terminal
+// @Description Update nama terminal atau menonaktifkannya. Terminal nonaktif tidak bisa dipakai login PIN dan kasir yang aktif di terminal itu langsung logout
+// @Tags terminals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Terminal ID"
+// @Param terminal body dto.TerminalRequest true "Terminal Data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/terminals/{id} [put]
+func (h *TerminalHandler) UpdateTerminal(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.TerminalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	terminal, err := h.terminalService.UpdateTerminal(r.Context(), id, dto.TerminalReqToDomain(&req))
+	if err != nil {
+		h.writeError(w, err, "Failed to update terminal")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Terminal updated successfully", terminal)
+}
+
+func (h *TerminalHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, utils.ErrTerminalNotFound):
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrDuplicateTerminal):
+		utils.ErrorResponse(w, http.StatusConflict, err.Error())
+	default:
+		utils.ErrorResponse(w, http.StatusInternalServerError, message)
+	}
+}
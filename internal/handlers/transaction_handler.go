@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/dto"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+	"strconv"
+)
+
+type TransactionHandler struct {
+	transactionService service.TransactionService
+}
+
+func NewTransactionHandler(transactionService service.TransactionService) *TransactionHandler {
+	return &TransactionHandler{transactionService: transactionService}
+}
+
+// Checkout godoc
+// @Summary Checkout
+// @Description Membuat transaksi penjualan, menerapkan promo dan voucher, menjual atau top up gift card, mencatat pembayaran tunai/poin/kasbon (on_account)/gift card beserta kembalian, memberi poin loyalitas, lalu mengurangi stok produk, varian, komponen dan bahan modifier. Tanpa payments, total dianggap dibayar tunai pas. Kasir yang login (dan terminalnya untuk sesi PIN) dicatat di transaksi. Harga override (price_override) dan diskon manual di atas batas pengaturan butuh persetujuan supervisor: tanpa izin, respon 403 berisi approval_required dan daftar actions, lalu request diulang dengan approval grant dari supervisor (X-Approval-Token, dari POST /api/auth/approval-grants, sekali pakai) atau username dan PIN supervisor (X-Approver, X-Approver-PIN). Peminta dan penyetuju dicatat di approvals
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param checkout body dto.CheckoutRequest true "Checkout Data"
+// @Param X-Approval-Token header string false "Approval grant token issued by the supervisor"
+// @Param X-Approver header string false "Username of the approving supervisor"
+// @Param X-Approver-PIN header string false "PIN of the approving supervisor"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 423 {object} map[string]string
+// @Router /api/transactions/checkout [post]
+func (h *TransactionHandler) Checkout(w http.ResponseWriter, r *http.Request) {
+	var req dto.CheckoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	transaction, err := h.transactionService.Checkout(r.Context(), dto.CheckoutReqToDomain(&req))
+	if err != nil {
+		var approval *utils.ApprovalRequiredError
+		switch {
+		case errors.As(err, &approval):
+			utils.ApprovalRequiredResponse(w, approval)
+		case errors.Is(err, utils.ErrApprovalGrantUsed):
+			utils.ErrorResponse(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, utils.ErrProductNotFound),
+			errors.Is(err, utils.ErrVariantNotFound),
+			errors.Is(err, utils.ErrUnitNotFound),
+			errors.Is(err, utils.ErrVoucherNotFound),
+			errors.Is(err, utils.ErrCustomerNotFound),
+			errors.Is(err, utils.ErrGiftCardNotFound):
+			utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, utils.ErrVariantRequired),
+			errors.Is(err, utils.ErrInvalidModifier),
+			errors.Is(err, utils.ErrModifierSelection),
+			errors.Is(err, utils.ErrVoucherInactive),
+			errors.Is(err, utils.ErrVoucherMinPurchase),
+			errors.Is(err, utils.ErrVoucherCustomerRequired),
+			errors.Is(err, utils.ErrLoyaltyDisabled),
+			errors.Is(err, utils.ErrLoyaltyCustomerRequired),
+			errors.Is(err, utils.ErrMinRedeemPoints),
+			errors.Is(err, utils.ErrInvalidPayment),
+			errors.Is(err, utils.ErrInsufficientPayment),
+			errors.Is(err, utils.ErrCreditCustomerRequired),
+			errors.Is(err, utils.ErrGiftCardInactive),
+			errors.Is(err, utils.ErrInvalidDiscount),
+			errors.Is(err, utils.ErrEmptyCheckout):
+			utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, utils.ErrInsufficientStock),
+			errors.Is(err, utils.ErrVoucherLimitReached),
+			errors.Is(err, utils.ErrInsufficientPoints),
+			errors.Is(err, utils.ErrCreditLimitExceeded),
+			errors.Is(err, utils.ErrInsufficientGiftCardBalance):
+			utils.ErrorResponse(w, http.StatusConflict, err.Error())
+		default:
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to checkout")
+		}
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Checkout successful", transaction)
+}
+
+// Quote godoc
+// @Summary Price quote
+// @Description Menghitung harga satuan efektif tiap item berdasarkan daftar harga pelanggan dan potongan harga grosir, tanpa menyimpan transaksi
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param checkout body dto.CheckoutRequest true "Cart Data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/transactions/quote [post]
+func (h *TransactionHandler) Quote(w http.ResponseWriter, r *http.Request) {
+	var req dto.CheckoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	quote, err := h.transactionService.Quote(r.Context(), dto.CheckoutReqToDomain(&req))
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrProductNotFound),
+			errors.Is(err, utils.ErrVariantNotFound),
+			errors.Is(err, utils.ErrUnitNotFound),
+			errors.Is(err, utils.ErrCustomerNotFound):
+			utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, utils.ErrVariantRequired),
+			errors.Is(err, utils.ErrInvalidModifier),
+			errors.Is(err, utils.ErrModifierSelection),
+			errors.Is(err, utils.ErrEmptyCheckout):
+			utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		default:
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to quote prices")
+		}
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Quote calculated", quote)
+}
+
+// GetTransactions godoc
+// @Summary Get all transactions
+// @Description Mengambil semua data transaksi
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Param customer_id query int false "Filter by customer ID"
+// @Param cashier_id query int false "Filter by cashier user ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/transactions [get]
+func (h *TransactionHandler) GetTransactions(w http.ResponseWriter, r *http.Request) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = 10
+	}
+
+	var filter domain.TransactionFilter
+	if customerID, err := strconv.Atoi(r.URL.Query().Get("customer_id")); err == nil && customerID > 0 {
+		filter.CustomerID = &customerID
+	}
+	if cashierID, err := strconv.Atoi(r.URL.Query().Get("cashier_id")); err == nil && cashierID > 0 {
+		filter.CashierID = &cashierID
+	}
+
+	transactions, total, err := h.transactionService.GetTransactions(r.Context(), filter, page, pageSize)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get transactions")
+		return
+	}
+
+	utils.SuccessResponse(
+		w,
+		http.StatusOK,
+		"Transactions found",
+		transactions,
+		utils.WithPagination(total, page, pageSize),
+	)
+}
+
+// GetTransactionByID godoc
+// @Summary Get transaction by ID
+// @Description Mengambil transaksi beserta item berdasarkan ID
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Transaction ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/transactions/{id} [get]
+func (h *TransactionHandler) GetTransactionByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	transaction, err := h.transactionService.GetTransactionByID(r.Context(), id)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, utils.ErrTransactionNotFound.Error())
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Transaction found", transaction)
+}
+
+// RefundTransaction godoc
+// @Summary Refund transaction
+// @Description Refund penuh transaksi: stok dikembalikan, pemakaian voucher dibatalkan, mutasi gift card dibatalkan, sisa kasbon dihapus, poin yang didapat ditarik kembali dan poin yang dipakai dikembalikan. Kasir tanpa izin void mendapat respon 403 approval_required dan harus mengulang request dengan persetujuan supervisor (approval grant untuk transaksi ini di X-Approval-Token, atau X-Approver dan X-Approver-PIN)
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Transaction ID"
+// @Param X-Approval-Token header string false "Approval grant token issued by the supervisor"
+// @Param X-Approver header string false "Username of the approving supervisor"
+// @Param X-Approver-PIN header string false "PIN of the approving supervisor"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 423 {object} map[string]string
+// @Router /api/transactions/{id}/refund [post]
+func (h *TransactionHandler) RefundTransaction(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	transaction, err := h.transactionService.RefundTransaction(r.Context(), id)
+	if err != nil {
+		var approval *utils.ApprovalRequiredError
+		switch {
+		case errors.As(err, &approval):
+			utils.ApprovalRequiredResponse(w, approval)
+		case errors.Is(err, utils.ErrApprovalGrantUsed):
+			utils.ErrorResponse(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, utils.ErrTransactionNotFound):
+			utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, utils.ErrTransactionRefunded), errors.Is(err, utils.ErrGiftCardSpent):
+			utils.ErrorResponse(w, http.StatusConflict, err.Error())
+		default:
+			utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to refund transaction")
+		}
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Transaction refunded successfully", transaction)
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/dto"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+	"strconv"
+)
+
+type UnitHandler struct {
+	unitService service.UnitService
+}
+
+func NewUnitHandler(unitService service.UnitService) *UnitHandler {
+	return &UnitHandler{unitService: unitService}
+}
+
+// GetUnits godoc
+// @Summary Get product units
+// @Description Mengambil satuan alternatif produk beserta faktor konversi ke satuan dasar
+// @Tags units
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/products/{id}/units [get]
+func (h *UnitHandler) GetUnits(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	units, err := h.unitService.GetUnits(r.Context(), productID)
+	if err != nil {
+		h.writeError(w, err, "failed to get units")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Units found", units)
+}
+
+// CreateUnit godoc
+// @Summary Create product unit
+// @Description Membuat satuan alternatif, misalnya pack berisi 10 pcs, dengan harga dan barcode sendiri
+// @Tags units
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param unit body dto.UnitRequest true "Unit Data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/products/{id}/units [post]
+func (h *UnitHandler) CreateUnit(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.UnitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	unit := dto.UnitReqToDomain(&req)
+	unit.ProductID = productID
+
+	if _, err := h.unitService.CreateUnit(r.Context(), unit); err != nil {
+		h.writeError(w, err, "Failed to create unit")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Unit created successfully", unit)
+}
+
+// UpdateUnit godoc
+// @Summary Update unit
+// @Description Update satuan alternatif berdasarkan ID
+// @Tags units
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Unit ID"
+// @Param unit body dto.UnitRequest true "Unit Data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/units/{id} [put]
+func (h *UnitHandler) UpdateUnit(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.UnitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	unit, err := h.unitService.UpdateUnit(r.Context(), id, dto.UnitReqToDomain(&req))
+	if err != nil {
+		h.writeError(w, err, "Failed to update unit")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Unit updated successfully", unit)
+}
+
+// DeleteUnit godoc
+// @Summary Delete unit
+// @Description Menghapus satuan alternatif berdasarkan ID
+// @Tags units
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Unit ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/units/{id} [delete]
+func (h *UnitHandler) DeleteUnit(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	if err := h.unitService.DeleteUnit(r.Context(), id); err != nil {
+		h.writeError(w, err, "failed to delete unit")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Unit deleted successfully", nil)
+}
+
+func (h *UnitHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, utils.ErrProductNotFound), errors.Is(err, utils.ErrUnitNotFound):
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrDuplicateUnit):
+		utils.ErrorResponse(w, http.StatusConflict, err.Error())
+	default:
+		utils.ErrorResponse(w, http.StatusInternalServerError, message)
+	}
+}
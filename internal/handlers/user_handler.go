@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/dto"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+	"strconv"
+)
+
+type UserHandler struct {
+	userService service.UserService
+}
+
+func NewUserHandler(userService service.UserService) *UserHandler {
+	return &UserHandler{userService: userService}
+}
+
+// GetUsers godoc
+// @Summary Get all users
+// @Description Mengambil semua user yang bisa login
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/users [get]
+func (h *UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = 10
+	}
+
+	users, total, err := h.userService.GetUsers(r.Context(), page, pageSize)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get users")
+		return
+	}
+
+	utils.SuccessResponse(
+		w,
+		http.StatusOK,
+		"Users found",
+		users,
+		utils.WithPagination(total, page, pageSize),
+	)
+}
+
+// CreateUser godoc
+// @Summary Create user
+// @Description Membuat user baru dengan role-nya (misalnya owner, manager, cashier). Password disimpan sebagai hash bcrypt
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user body dto.UserRequest true "User Data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/users [post]
+func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var req dto.UserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	user, err := h.userService.CreateUser(r.Context(), dto.UserReqToDomain(&req), req.Password)
+	if err != nil {
+		h.writeError(w, err, "Failed to create user")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "User created successfully", user)
+}
+
+// RevokeSessions godoc
+// @Summary Revoke user sessions
+// @Description Mencabut semua sesi user, misalnya saat perangkat hilang. User harus login ulang
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/users/{id}/revoke-sessions [post]
+func (h *UserHandler) RevokeSessions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	if err := h.userService.RevokeSessions(r.Context(), id); err != nil {
+		h.writeError(w, err, "Failed to revoke sessions")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Sessions revoked successfully", nil)
+}
+
+// SetUserPIN godoc
+// @Summary Set user PIN
+// @Description Mengatur atau mereset PIN user untuk login cepat di terminal. Juga membuka PIN yang terkunci
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param pin body dto.PINRequest true "PIN"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/users/{id}/pin [put]
+func (h *UserHandler) SetUserPIN(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.PINRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	if err := h.userService.SetPIN(r.Context(), id, req.PIN); err != nil {
+		h.writeError(w, err, "Failed to set PIN")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "PIN updated successfully", nil)
+}
+
+func (h *UserHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, utils.ErrUserNotFound), errors.Is(err, utils.ErrRoleNotFound):
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrDuplicateUser):
+		utils.ErrorResponse(w, http.StatusConflict, err.Error())
+	default:
+		utils.ErrorResponse(w, http.StatusInternalServerError, message)
+	}
+}
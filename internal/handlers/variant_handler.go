@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/dto"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+	"strconv"
+)
+
+type VariantHandler struct {
+	variantService service.VariantService
+}
+
+func NewVariantHandler(variantService service.VariantService) *VariantHandler {
+	return &VariantHandler{variantService: variantService}
+}
+
+// GetVariants godoc
+// @Summary Get product variants
+// @Description Mengambil opsi varian dan semua varian dari sebuah produk
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/products/{id}/variants [get]
+func (h *VariantHandler) GetVariants(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	options, variants, err := h.variantService.GetVariants(r.Context(), productID)
+	if err != nil {
+		if errors.Is(err, utils.ErrProductNotFound) {
+			utils.ErrorResponse(w, http.StatusNotFound, utils.ErrProductNotFound.Error())
+			return
+		}
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get variants")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Variants found", map[string]interface{}{
+		"options":  options,
+		"variants": variants,
+	})
+}
+
+// ReplaceVariantOptions godoc
+// @Summary Replace product variant options
+// @Description Mengganti daftar opsi varian produk, misalnya size [S, M, L] dan color [black, white]
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param options body dto.VariantOptionsRequest true "Variant Options"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/products/{id}/variant-options [put]
+func (h *VariantHandler) ReplaceVariantOptions(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.VariantOptionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	options, err := h.variantService.ReplaceVariantOptions(r.Context(), productID, dto.VariantOptionsReqToDomain(&req))
+	if err != nil {
+		if errors.Is(err, utils.ErrProductNotFound) {
+			utils.ErrorResponse(w, http.StatusNotFound, utils.ErrProductNotFound.Error())
+			return
+		}
+		utils.ErrorResponse(w, http.StatusInternalServerError, "Failed to update variant options")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Variant options updated successfully", options)
+}
+
+// CreateVariant godoc
+// @Summary Create product variant
+// @Description Membuat varian baru dengan SKU, barcode, harga dan stok sendiri
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param variant body dto.VariantRequest true "Variant Data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/products/{id}/variants [post]
+func (h *VariantHandler) CreateVariant(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.VariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	variant := dto.VariantReqToDomain(&req)
+	variant.ProductID = productID
+
+	if _, err := h.variantService.CreateVariant(r.Context(), variant); err != nil {
+		h.writeError(w, err, "Failed to create variant")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Variant created successfully", variant)
+}
+
+// UpdateVariant godoc
+// @Summary Update variant
+// @Description Update varian berdasarkan ID
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Variant ID"
+// @Param variant body dto.VariantRequest true "Variant Data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/variants/{id} [put]
+func (h *VariantHandler) UpdateVariant(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.VariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	variant, err := h.variantService.UpdateVariant(r.Context(), id, dto.VariantReqToDomain(&req))
+	if err != nil {
+		h.writeError(w, err, "Failed to update variant")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Variant updated successfully", variant)
+}
+
+// DeleteVariant godoc
+// @Summary Delete variant
+// @Description Menghapus varian berdasarkan ID
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Variant ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/variants/{id} [delete]
+func (h *VariantHandler) DeleteVariant(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	if err := h.variantService.DeleteVariant(r.Context(), id); err != nil {
+		h.writeError(w, err, "failed to delete variant")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Variant deleted successfully", nil)
+}
+
+func (h *VariantHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, utils.ErrProductNotFound), errors.Is(err, utils.ErrVariantNotFound):
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrInvalidVariant), errors.Is(err, utils.ErrCompositeVariants):
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, utils.ErrDuplicateVariant), errors.Is(err, utils.ErrVariantInUse):
+		utils.ErrorResponse(w, http.StatusConflict, err.Error())
+	default:
+		utils.ErrorResponse(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/dto"
+	service "kasir-api/internal/services"
+	"kasir-api/internal/utils"
+	"net/http"
+	"strconv"
+)
+
+type VoucherHandler struct {
+	voucherService service.VoucherService
+}
+
+func NewVoucherHandler(voucherService service.VoucherService) *VoucherHandler {
+	return &VoucherHandler{voucherService: voucherService}
+}
+
+// GetVouchers godoc
+// @Summary Get all vouchers
+// @Description Mengambil semua voucher beserta jumlah pemakaiannya
+// @Tags vouchers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/vouchers [get]
+func (h *VoucherHandler) GetVouchers(w http.ResponseWriter, r *http.Request) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = 10
+	}
+
+	vouchers, total, err := h.voucherService.GetVouchers(r.Context(), page, pageSize)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusInternalServerError, "failed to get vouchers")
+		return
+	}
+
+	utils.SuccessResponse(
+		w,
+		http.StatusOK,
+		"Vouchers found",
+		vouchers,
+		utils.WithPagination(total, page, pageSize),
+	)
+}
+
+// GetVoucherByID godoc
+// @Summary Get voucher by ID
+// @Description Mengambil voucher berdasarkan ID
+// @Tags vouchers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Voucher ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/vouchers/{id} [get]
+func (h *VoucherHandler) GetVoucherByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	voucher, err := h.voucherService.GetVoucherByID(r.Context(), id)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusNotFound, utils.ErrVoucherNotFound.Error())
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Voucher found", voucher)
+}
+
+// CreateVoucher godoc
+// @Summary Create voucher
+// @Description Membuat kode voucher dengan diskon, periode berlaku, batas pemakaian total dan per pelanggan, serta minimal belanja
+// @Tags vouchers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param voucher body dto.VoucherRequest true "Voucher Data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/vouchers [post]
+func (h *VoucherHandler) CreateVoucher(w http.ResponseWriter, r *http.Request) {
+	var req dto.VoucherRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	voucher, err := h.voucherService.CreateVoucher(r.Context(), dto.VoucherReqToDomain(&req))
+	if err != nil {
+		h.writeError(w, err, "Failed to create voucher")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Voucher created successfully", voucher)
+}
+
+// UpdateVoucher godoc
+// @Summary Update voucher
+// @Description Update voucher berdasarkan ID
+// @Tags vouchers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Voucher ID"
+// @Param voucher body dto.VoucherRequest true "Voucher Data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/vouchers/{id} [put]
+func (h *VoucherHandler) UpdateVoucher(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	var req dto.VoucherRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	voucher, err := h.voucherService.UpdateVoucher(r.Context(), id, dto.VoucherReqToDomain(&req))
+	if err != nil {
+		h.writeError(w, err, "Failed to update voucher")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Voucher updated successfully", voucher)
+}
+
+// DeleteVoucher godoc
+// @Summary Delete voucher
+// @Description Menghapus voucher berdasarkan ID
+// @Tags vouchers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Voucher ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/vouchers/{id} [delete]
+func (h *VoucherHandler) DeleteVoucher(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid Request")
+		return
+	}
+
+	if err := h.voucherService.DeleteVoucher(r.Context(), id); err != nil {
+		h.writeError(w, err, "failed to delete voucher")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Voucher deleted successfully", nil)
+}
+
+// ValidateVoucher godoc
+// @Summary Validate voucher
+// @Description Mengecek apakah kode voucher bisa dipakai untuk nominal belanja tertentu dan berapa diskonnya, tanpa memakai kuota
+// @Tags vouchers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param voucher body dto.VoucherValidateRequest true "Voucher Code"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/vouchers/validate [post]
+func (h *VoucherHandler) ValidateVoucher(w http.ResponseWriter, r *http.Request) {
+	var req dto.VoucherValidateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.ValidationErrorResponse(w, err)
+		return
+	}
+
+	validation, err := h.voucherService.ValidateVoucher(r.Context(), req.Code, req.CustomerID, req.Amount)
+	if err != nil {
+		h.writeError(w, err, "failed to validate voucher")
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Voucher is valid", validation)
+}
+
+func (h *VoucherHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, utils.ErrVoucherNotFound):
+		utils.ErrorResponse(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrInvalidVoucher),
+		errors.Is(err, utils.ErrVoucherInactive),
+		errors.Is(err, utils.ErrVoucherMinPurchase),
+		errors.Is(err, utils.ErrVoucherCustomerRequired):
+		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, utils.ErrDuplicateVoucher), errors.Is(err, utils.ErrVoucherLimitReached):
+		utils.ErrorResponse(w, http.StatusConflict, err.Error())
+	default:
+		utils.ErrorResponse(w, http.StatusInternalServerError, message)
+	}
+}
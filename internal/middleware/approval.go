@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"kasir-api/internal/utils"
+	"net/http"
+)
+
+// ApprovalVerifier resolves supervisor credentials to the approving user.
+type ApprovalVerifier interface {
+	Approve(ctx context.Context, approvalToken string, username string, pin string, terminalKey string) (*utils.Approver, error)
+}
+
+// Approval verifies the supervisor credentials a request carries, if any,
+// and stores the approver in the request context. Whether the request
+// needed approval at all is decided by the service handling it.
+func Approval(verifier ApprovalVerifier) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			token := r.Header.Get(utils.ApprovalTokenHeader)
+			username := r.Header.Get(utils.ApproverHeader)
+			pin := r.Header.Get(utils.ApproverPINHeader)
+			if token == "" && username == "" && pin == "" {
+				next(w, r)
+				return
+			}
+
+			approver, err := verifier.Approve(r.Context(), token, username, pin, r.Header.Get(utils.TerminalKeyHeader))
+			switch {
+			case errors.Is(err, utils.ErrPINLocked):
+				utils.ErrorResponse(w, http.StatusLocked, err.Error())
+				return
+			case errors.Is(err, utils.ErrInvalidApprover),
+				errors.Is(err, utils.ErrInvalidPIN),
+				errors.Is(err, utils.ErrInvalidTerminal):
+				utils.ErrorResponse(w, http.StatusForbidden, err.Error())
+				return
+			case err != nil:
+				utils.ErrorResponse(w, http.StatusInternalServerError, "failed to verify approval")
+				return
+			}
+
+			next(w, r.WithContext(utils.WithApprover(r.Context(), approver)))
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"kasir-api/internal/utils"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// TokenAuthenticator resolves a bearer access token, and the terminal key
+// sent with it, to the user the token was issued to.
+type TokenAuthenticator interface {
+	Authenticate(ctx context.Context, accessToken string, terminalKey string) (*utils.AuthUser, error)
+}
+
+// Authenticate rejects requests without a valid "Authorization: Bearer"
+// access token and stores the authenticated user in the request context.
+// Requests already authenticated further out are passed through.
+func Authenticate(authenticator TokenAuthenticator) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if utils.AuthUserFromContext(r.Context()) != nil {
+				next(w, r)
+				return
+			}
+
+			scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+			if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				utils.ErrorResponse(w, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+				return
+			}
+
+			user, err := authenticator.Authenticate(r.Context(), strings.TrimSpace(token), r.Header.Get(utils.TerminalKeyHeader))
+			if errors.Is(err, utils.ErrInvalidToken) {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				utils.ErrorResponse(w, http.StatusUnauthorized, err.Error())
+				return
+			}
+			if err != nil {
+				utils.ErrorResponse(w, http.StatusInternalServerError, "failed to authenticate")
+				return
+			}
+
+			next(w, r.WithContext(utils.WithAuthUser(r.Context(), user)))
+		}
+	}
+}
+
+// AuthenticateByDefault authenticates every request except those routed to
+// one of the public patterns, so a route registered without Authenticate is
+// closed instead of open. API keys carry permissions instead of a user, so
+// they are only accepted on routes registered with an Authorize handler;
+// anywhere else they are rejected.
+func AuthenticateByDefault(mux *http.ServeMux, authenticator TokenAuthenticator, public ...string) http.Handler {
+	authenticate := Authenticate(authenticator)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler, pattern := mux.Handler(r)
+		if slices.Contains(public, pattern) {
+			mux.ServeHTTP(w, r)
+			return
+		}
+		_, scoped := handler.(authorizedHandler)
+		authenticate(func(w http.ResponseWriter, r *http.Request) {
+			if user := utils.AuthUserFromContext(r.Context()); user.APIKeyID != nil && !scoped && pattern != "" {
+				utils.ErrorResponse(w, http.StatusForbidden, utils.ErrUserLoginRequired.Error())
+				return
+			}
+			mux.ServeHTTP(w, r)
+		})(w, r)
+	})
+}
+
+// authorizedHandler is a route that authenticates and checks a permission
+// itself, which AuthenticateByDefault looks for before letting an API key
+// through.
+type authorizedHandler struct {
+	http.HandlerFunc
+}
+
+// Authorize returns handlers that authenticate the request and reject users
+// and API keys without the permission. Register them with http.Handle so
+// AuthenticateByDefault can tell that the route requires a permission.
+func Authorize(authenticator TokenAuthenticator) func(permission string, next http.HandlerFunc) http.Handler {
+	authenticate := Authenticate(authenticator)
+	return func(permission string, next http.HandlerFunc) http.Handler {
+		return authorizedHandler{authenticate(RequirePermission(permission, next))}
+	}
+}
+
+// RequirePermission rejects requests whose user has no role granting the
+// permission. It must run after Authenticate.
+func RequirePermission(permission string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := utils.AuthUserFromContext(r.Context())
+		if user == nil {
+			utils.ErrorResponse(w, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+			return
+		}
+		if !user.Can(permission) {
+			utils.ErrorResponse(w, http.StatusForbidden, utils.ErrForbidden.Error())
+			return
+		}
+		next(w, r)
+	}
+}
+
+// RequireUser rejects requests authenticated with an API key, for endpoints
+// that only make sense for a person, such as their own session. It must
+// run after Authenticate.
+func RequireUser(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := utils.AuthUserFromContext(r.Context())
+		if user == nil {
+			utils.ErrorResponse(w, http.StatusUnauthorized, utils.ErrUnauthorized.Error())
+			return
+		}
+		if user.APIKeyID != nil {
+			utils.ErrorResponse(w, http.StatusForbidden, utils.ErrUserLoginRequired.Error())
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"kasir-api/internal/utils"
+	"net/http"
+)
+
+// RequireIfMatch rejects requests without an If-Match header so clients
+// cannot blindly overwrite a resource they have not read.
+func RequireIfMatch(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Match") == "" {
+			utils.ErrorResponse(w, http.StatusPreconditionRequired, utils.ErrPreconditionMissing.Error())
+			return
+		}
+		next(w, r)
+	}
+}
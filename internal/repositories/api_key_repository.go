@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+
+	"github.com/lib/pq"
+)
+
+type APIKeyRepository interface {
+	GetAPIKeys(ctx context.Context) ([]domain.APIKey, error)
+	GetAPIKeyByID(ctx context.Context, id int) (*domain.APIKey, error)
+	GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+	CreateAPIKey(ctx context.Context, key *domain.APIKey, keyHash string) (*domain.APIKey, error)
+	RotateAPIKey(ctx context.Context, id int, prefix string, keyHash string) (*domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int) (*domain.APIKey, error)
+	TouchAPIKey(ctx context.Context, id int) error
+}
+
+type APIKeyRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) APIKeyRepository {
+	return &APIKeyRepositoryImpl{db: db}
+}
+
+const apiKeyColumns = `id, tenant_id, name, prefix, permissions, expires_at, last_used_at, rotated_at, revoked_at, created_by, created_at`
+
+const apiKeySelectQuery = `SELECT ` + apiKeyColumns + ` FROM api_keys`
+
+func scanAPIKey(scanner rowScanner, key *domain.APIKey) error {
+	return scanner.Scan(
+		&key.ID,
+		&key.TenantID,
+		&key.Name,
+		&key.Prefix,
+		pq.Array(&key.Permissions),
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RotatedAt,
+		&key.RevokedAt,
+		&key.CreatedBy,
+		&key.CreatedAt,
+	)
+}
+
+func (r *APIKeyRepositoryImpl) GetAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, apiKeySelectQuery+" ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []domain.APIKey
+	for rows.Next() {
+		var key domain.APIKey
+		if err := scanAPIKey(rows, &key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *APIKeyRepositoryImpl) GetAPIKeyByID(ctx context.Context, id int) (*domain.APIKey, error) {
+	var key domain.APIKey
+	if err := scanAPIKey(r.db.QueryRowContext(ctx, apiKeySelectQuery+" WHERE id = $1", id), &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// GetActiveAPIKeyByHash returns the key with the hash as long as it is
+// neither revoked nor expired. Without a tenant in ctx it is looked for in
+// whichever tenant the key belongs to.
+func (r *APIKeyRepositoryImpl) GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	ctx, err := withTenantOf(ctx, r.db, "tenant_of_api_key", keyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var key domain.APIKey
+	err = scanAPIKey(
+		r.db.QueryRowContext(
+			ctx,
+			apiKeySelectQuery+" WHERE key_hash = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())",
+			keyHash,
+		),
+		&key,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *APIKeyRepositoryImpl) CreateAPIKey(ctx context.Context, key *domain.APIKey, keyHash string) (*domain.APIKey, error) {
+	err := r.db.QueryRowContext(
+		ctx,
+		`INSERT INTO api_keys (name, prefix, key_hash, permissions, expires_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`,
+		key.Name,
+		key.Prefix,
+		keyHash,
+		pq.Array(key.Permissions),
+		key.ExpiresAt,
+		key.CreatedBy,
+	).Scan(&key.ID, &key.CreatedAt)
+	if isUniqueViolation(err) {
+		return nil, utils.ErrDuplicateAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// RotateAPIKey replaces the key of an API key that is not revoked. The old
+// key stops working at once.
+func (r *APIKeyRepositoryImpl) RotateAPIKey(ctx context.Context, id int, prefix string, keyHash string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	err := scanAPIKey(
+		r.db.QueryRowContext(
+			ctx,
+			`UPDATE api_keys SET prefix = $1, key_hash = $2, rotated_at = NOW()
+			WHERE id = $3 AND revoked_at IS NULL RETURNING `+apiKeyColumns,
+			prefix,
+			keyHash,
+			id,
+		),
+		&key,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, utils.ErrAPIKeyRevoked
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// RevokeAPIKey disables a key for good. The key is kept so past changes
+// made with it can still be traced.
+func (r *APIKeyRepositoryImpl) RevokeAPIKey(ctx context.Context, id int) (*domain.APIKey, error) {
+	var key domain.APIKey
+	err := scanAPIKey(
+		r.db.QueryRowContext(
+			ctx,
+			"UPDATE api_keys SET revoked_at = COALESCE(revoked_at, NOW()) WHERE id = $1 RETURNING "+apiKeyColumns,
+			id,
+		),
+		&key,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, utils.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// TouchAPIKey records that a key was used, at most once a minute so busy
+// integrations do not write on every request.
+func (r *APIKeyRepositoryImpl) TouchAPIKey(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE api_keys SET last_used_at = NOW()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')`,
+		id,
+	)
+	return err
+}
@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+	"slices"
+
+	"github.com/lib/pq"
+)
+
+type ApprovalRepository interface {
+	GetApprovalSettings(ctx context.Context) (*domain.ApprovalSettings, error)
+	UpdateApprovalSettings(ctx context.Context, settings *domain.ApprovalSettings) (*domain.ApprovalSettings, error)
+	CreateApprovalGrant(ctx context.Context, grant *domain.ApprovalGrant, tokenHash string) (*domain.ApprovalGrant, error)
+	GetActiveApprovalGrantByHash(ctx context.Context, tokenHash string) (*domain.ApprovalGrant, error)
+}
+
+type ApprovalRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewApprovalRepository(db *sql.DB) ApprovalRepository {
+	return &ApprovalRepositoryImpl{db: db}
+}
+
+func (r *ApprovalRepositoryImpl) GetApprovalSettings(ctx context.Context) (*domain.ApprovalSettings, error) {
+	var settings domain.ApprovalSettings
+	err := r.db.QueryRowContext(
+		ctx,
+		"SELECT discount_threshold_percent FROM approval_settings WHERE tenant_id = current_tenant_id()",
+	).Scan(&settings.DiscountThresholdPercent)
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *ApprovalRepositoryImpl) UpdateApprovalSettings(ctx context.Context, settings *domain.ApprovalSettings) (*domain.ApprovalSettings, error) {
+	query := `
+		INSERT INTO approval_settings (discount_threshold_percent)
+		VALUES ($1)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			discount_threshold_percent = EXCLUDED.discount_threshold_percent`
+
+	if _, err := r.db.ExecContext(ctx, query, settings.DiscountThresholdPercent); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (r *ApprovalRepositoryImpl) CreateApprovalGrant(ctx context.Context, grant *domain.ApprovalGrant, tokenHash string) (*domain.ApprovalGrant, error) {
+	err := r.db.QueryRowContext(
+		ctx,
+		`INSERT INTO approval_grants (token_hash, approver_id, actions, transaction_id, expires_at)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`,
+		tokenHash,
+		grant.ApproverID,
+		pq.Array(grant.Actions),
+		grant.TransactionID,
+		grant.ExpiresAt,
+	).Scan(&grant.ID, &grant.CreatedAt)
+	if isForeignKeyViolation(err) {
+		return nil, utils.ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return grant, nil
+}
+
+// GetActiveApprovalGrantByHash returns the grant with the hash as long as it
+// is neither used nor expired.
+func (r *ApprovalRepositoryImpl) GetActiveApprovalGrantByHash(ctx context.Context, tokenHash string) (*domain.ApprovalGrant, error) {
+	var grant domain.ApprovalGrant
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT id, approver_id, actions, transaction_id, expires_at, consumed_at, created_at
+		FROM approval_grants
+		WHERE token_hash = $1 AND consumed_at IS NULL AND expires_at > NOW()`,
+		tokenHash,
+	).Scan(
+		&grant.ID,
+		&grant.ApproverID,
+		pq.Array(&grant.Actions),
+		&grant.TransactionID,
+		&grant.ExpiresAt,
+		&grant.ConsumedAt,
+		&grant.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &grant, nil
+}
+
+// insertApprovals records the approvals of an operation on a transaction,
+// inside the database transaction that performs it. The grants they were
+// given with are used up in the same transaction, so a grant approves one
+// operation only.
+func insertApprovals(ctx context.Context, tx *sql.Tx, transactionID int, approvals []domain.Approval) error {
+	var consumed []int
+	for i := range approvals {
+		approval := &approvals[i]
+		approval.TransactionID = transactionID
+		if approval.GrantID != nil && !slices.Contains(consumed, *approval.GrantID) {
+			if err := consumeApprovalGrant(ctx, tx, *approval.GrantID); err != nil {
+				return err
+			}
+			consumed = append(consumed, *approval.GrantID)
+		}
+
+		err := tx.QueryRowContext(
+			ctx,
+			`INSERT INTO approvals (transaction_id, action, details, requested_by, approved_by, method, grant_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at`,
+			approval.TransactionID,
+			approval.Action,
+			approval.Details,
+			approval.RequestedBy,
+			approval.ApprovedBy,
+			approval.Method,
+			approval.GrantID,
+		).Scan(&approval.ID, &approval.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// consumeApprovalGrant marks a grant used. It fails with
+// ErrApprovalGrantUsed when the grant expired or another operation used it
+// first.
+func consumeApprovalGrant(ctx context.Context, tx *sql.Tx, id int) error {
+	result, err := tx.ExecContext(
+		ctx,
+		"UPDATE approval_grants SET consumed_at = NOW() WHERE id = $1 AND consumed_at IS NULL AND expires_at > NOW()",
+		id,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return utils.ErrApprovalGrantUsed
+	}
+	return nil
+}
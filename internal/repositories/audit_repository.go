@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	domain "kasir-api/internal/domains"
+	"strings"
+)
+
+type AuditRepository interface {
+	GetAuditLogs(ctx context.Context, filter domain.AuditLogFilter, page int, pageSize int) ([]domain.AuditLog, int, error)
+}
+
+type AuditRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) AuditRepository {
+	return &AuditRepositoryImpl{db: db}
+}
+
+func buildAuditLogFilter(filter domain.AuditLogFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.EntityType != "" {
+		add("entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		add("entity_id = $%d", *filter.EntityID)
+	}
+	if filter.ActorID != nil {
+		add("actor_id = $%d", *filter.ActorID)
+	}
+	if filter.APIKeyID != nil {
+		add("api_key_id = $%d", *filter.APIKeyID)
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	if filter.RequestID != "" {
+		add("request_id = $%d", filter.RequestID)
+	}
+	if filter.From != nil {
+		add("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("created_at < $%d", *filter.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func (r *AuditRepositoryImpl) GetAuditLogs(ctx context.Context, filter domain.AuditLogFilter, page int, pageSize int) ([]domain.AuditLog, int, error) {
+	where, args := buildAuditLogFilter(filter)
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_logs"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(
+		`SELECT id, actor, actor_id, api_key_id, action, entity_type, entity_id, changes, ip, request_id, created_at
+		FROM audit_logs%s ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`,
+		where,
+		len(args)+1,
+		len(args)+2,
+	)
+	args = append(args, pageSize, (page-1)*pageSize)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var logs []domain.AuditLog
+	for rows.Next() {
+		var log domain.AuditLog
+		var changes []byte
+		if err := rows.Scan(
+			&log.ID,
+			&log.Actor,
+			&log.ActorID,
+			&log.APIKeyID,
+			&log.Action,
+			&log.EntityType,
+			&log.EntityID,
+			&changes,
+			&log.IP,
+			&log.RequestID,
+			&log.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		if err := json.Unmarshal(changes, &log.Changes); err != nil {
+			return nil, 0, err
+		}
+		logs = append(logs, log)
+	}
+	return logs, total, rows.Err()
+}
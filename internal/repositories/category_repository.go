@@ -3,15 +3,23 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+	"slices"
+	"strings"
+
+	"github.com/lib/pq"
 )
 
 type CategoryRepository interface {
 	GetCategories(ctx context.Context, page int, pageSize int) ([]domain.Category, int, error)
 	GetCategoryByID(ctx context.Context, id int) (*domain.Category, error)
 	CreateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error)
-	UpdateCategory(ctx context.Context, id int, category *domain.Category) (*domain.Category, error)
-	DeleteCategory(ctx context.Context, id int) error
+	UpdateCategory(ctx context.Context, id int, category *domain.Category, versions []int) (*domain.Category, error)
+	PatchCategory(ctx context.Context, id int, patch *domain.CategoryPatch, versions []int) (*domain.Category, error)
+	DeleteCategory(ctx context.Context, id int, versions []int) error
 }
 
 type CategoryRepositoryImpl struct {
@@ -29,7 +37,7 @@ func (p *CategoryRepositoryImpl) GetCategories(ctx context.Context, page int, pa
 		return nil, 0, err
 	}
 
-	rows, err := p.db.QueryContext(ctx, "SELECT * FROM categories LIMIT $1 OFFSET $2", pageSize, (page-1)*pageSize)
+	rows, err := p.db.QueryContext(ctx, "SELECT id, name, description, tax_rate_id, version FROM categories ORDER BY id LIMIT $1 OFFSET $2", pageSize, (page-1)*pageSize)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -38,7 +46,7 @@ func (p *CategoryRepositoryImpl) GetCategories(ctx context.Context, page int, pa
 	var categories []domain.Category
 	for rows.Next() {
 		var category domain.Category
-		if err := rows.Scan(&category.ID, &category.Name, &category.Description); err != nil {
+		if err := rows.Scan(&category.ID, &category.Name, &category.Description, &category.TaxRateID, &category.Version); err != nil {
 			return nil, 0, err
 		}
 		categories = append(categories, category)
@@ -48,7 +56,7 @@ func (p *CategoryRepositoryImpl) GetCategories(ctx context.Context, page int, pa
 
 func (p *CategoryRepositoryImpl) GetCategoryByID(ctx context.Context, id int) (*domain.Category, error) {
 	var category domain.Category
-	err := p.db.QueryRowContext(ctx, "SELECT * FROM categories WHERE id = $1", id).Scan(&category.ID, &category.Name, &category.Description)
+	err := p.db.QueryRowContext(ctx, "SELECT id, name, description, tax_rate_id, version FROM categories WHERE id = $1", id).Scan(&category.ID, &category.Name, &category.Description, &category.TaxRateID, &category.Version)
 
 	if err != nil {
 		return nil, err
@@ -57,14 +65,14 @@ func (p *CategoryRepositoryImpl) GetCategoryByID(ctx context.Context, id int) (*
 }
 
 func (p *CategoryRepositoryImpl) CreateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error) {
-	query := `INSERT INTO categories (name, description) VALUES ($1, $2) RETURNING id`
+	query := `INSERT INTO categories (name, description) VALUES ($1, $2) RETURNING id, version`
 
 	err := p.db.QueryRowContext(
 		ctx,
 		query,
 		category.Name,
 		category.Description,
-	).Scan(&category.ID)
+	).Scan(&category.ID, &category.Version)
 
 	if err != nil {
 		return nil, err
@@ -73,8 +81,14 @@ func (p *CategoryRepositoryImpl) CreateCategory(ctx context.Context, category *d
 	return category, nil
 }
 
-func (p *CategoryRepositoryImpl) UpdateCategory(ctx context.Context, id int, category *domain.Category) (*domain.Category, error) {
-	query := `UPDATE categories SET name = $1, description = $2 WHERE id = $3 RETURNING id`
+// Nil versions skip the optimistic concurrency check; otherwise the
+// category must be at one of them.
+func (p *CategoryRepositoryImpl) UpdateCategory(ctx context.Context, id int, category *domain.Category, versions []int) (*domain.Category, error) {
+	query := `
+		UPDATE categories
+		SET name = $1, description = $2, version = version + 1
+		WHERE id = $3 AND ($4::int[] IS NULL OR version = ANY($4::int[]))
+		RETURNING id, tax_rate_id, version`
 
 	err := p.db.QueryRowContext(
 		ctx,
@@ -82,8 +96,12 @@ func (p *CategoryRepositoryImpl) UpdateCategory(ctx context.Context, id int, cat
 		category.Name,
 		category.Description,
 		id,
-	).Scan(&category.ID)
+		pq.Array(versions),
+	).Scan(&category.ID, &category.TaxRateID, &category.Version)
 
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, p.versionMismatch(ctx, id)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -91,11 +109,76 @@ func (p *CategoryRepositoryImpl) UpdateCategory(ctx context.Context, id int, cat
 	return category, nil
 }
 
-func (p *CategoryRepositoryImpl) DeleteCategory(ctx context.Context, id int) error {
-	query := "DELETE FROM categories WHERE id = $1"
-	_, err := p.db.ExecContext(ctx, query, id)
+func (p *CategoryRepositoryImpl) PatchCategory(ctx context.Context, id int, patch *domain.CategoryPatch, versions []int) (*domain.Category, error) {
+	var sets []string
+	var args []interface{}
+
+	set := func(column string, value interface{}) {
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	if patch.Name != nil {
+		set("name", *patch.Name)
+	}
+	if patch.Description != nil {
+		set("description", *patch.Description)
+	}
+
+	if len(sets) == 0 {
+		category, err := p.GetCategoryByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if versions != nil && !slices.Contains(versions, category.Version) {
+			return nil, utils.ErrVersionMismatch
+		}
+		return category, nil
+	}
+
+	args = append(args, id, pq.Array(versions))
+	query := fmt.Sprintf(
+		"UPDATE categories SET %s, version = version + 1 WHERE id = $%d AND ($%d::int[] IS NULL OR version = ANY($%d::int[]))",
+		strings.Join(sets, ", "),
+		len(args)-1,
+		len(args),
+		len(args),
+	)
+	result, err := p.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if affected == 0 {
+		return nil, p.versionMismatch(ctx, id)
+	}
+
+	return p.GetCategoryByID(ctx, id)
+}
+
+func (p *CategoryRepositoryImpl) DeleteCategory(ctx context.Context, id int, versions []int) error {
+	query := "DELETE FROM categories WHERE id = $1 AND ($2::int[] IS NULL OR version = ANY($2::int[]))"
+	result, err := p.db.ExecContext(ctx, query, id, pq.Array(versions))
 	if err != nil {
 		return err
 	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return p.versionMismatch(ctx, id)
+	}
 	return nil
 }
+
+// versionMismatch explains why a conditional write touched no row: the
+// category is gone, or it is at a version the client did not expect.
+func (p *CategoryRepositoryImpl) versionMismatch(ctx context.Context, id int) error {
+	var exists bool
+	if err := p.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM categories WHERE id = $1)", id).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return utils.ErrCategoryNotFound
+	}
+	return utils.ErrVersionMismatch
+}
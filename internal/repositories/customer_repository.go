@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+	"strings"
+)
+
+type CustomerRepository interface {
+	GetCustomers(ctx context.Context, filter domain.CustomerFilter, page int, pageSize int) ([]domain.Customer, int, error)
+	GetCustomerByID(ctx context.Context, id int) (*domain.Customer, error)
+	CreateCustomer(ctx context.Context, customer *domain.Customer) (*domain.Customer, error)
+	UpdateCustomer(ctx context.Context, id int, customer *domain.Customer) (*domain.Customer, error)
+	DeleteCustomer(ctx context.Context, id int) error
+	GetCustomerStats(ctx context.Context, id int) (*domain.CustomerStats, error)
+}
+
+type CustomerRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewCustomerRepository(db *sql.DB) CustomerRepository {
+	return &CustomerRepositoryImpl{db: db}
+}
+
+const customerSelectQuery = `
+	SELECT id, name, phone, email, address, notes, credit_limit, credit_balance, created_at
+	FROM customers`
+
+func buildCustomerFilter(filter domain.CustomerFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		conditions = append(conditions, fmt.Sprintf("name ILIKE $%d", len(args)))
+	}
+	if filter.Phone != "" {
+		args = append(args, "%"+filter.Phone+"%")
+		conditions = append(conditions, fmt.Sprintf("phone LIKE $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func scanCustomer(scanner rowScanner, customer *domain.Customer) error {
+	return scanner.Scan(
+		&customer.ID,
+		&customer.Name,
+		&customer.Phone,
+		&customer.Email,
+		&customer.Address,
+		&customer.Notes,
+		&customer.CreditLimit,
+		&customer.CreditBalance,
+		&customer.CreatedAt,
+	)
+}
+
+func (r *CustomerRepositoryImpl) GetCustomers(ctx context.Context, filter domain.CustomerFilter, page int, pageSize int) ([]domain.Customer, int, error) {
+	where, args := buildCustomerFilter(filter)
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM customers"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, pageSize, (page-1)*pageSize)
+	query := fmt.Sprintf("%s%s ORDER BY name, id LIMIT $%d OFFSET $%d", customerSelectQuery, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var customers []domain.Customer
+	for rows.Next() {
+		var customer domain.Customer
+		if err := scanCustomer(rows, &customer); err != nil {
+			return nil, 0, err
+		}
+		customers = append(customers, customer)
+	}
+	return customers, total, rows.Err()
+}
+
+func (r *CustomerRepositoryImpl) GetCustomerByID(ctx context.Context, id int) (*domain.Customer, error) {
+	var customer domain.Customer
+	if err := scanCustomer(r.db.QueryRowContext(ctx, customerSelectQuery+" WHERE id = $1", id), &customer); err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+func (r *CustomerRepositoryImpl) CreateCustomer(ctx context.Context, customer *domain.Customer) (*domain.Customer, error) {
+	err := r.db.QueryRowContext(
+		ctx,
+		`INSERT INTO customers (name, phone, email, address, notes)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id, credit_limit, credit_balance, created_at`,
+		customer.Name,
+		customer.Phone,
+		customer.Email,
+		customer.Address,
+		customer.Notes,
+	).Scan(&customer.ID, &customer.CreditLimit, &customer.CreditBalance, &customer.CreatedAt)
+	if isUniqueViolation(err) {
+		return nil, utils.ErrDuplicateCustomer
+	}
+	if err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
+
+func (r *CustomerRepositoryImpl) UpdateCustomer(ctx context.Context, id int, customer *domain.Customer) (*domain.Customer, error) {
+	err := r.db.QueryRowContext(
+		ctx,
+		`UPDATE customers SET name = $1, phone = $2, email = $3, address = $4, notes = $5
+		WHERE id = $6 RETURNING id, credit_limit, credit_balance, created_at`,
+		customer.Name,
+		customer.Phone,
+		customer.Email,
+		customer.Address,
+		customer.Notes,
+		id,
+	).Scan(&customer.ID, &customer.CreditLimit, &customer.CreditBalance, &customer.CreatedAt)
+	if isUniqueViolation(err) {
+		return nil, utils.ErrDuplicateCustomer
+	}
+	if err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
+
+func (r *CustomerRepositoryImpl) DeleteCustomer(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM customers WHERE id = $1", id)
+	return err
+}
+
+// GetCustomerStats returns the lifetime spend and number of visits of a
+// customer over all of their transactions that were not refunded.
+func (r *CustomerRepositoryImpl) GetCustomerStats(ctx context.Context, id int) (*domain.CustomerStats, error) {
+	var stats domain.CustomerStats
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT COALESCE(SUM(total_amount), 0), COUNT(*), MIN(created_at), MAX(created_at)
+		FROM transactions WHERE customer_id = $1 AND status <> $2`,
+		id,
+		domain.TransactionStatusRefunded,
+	).Scan(&stats.LifetimeSpend, &stats.VisitCount, &stats.FirstVisit, &stats.LastVisit)
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
@@ -0,0 +1,231 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+)
+
+type GiftCardRepository interface {
+	GetGiftCards(ctx context.Context, page int, pageSize int) ([]domain.GiftCard, int, error)
+	GetGiftCardByCode(ctx context.Context, code string) (*domain.GiftCard, error)
+	GetGiftCardLedger(ctx context.Context, giftCardID int, page int, pageSize int) ([]domain.GiftCardEntry, int, error)
+}
+
+type GiftCardRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewGiftCardRepository(db *sql.DB) GiftCardRepository {
+	return &GiftCardRepositoryImpl{db: db}
+}
+
+const giftCardSelectQuery = `
+	SELECT id, code, balance, active, created_at
+	FROM gift_cards`
+
+func scanGiftCard(scanner rowScanner, card *domain.GiftCard) error {
+	return scanner.Scan(&card.ID, &card.Code, &card.Balance, &card.Active, &card.CreatedAt)
+}
+
+func (r *GiftCardRepositoryImpl) GetGiftCards(ctx context.Context, page int, pageSize int) ([]domain.GiftCard, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM gift_cards").Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, giftCardSelectQuery+" ORDER BY id DESC LIMIT $1 OFFSET $2", pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var cards []domain.GiftCard
+	for rows.Next() {
+		var card domain.GiftCard
+		if err := scanGiftCard(rows, &card); err != nil {
+			return nil, 0, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, total, rows.Err()
+}
+
+func (r *GiftCardRepositoryImpl) GetGiftCardByCode(ctx context.Context, code string) (*domain.GiftCard, error) {
+	var card domain.GiftCard
+	if err := scanGiftCard(r.db.QueryRowContext(ctx, giftCardSelectQuery+" WHERE code = $1", code), &card); err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+func (r *GiftCardRepositoryImpl) GetGiftCardLedger(ctx context.Context, giftCardID int, page int, pageSize int) ([]domain.GiftCardEntry, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM gift_card_ledger WHERE gift_card_id = $1", giftCardID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, gift_card_id, transaction_id, type, amount, balance_after, created_at
+		FROM gift_card_ledger WHERE gift_card_id = $1
+		ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`,
+		giftCardID,
+		pageSize,
+		(page-1)*pageSize,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var entries []domain.GiftCardEntry
+	for rows.Next() {
+		var entry domain.GiftCardEntry
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.GiftCardID,
+			&entry.TransactionID,
+			&entry.Type,
+			&entry.Amount,
+			&entry.BalanceAfter,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, total, rows.Err()
+}
+
+// sellGiftCards issues the new gift cards of a transaction and tops up the
+// existing ones, filling in their IDs and balances.
+func sellGiftCards(ctx context.Context, tx *sql.Tx, transactionID int, sales []domain.GiftCardSale) error {
+	for i := range sales {
+		sale := &sales[i]
+		entryType := domain.GiftCardEntryTopUp
+		if sale.GiftCardID == nil {
+			var id int
+			if err := tx.QueryRowContext(ctx, "INSERT INTO gift_cards (code) VALUES ($1) RETURNING id", sale.Code).Scan(&id); err != nil {
+				return err
+			}
+			sale.GiftCardID = &id
+			entryType = domain.GiftCardEntryIssue
+		}
+
+		balance, err := creditGiftCard(ctx, tx, *sale.GiftCardID, &transactionID, entryType, sale.Amount)
+		if err != nil {
+			return err
+		}
+		sale.Balance = balance
+	}
+	return nil
+}
+
+// creditGiftCard adds to a gift card balance and ledgers the movement.
+func creditGiftCard(ctx context.Context, tx *sql.Tx, giftCardID int, transactionID *int, entryType string, amount int) (int, error) {
+	var balance int
+	err := tx.QueryRowContext(
+		ctx,
+		"UPDATE gift_cards SET balance = balance + $1 WHERE id = $2 RETURNING balance",
+		amount,
+		giftCardID,
+	).Scan(&balance)
+	if err != nil {
+		return 0, err
+	}
+	return balance, insertGiftCardEntry(ctx, tx, giftCardID, transactionID, entryType, amount, balance)
+}
+
+// debitGiftCard takes from a gift card balance in a single conditional
+// update, so concurrent redemptions can never overdraw the card. It fails
+// with ErrInsufficientGiftCardBalance when the balance is too low.
+func debitGiftCard(ctx context.Context, tx *sql.Tx, giftCardID int, transactionID *int, entryType string, amount int) (int, error) {
+	var balance int
+	err := tx.QueryRowContext(
+		ctx,
+		"UPDATE gift_cards SET balance = balance - $1 WHERE id = $2 AND balance >= $1 RETURNING balance",
+		amount,
+		giftCardID,
+	).Scan(&balance)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, utils.ErrInsufficientGiftCardBalance
+	}
+	if err != nil {
+		return 0, err
+	}
+	return balance, insertGiftCardEntry(ctx, tx, giftCardID, transactionID, entryType, -amount, balance)
+}
+
+// reverseGiftCards undoes every gift card movement of a refunded
+// transaction: redemptions are credited back and cards sold or topped up
+// are debited again, failing with ErrGiftCardSpent if that value is gone.
+func reverseGiftCards(ctx context.Context, tx *sql.Tx, transactionID int) error {
+	rows, err := tx.QueryContext(
+		ctx,
+		`SELECT gift_card_id, SUM(amount) FROM gift_card_ledger
+		WHERE transaction_id = $1
+		GROUP BY gift_card_id HAVING SUM(amount) <> 0
+		ORDER BY gift_card_id`,
+		transactionID,
+	)
+	if err != nil {
+		return err
+	}
+
+	type movement struct{ giftCardID, amount int }
+	var movements []movement
+	for rows.Next() {
+		var m movement
+		if err := rows.Scan(&m.giftCardID, &m.amount); err != nil {
+			rows.Close()
+			return err
+		}
+		movements = append(movements, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, m := range movements {
+		if m.amount < 0 {
+			if _, err := creditGiftCard(ctx, tx, m.giftCardID, &transactionID, domain.GiftCardEntryReverse, -m.amount); err != nil {
+				return err
+			}
+			continue
+		}
+		_, err := debitGiftCard(ctx, tx, m.giftCardID, &transactionID, domain.GiftCardEntryReverse, m.amount)
+		if errors.Is(err, utils.ErrInsufficientGiftCardBalance) {
+			return utils.ErrGiftCardSpent
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertGiftCardEntry(
+	ctx context.Context,
+	tx *sql.Tx,
+	giftCardID int,
+	transactionID *int,
+	entryType string,
+	amount int,
+	balanceAfter int,
+) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO gift_card_ledger (gift_card_id, transaction_id, type, amount, balance_after)
+		VALUES ($1, $2, $3, $4, $5)`,
+		giftCardID,
+		transactionID,
+		entryType,
+		amount,
+		balanceAfter,
+	)
+	return err
+}
@@ -0,0 +1,368 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type LoyaltyRepository interface {
+	GetLoyaltySettings(ctx context.Context) (*domain.LoyaltySettings, error)
+	UpdateLoyaltySettings(ctx context.Context, settings *domain.LoyaltySettings) (*domain.LoyaltySettings, error)
+	SetProductBonus(ctx context.Context, productID int, points *int) error
+	SetCategoryBonus(ctx context.Context, categoryID int, points int) error
+	GetBonusPoints(ctx context.Context, productIDs []int) (map[int]int, error)
+	GetLoyaltyAccount(ctx context.Context, customerID int, page int, pageSize int) (*domain.LoyaltyAccount, int, error)
+}
+
+type LoyaltyRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewLoyaltyRepository(db *sql.DB) LoyaltyRepository {
+	return &LoyaltyRepositoryImpl{db: db}
+}
+
+func (r *LoyaltyRepositoryImpl) GetLoyaltySettings(ctx context.Context) (*domain.LoyaltySettings, error) {
+	var settings domain.LoyaltySettings
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT enabled, spend_per_point, point_value, expiry_days, min_redeem_points
+		FROM loyalty_settings WHERE tenant_id = current_tenant_id()`,
+	).Scan(
+		&settings.Enabled,
+		&settings.SpendPerPoint,
+		&settings.PointValue,
+		&settings.ExpiryDays,
+		&settings.MinRedeemPoints,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *LoyaltyRepositoryImpl) UpdateLoyaltySettings(ctx context.Context, settings *domain.LoyaltySettings) (*domain.LoyaltySettings, error) {
+	query := `
+		INSERT INTO loyalty_settings (enabled, spend_per_point, point_value, expiry_days, min_redeem_points)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			spend_per_point = EXCLUDED.spend_per_point,
+			point_value = EXCLUDED.point_value,
+			expiry_days = EXCLUDED.expiry_days,
+			min_redeem_points = EXCLUDED.min_redeem_points`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		settings.Enabled,
+		settings.SpendPerPoint,
+		settings.PointValue,
+		settings.ExpiryDays,
+		settings.MinRedeemPoints,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (r *LoyaltyRepositoryImpl) SetProductBonus(ctx context.Context, productID int, points *int) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		"UPDATE products SET loyalty_bonus_points = $1, version = version + 1 WHERE id = $2",
+		points,
+		productID,
+	)
+	return err
+}
+
+func (r *LoyaltyRepositoryImpl) SetCategoryBonus(ctx context.Context, categoryID int, points int) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		"UPDATE categories SET loyalty_bonus_points = $1, version = version + 1 WHERE id = $2",
+		points,
+		categoryID,
+	)
+	return err
+}
+
+// GetBonusPoints returns the bonus points per base unit of each product,
+// taken from the product itself or else its category. Products without a
+// bonus are left out of the map.
+func (r *LoyaltyRepositoryImpl) GetBonusPoints(ctx context.Context, productIDs []int) (map[int]int, error) {
+	query := `
+		SELECT products.id, COALESCE(products.loyalty_bonus_points, categories.loyalty_bonus_points)
+		FROM products
+		JOIN categories ON categories.id = products.category_id
+		WHERE products.id = ANY($1)
+			AND COALESCE(products.loyalty_bonus_points, categories.loyalty_bonus_points) > 0`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(productIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bonus := make(map[int]int, len(productIDs))
+	for rows.Next() {
+		var productID, points int
+		if err := rows.Scan(&productID, &points); err != nil {
+			return nil, err
+		}
+		bonus[productID] = points
+	}
+	return bonus, rows.Err()
+}
+
+// GetLoyaltyAccount expires any points that are due and returns the
+// customer's balance with a page of their ledger, newest first.
+func (r *LoyaltyRepositoryImpl) GetLoyaltyAccount(ctx context.Context, customerID int, page int, pageSize int) (*domain.LoyaltyAccount, int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback()
+
+	account := &domain.LoyaltyAccount{CustomerID: customerID}
+	if _, err := lockLoyaltyPoints(ctx, tx, customerID); err != nil {
+		return nil, 0, err
+	}
+	if err := expirePoints(ctx, tx, customerID, time.Now()); err != nil {
+		return nil, 0, err
+	}
+	if err := tx.QueryRowContext(ctx, "SELECT loyalty_points FROM customers WHERE id = $1", customerID).Scan(&account.Points); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM loyalty_ledger WHERE customer_id = $1", customerID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := tx.QueryContext(
+		ctx,
+		`SELECT id, customer_id, transaction_id, type, points, remaining, expires_at, created_at
+		FROM loyalty_ledger WHERE customer_id = $1
+		ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`,
+		customerID,
+		pageSize,
+		(page-1)*pageSize,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	for rows.Next() {
+		var entry domain.LoyaltyEntry
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.CustomerID,
+			&entry.TransactionID,
+			&entry.Type,
+			&entry.Points,
+			&entry.Remaining,
+			&entry.ExpiresAt,
+			&entry.CreatedAt,
+		); err != nil {
+			rows.Close()
+			return nil, 0, err
+		}
+		account.Entries = append(account.Entries, entry)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, err
+	}
+	return account, total, nil
+}
+
+// lockLoyaltyPoints locks the customer row, serialising every change to
+// their points, and returns the current balance.
+func lockLoyaltyPoints(ctx context.Context, tx *sql.Tx, customerID int) (int, error) {
+	var points int
+	err := tx.QueryRowContext(ctx, "SELECT loyalty_points FROM customers WHERE id = $1 FOR UPDATE", customerID).Scan(&points)
+	return points, err
+}
+
+// expirePoints writes off the unspent part of every lot that has expired at
+// the given time. Each write-off keeps the transaction of its lot so a refund
+// knows how much of the points it earned are already gone. The customer row
+// must already be locked.
+func expirePoints(ctx context.Context, tx *sql.Tx, customerID int, at time.Time) error {
+	rows, err := tx.QueryContext(
+		ctx,
+		`UPDATE loyalty_ledger SET remaining = 0
+		FROM (
+			SELECT id, remaining FROM loyalty_ledger
+			WHERE customer_id = $1 AND remaining > 0 AND expires_at <= $2
+		) expired
+		WHERE loyalty_ledger.id = expired.id
+		RETURNING loyalty_ledger.transaction_id, expired.remaining`,
+		customerID,
+		at,
+	)
+	if err != nil {
+		return err
+	}
+
+	type lot struct {
+		transactionID *int
+		remaining     int
+	}
+	var lots []lot
+	for rows.Next() {
+		var expired lot
+		if err := rows.Scan(&expired.transactionID, &expired.remaining); err != nil {
+			rows.Close()
+			return err
+		}
+		lots = append(lots, expired)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, expired := range lots {
+		if err := insertLoyaltyEntry(ctx, tx, customerID, expired.transactionID, domain.LoyaltyEntryExpire, -expired.remaining, 0, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expiredPoints returns how many of the points earned on a transaction have
+// expired unspent.
+func expiredPoints(ctx context.Context, tx *sql.Tx, customerID int, transactionID int) (int, error) {
+	var points int
+	err := tx.QueryRowContext(
+		ctx,
+		`SELECT COALESCE(-SUM(points), 0) FROM loyalty_ledger
+		WHERE customer_id = $1 AND transaction_id = $2 AND type = $3`,
+		customerID,
+		transactionID,
+		domain.LoyaltyEntryExpire,
+	).Scan(&points)
+	return points, err
+}
+
+// pointsToReverse returns how many of the points earned on a transaction a
+// refund takes back: all of them, spent or not, except those that expired
+// unspent and are already gone.
+func pointsToReverse(earned int, expired int) int {
+	return max(0, earned-expired)
+}
+
+// addPoints credits points as a new lot. While the balance is negative,
+// for example after a refund took back points that were already spent, the
+// lot first pays off that debt.
+func addPoints(ctx context.Context, tx *sql.Tx, customerID int, transactionID *int, entryType string, points int, expiresAt *time.Time) error {
+	balance, err := lockLoyaltyPoints(ctx, tx, customerID)
+	if err != nil {
+		return err
+	}
+
+	remaining := points
+	if balance < 0 {
+		remaining = max(0, points+balance)
+	}
+	return insertLoyaltyEntry(ctx, tx, customerID, transactionID, entryType, points, remaining, expiresAt)
+}
+
+// spendPoints takes points from the customer's lots, soonest to expire
+// first, preferring lots earned on preferTransactionID. Unless allowDebt is
+// set, spending more than the balance fails with ErrInsufficientPoints.
+func spendPoints(
+	ctx context.Context,
+	tx *sql.Tx,
+	customerID int,
+	transactionID *int,
+	entryType string,
+	points int,
+	preferTransactionID *int,
+	allowDebt bool,
+) error {
+	balance, err := lockLoyaltyPoints(ctx, tx, customerID)
+	if err != nil {
+		return err
+	}
+	if !allowDebt && balance < points {
+		return utils.ErrInsufficientPoints
+	}
+
+	rows, err := tx.QueryContext(
+		ctx,
+		`SELECT id, remaining FROM loyalty_ledger
+		WHERE customer_id = $1 AND remaining > 0
+		ORDER BY COALESCE(transaction_id = $2, FALSE) DESC, expires_at NULLS LAST, id`,
+		customerID,
+		preferTransactionID,
+	)
+	if err != nil {
+		return err
+	}
+
+	type lot struct{ id, take int }
+	var lots []lot
+	left := points
+	for rows.Next() && left > 0 {
+		var id, remaining int
+		if err := rows.Scan(&id, &remaining); err != nil {
+			rows.Close()
+			return err
+		}
+		take := min(remaining, left)
+		lots = append(lots, lot{id: id, take: take})
+		left -= take
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, lot := range lots {
+		if _, err := tx.ExecContext(ctx, "UPDATE loyalty_ledger SET remaining = remaining - $1 WHERE id = $2", lot.take, lot.id); err != nil {
+			return err
+		}
+	}
+	return insertLoyaltyEntry(ctx, tx, customerID, transactionID, entryType, -points, 0, nil)
+}
+
+// insertLoyaltyEntry records a ledger entry and moves the customer's
+// balance by its points.
+func insertLoyaltyEntry(
+	ctx context.Context,
+	tx *sql.Tx,
+	customerID int,
+	transactionID *int,
+	entryType string,
+	points int,
+	remaining int,
+	expiresAt *time.Time,
+) error {
+	if _, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO loyalty_ledger (customer_id, transaction_id, type, points, remaining, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		customerID,
+		transactionID,
+		entryType,
+		points,
+		remaining,
+		expiresAt,
+	); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, "UPDATE customers SET loyalty_points = loyalty_points + $1 WHERE id = $2", points, customerID)
+	return err
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import "testing"
+
+func TestPointsToReverse(t *testing.T) {
+	tests := []struct {
+		name     string
+		earned   int
+		expired  int
+		reversed int
+	}{
+		{name: "none expired", earned: 50, reversed: 50},
+		{name: "some expired unspent", earned: 50, expired: 20, reversed: 30},
+		{name: "all expired unspent", earned: 50, expired: 50, reversed: 0},
+		{name: "nothing earned", reversed: 0},
+	}
+
+	for _, test := range tests {
+		if reversed := pointsToReverse(test.earned, test.expired); reversed != test.reversed {
+			t.Errorf("%s: %d points reversed, want %d", test.name, reversed, test.reversed)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	domain "kasir-api/internal/domains"
+
+	"github.com/lib/pq"
+)
+
+type ModifierRepository interface {
+	GetModifierGroups(ctx context.Context, page int, pageSize int) ([]domain.ModifierGroup, int, error)
+	GetModifierGroupByID(ctx context.Context, id int) (*domain.ModifierGroup, error)
+	CreateModifierGroup(ctx context.Context, group *domain.ModifierGroup) (*domain.ModifierGroup, error)
+	UpdateModifierGroup(ctx context.Context, id int, group *domain.ModifierGroup) (*domain.ModifierGroup, error)
+	DeleteModifierGroup(ctx context.Context, id int) error
+	SetProductModifierGroups(ctx context.Context, productID int, groupIDs []int) error
+	SetCategoryModifierGroups(ctx context.Context, categoryID int, groupIDs []int) error
+	GetProductModifierGroups(ctx context.Context, productID int) ([]domain.ModifierGroup, error)
+}
+
+type ModifierRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewModifierRepository(db *sql.DB) ModifierRepository {
+	return &ModifierRepositoryImpl{db: db}
+}
+
+func (r *ModifierRepositoryImpl) GetModifierGroups(ctx context.Context, page int, pageSize int) ([]domain.ModifierGroup, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM modifier_groups").Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	groups, err := r.queryGroups(
+		ctx,
+		"SELECT id, name, min_select, max_select FROM modifier_groups ORDER BY id LIMIT $1 OFFSET $2",
+		pageSize,
+		(page-1)*pageSize,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	return groups, total, nil
+}
+
+func (r *ModifierRepositoryImpl) GetModifierGroupByID(ctx context.Context, id int) (*domain.ModifierGroup, error) {
+	groups, err := r.queryGroups(ctx, "SELECT id, name, min_select, max_select FROM modifier_groups WHERE id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &groups[0], nil
+}
+
+// GetProductModifierGroups returns the groups linked to the product itself
+// and to its category.
+func (r *ModifierRepositoryImpl) GetProductModifierGroups(ctx context.Context, productID int) ([]domain.ModifierGroup, error) {
+	query := `
+		SELECT id, name, min_select, max_select
+		FROM modifier_groups
+		WHERE id IN (
+			SELECT group_id FROM product_modifier_groups WHERE product_id = $1
+			UNION
+			SELECT cmg.group_id
+			FROM category_modifier_groups cmg
+			JOIN products ON products.category_id = cmg.category_id
+			WHERE products.id = $1
+		)
+		ORDER BY id`
+
+	return r.queryGroups(ctx, query, productID)
+}
+
+func (r *ModifierRepositoryImpl) queryGroups(ctx context.Context, query string, args ...interface{}) ([]domain.ModifierGroup, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []domain.ModifierGroup
+	for rows.Next() {
+		var group domain.ModifierGroup
+		if err := rows.Scan(&group.ID, &group.Name, &group.MinSelect, &group.MaxSelect); err != nil {
+			return nil, err
+		}
+		group.Modifiers = []domain.Modifier{}
+		groups = append(groups, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(groups) == 0 {
+		return groups, nil
+	}
+	return groups, r.attachModifiers(ctx, groups)
+}
+
+func (r *ModifierRepositoryImpl) attachModifiers(ctx context.Context, groups []domain.ModifierGroup) error {
+	ids := make([]int, len(groups))
+	index := make(map[int]int, len(groups))
+	for i, group := range groups {
+		ids[i] = group.ID
+		index[group.ID] = i
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, group_id, name, price_delta, ingredient_product_id, ingredient_quantity
+		FROM modifiers WHERE group_id = ANY($1) ORDER BY id`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var modifier domain.Modifier
+		if err := rows.Scan(
+			&modifier.ID,
+			&modifier.GroupID,
+			&modifier.Name,
+			&modifier.PriceDelta,
+			&modifier.IngredientProductID,
+			&modifier.IngredientQuantity,
+		); err != nil {
+			return err
+		}
+		group := &groups[index[modifier.GroupID]]
+		group.Modifiers = append(group.Modifiers, modifier)
+	}
+	return rows.Err()
+}
+
+func (r *ModifierRepositoryImpl) CreateModifierGroup(ctx context.Context, group *domain.ModifierGroup) (*domain.ModifierGroup, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(
+		ctx,
+		"INSERT INTO modifier_groups (name, min_select, max_select) VALUES ($1, $2, $3) RETURNING id",
+		group.Name,
+		group.MinSelect,
+		group.MaxSelect,
+	).Scan(&group.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertModifiers(ctx, tx, group); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// UpdateModifierGroup replaces the group's modifiers. Past transaction lines
+// keep their own copy of the modifier name and price.
+func (r *ModifierRepositoryImpl) UpdateModifierGroup(ctx context.Context, id int, group *domain.ModifierGroup) (*domain.ModifierGroup, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE modifier_groups SET name = $1, min_select = $2, max_select = $3 WHERE id = $4",
+		group.Name,
+		group.MinSelect,
+		group.MaxSelect,
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM modifiers WHERE group_id = $1", id); err != nil {
+		return nil, err
+	}
+
+	group.ID = id
+	if err := insertModifiers(ctx, tx, group); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func insertModifiers(ctx context.Context, tx *sql.Tx, group *domain.ModifierGroup) error {
+	for i := range group.Modifiers {
+		modifier := &group.Modifiers[i]
+		modifier.GroupID = group.ID
+		err := tx.QueryRowContext(
+			ctx,
+			`INSERT INTO modifiers (group_id, name, price_delta, ingredient_product_id, ingredient_quantity)
+			VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+			modifier.GroupID,
+			modifier.Name,
+			modifier.PriceDelta,
+			modifier.IngredientProductID,
+			modifier.IngredientQuantity,
+		).Scan(&modifier.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ModifierRepositoryImpl) DeleteModifierGroup(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM modifier_groups WHERE id = $1", id)
+	return err
+}
+
+func (r *ModifierRepositoryImpl) SetProductModifierGroups(ctx context.Context, productID int, groupIDs []int) error {
+	return r.replaceLinks(ctx, "product_modifier_groups", "product_id", productID, groupIDs)
+}
+
+func (r *ModifierRepositoryImpl) SetCategoryModifierGroups(ctx context.Context, categoryID int, groupIDs []int) error {
+	return r.replaceLinks(ctx, "category_modifier_groups", "category_id", categoryID, groupIDs)
+}
+
+func (r *ModifierRepositoryImpl) replaceLinks(ctx context.Context, table string, column string, ownerID int, groupIDs []int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE "+column+" = $1", ownerID); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO "+table+" ("+column+", group_id) SELECT $1, unnest($2::int[]) ON CONFLICT DO NOTHING",
+		ownerID,
+		pq.Array(groupIDs),
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	domain "kasir-api/internal/domains"
+)
+
+type PriceHistoryRepository interface {
+	GetPriceHistory(ctx context.Context, productID int, page int, pageSize int) ([]domain.PriceHistory, int, error)
+	GetPriceChanges(ctx context.Context, filter domain.PriceHistoryFilter, page int, pageSize int) ([]domain.PriceHistory, int, error)
+}
+
+type PriceHistoryRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewPriceHistoryRepository(db *sql.DB) PriceHistoryRepository {
+	return &PriceHistoryRepositoryImpl{db: db}
+}
+
+const priceHistorySelectQuery = `
+	SELECT
+		price_history.id, price_history.product_id, price_history.variant_id, products.name, price_history.old_price,
+		price_history.new_price, price_history.source, price_history.changed_by, price_history.created_at
+	FROM price_history
+	JOIN products ON products.id = price_history.product_id`
+
+// GetPriceHistory returns the price changes of a product, newest first.
+func (r *PriceHistoryRepositoryImpl) GetPriceHistory(ctx context.Context, productID int, page int, pageSize int) ([]domain.PriceHistory, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM price_history WHERE product_id = $1", productID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := priceHistorySelectQuery + `
+		WHERE price_history.product_id = $1
+		ORDER BY price_history.created_at DESC, price_history.id DESC
+		LIMIT $2 OFFSET $3`
+
+	history, err := r.queryPriceHistory(ctx, query, productID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	return history, total, nil
+}
+
+// GetPriceChanges returns the price changes of every product in a period,
+// newest first.
+func (r *PriceHistoryRepositoryImpl) GetPriceChanges(ctx context.Context, filter domain.PriceHistoryFilter, page int, pageSize int) ([]domain.PriceHistory, int, error) {
+	where := `
+		WHERE price_history.created_at >= $1 AND price_history.created_at < $2
+			AND ($3 = '' OR price_history.source = $3)`
+
+	var total int
+	if err := r.db.QueryRowContext(
+		ctx,
+		"SELECT COUNT(*) FROM price_history"+where,
+		filter.From,
+		filter.To,
+		filter.Source,
+	).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := priceHistorySelectQuery + where + `
+		ORDER BY price_history.created_at DESC, price_history.id DESC
+		LIMIT $4 OFFSET $5`
+
+	history, err := r.queryPriceHistory(ctx, query, filter.From, filter.To, filter.Source, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	return history, total, nil
+}
+
+func (r *PriceHistoryRepositoryImpl) queryPriceHistory(ctx context.Context, query string, args ...interface{}) ([]domain.PriceHistory, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []domain.PriceHistory
+	for rows.Next() {
+		var change domain.PriceHistory
+		if err := rows.Scan(
+			&change.ID,
+			&change.ProductID,
+			&change.VariantID,
+			&change.ProductName,
+			&change.OldPrice,
+			&change.NewPrice,
+			&change.Source,
+			&change.ChangedBy,
+			&change.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		history = append(history, change)
+	}
+	return history, rows.Err()
+}
@@ -0,0 +1,283 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+
+	"github.com/lib/pq"
+)
+
+type PriceListRepository interface {
+	GetPriceLists(ctx context.Context) ([]domain.PriceList, error)
+	GetPriceListByID(ctx context.Context, id int) (*domain.PriceList, error)
+	CreatePriceList(ctx context.Context, priceList *domain.PriceList) (*domain.PriceList, error)
+	UpdatePriceList(ctx context.Context, id int, priceList *domain.PriceList) (*domain.PriceList, error)
+	DeletePriceList(ctx context.Context, id int) error
+	SetCustomerPriceList(ctx context.Context, customerID int, priceListID *int) error
+	GetCustomerPriceListID(ctx context.Context, customerID *int) (*int, error)
+	ResolvePrice(ctx context.Context, priceListID int, productID int, variantID *int, quantity int) (*int, error)
+}
+
+type PriceListRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewPriceListRepository(db *sql.DB) PriceListRepository {
+	return &PriceListRepositoryImpl{db: db}
+}
+
+func (r *PriceListRepositoryImpl) GetPriceLists(ctx context.Context) ([]domain.PriceList, error) {
+	return r.queryPriceLists(ctx, "SELECT id, name, description, is_default FROM price_lists ORDER BY id")
+}
+
+func (r *PriceListRepositoryImpl) GetPriceListByID(ctx context.Context, id int) (*domain.PriceList, error) {
+	priceLists, err := r.queryPriceLists(ctx, "SELECT id, name, description, is_default FROM price_lists WHERE id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(priceLists) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &priceLists[0], nil
+}
+
+func (r *PriceListRepositoryImpl) queryPriceLists(ctx context.Context, query string, args ...interface{}) ([]domain.PriceList, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var priceLists []domain.PriceList
+	for rows.Next() {
+		var priceList domain.PriceList
+		if err := rows.Scan(&priceList.ID, &priceList.Name, &priceList.Description, &priceList.IsDefault); err != nil {
+			return nil, err
+		}
+		priceList.Items = []domain.PriceListItem{}
+		priceLists = append(priceLists, priceList)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(priceLists) == 0 {
+		return priceLists, nil
+	}
+	return priceLists, r.attachItems(ctx, priceLists)
+}
+
+func (r *PriceListRepositoryImpl) attachItems(ctx context.Context, priceLists []domain.PriceList) error {
+	ids := make([]int, len(priceLists))
+	index := make(map[int]int, len(priceLists))
+	for i, priceList := range priceLists {
+		ids[i] = priceList.ID
+		index[priceList.ID] = i
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, price_list_id, product_id, variant_id, min_quantity, price
+		FROM price_list_items WHERE price_list_id = ANY($1)
+		ORDER BY product_id, variant_id NULLS FIRST, min_quantity`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var priceListID int
+		var item domain.PriceListItem
+		if err := rows.Scan(&item.ID, &priceListID, &item.ProductID, &item.VariantID, &item.MinQuantity, &item.Price); err != nil {
+			return err
+		}
+		priceList := &priceLists[index[priceListID]]
+		priceList.Items = append(priceList.Items, item)
+	}
+	return rows.Err()
+}
+
+func (r *PriceListRepositoryImpl) CreatePriceList(ctx context.Context, priceList *domain.PriceList) (*domain.PriceList, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if priceList.IsDefault {
+		if _, err := tx.ExecContext(ctx, "UPDATE price_lists SET is_default = FALSE WHERE is_default"); err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.QueryRowContext(
+		ctx,
+		"INSERT INTO price_lists (name, description, is_default) VALUES ($1, $2, $3) RETURNING id",
+		priceList.Name,
+		priceList.Description,
+		priceList.IsDefault,
+	).Scan(&priceList.ID)
+	if isUniqueViolation(err) {
+		return nil, utils.ErrDuplicatePriceList
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertPriceListItems(ctx, tx, priceList); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return priceList, nil
+}
+
+// UpdatePriceList replaces the list's items. Past transaction lines keep the
+// price they were sold at.
+func (r *PriceListRepositoryImpl) UpdatePriceList(ctx context.Context, id int, priceList *domain.PriceList) (*domain.PriceList, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if priceList.IsDefault {
+		if _, err := tx.ExecContext(ctx, "UPDATE price_lists SET is_default = FALSE WHERE is_default AND id <> $1", id); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE price_lists SET name = $1, description = $2, is_default = $3 WHERE id = $4",
+		priceList.Name,
+		priceList.Description,
+		priceList.IsDefault,
+		id,
+	)
+	if isUniqueViolation(err) {
+		return nil, utils.ErrDuplicatePriceList
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM price_list_items WHERE price_list_id = $1", id); err != nil {
+		return nil, err
+	}
+
+	priceList.ID = id
+	if err := insertPriceListItems(ctx, tx, priceList); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return priceList, nil
+}
+
+func insertPriceListItems(ctx context.Context, tx *sql.Tx, priceList *domain.PriceList) error {
+	for i := range priceList.Items {
+		item := &priceList.Items[i]
+		err := tx.QueryRowContext(
+			ctx,
+			`INSERT INTO price_list_items (price_list_id, product_id, variant_id, min_quantity, price)
+			VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+			priceList.ID,
+			item.ProductID,
+			item.VariantID,
+			item.MinQuantity,
+			item.Price,
+		).Scan(&item.ID)
+		if isUniqueViolation(err) {
+			return utils.ErrDuplicatePriceList
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *PriceListRepositoryImpl) DeletePriceList(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM price_lists WHERE id = $1", id)
+	return err
+}
+
+// SetCustomerPriceList assigns a price list to a customer. A nil priceListID
+// puts the customer back on the default list.
+func (r *PriceListRepositoryImpl) SetCustomerPriceList(ctx context.Context, customerID int, priceListID *int) error {
+	if priceListID == nil {
+		_, err := r.db.ExecContext(ctx, "DELETE FROM customer_price_lists WHERE customer_id = $1", customerID)
+		return err
+	}
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO customer_price_lists (customer_id, price_list_id) VALUES ($1, $2)
+		ON CONFLICT (customer_id) DO UPDATE SET price_list_id = EXCLUDED.price_list_id`,
+		customerID,
+		*priceListID,
+	)
+	return err
+}
+
+// GetCustomerPriceListID returns the price list that applies to a customer:
+// their own list, otherwise the default list. It returns nil when neither
+// exists, meaning products sell at their own price.
+func (r *PriceListRepositoryImpl) GetCustomerPriceListID(ctx context.Context, customerID *int) (*int, error) {
+	var id int
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT id FROM (
+			SELECT price_list_id AS id, 1 AS priority FROM customer_price_lists WHERE customer_id = $1
+			UNION ALL
+			SELECT id, 2 FROM price_lists WHERE is_default
+		) candidates
+		ORDER BY priority
+		LIMIT 1`,
+		customerID,
+	).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// ResolvePrice returns the price per base unit for the given quantity, or
+// nil when the list has no entry for the product. The highest quantity break
+// reached wins, and entries for the exact variant win over product-wide ones.
+func (r *PriceListRepositoryImpl) ResolvePrice(ctx context.Context, priceListID int, productID int, variantID *int, quantity int) (*int, error) {
+	var price int
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT price FROM price_list_items
+		WHERE price_list_id = $1 AND product_id = $2
+			AND (variant_id = $3 OR variant_id IS NULL)
+			AND min_quantity <= $4
+		ORDER BY variant_id IS NULL, min_quantity DESC
+		LIMIT 1`,
+		priceListID,
+		productID,
+		variantID,
+		quantity,
+	).Scan(&price)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
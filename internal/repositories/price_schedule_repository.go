@@ -0,0 +1,318 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	domain "kasir-api/internal/domains"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type PriceScheduleRepository interface {
+	GetScheduledPrices(ctx context.Context, productID int) ([]domain.ScheduledPrice, error)
+	GetUpcomingPrices(ctx context.Context, productIDs []int, at time.Time) ([]domain.ScheduledPrice, error)
+	GetDuePrices(ctx context.Context, productIDs []int, at time.Time) ([]domain.ScheduledPrice, error)
+	CreateScheduledPrice(ctx context.Context, price *domain.ScheduledPrice) (*domain.ScheduledPrice, error)
+	DeleteScheduledPrice(ctx context.Context, id int) error
+	ApplyDuePrices(ctx context.Context, at time.Time) error
+
+	GetPriceWindows(ctx context.Context) ([]domain.PriceWindow, error)
+	GetPriceWindowByID(ctx context.Context, id int) (*domain.PriceWindow, error)
+	GetActivePriceWindows(ctx context.Context, productIDs []int, categoryIDs []int) ([]domain.PriceWindow, error)
+	CreatePriceWindow(ctx context.Context, window *domain.PriceWindow) (*domain.PriceWindow, error)
+	UpdatePriceWindow(ctx context.Context, id int, window *domain.PriceWindow) (*domain.PriceWindow, error)
+	DeletePriceWindow(ctx context.Context, id int) error
+}
+
+type PriceScheduleRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewPriceScheduleRepository(db *sql.DB) PriceScheduleRepository {
+	return &PriceScheduleRepositoryImpl{db: db}
+}
+
+const scheduledPriceSelectQuery = `
+	SELECT id, product_id, price, starts_at, applied_at
+	FROM scheduled_prices`
+
+func (r *PriceScheduleRepositoryImpl) GetScheduledPrices(ctx context.Context, productID int) ([]domain.ScheduledPrice, error) {
+	return r.queryScheduledPrices(ctx, scheduledPriceSelectQuery+" WHERE product_id = $1 ORDER BY starts_at DESC, id DESC", productID)
+}
+
+// GetUpcomingPrices returns the scheduled prices of the given products that
+// are not due at the given time, soonest first.
+func (r *PriceScheduleRepositoryImpl) GetUpcomingPrices(ctx context.Context, productIDs []int, at time.Time) ([]domain.ScheduledPrice, error) {
+	query := scheduledPriceSelectQuery + `
+		WHERE product_id = ANY($1) AND applied_at IS NULL AND starts_at > $2
+		ORDER BY product_id, starts_at, id`
+
+	return r.queryScheduledPrices(ctx, query, pq.Array(productIDs), at)
+}
+
+// GetDuePrices returns, for each of the given products, the latest
+// scheduled price that is due at the given time but has not been written to
+// the product yet: the price ApplyDuePrices will leave it at.
+func (r *PriceScheduleRepositoryImpl) GetDuePrices(ctx context.Context, productIDs []int, at time.Time) ([]domain.ScheduledPrice, error) {
+	query := `
+		SELECT DISTINCT ON (product_id) id, product_id, price, starts_at, applied_at
+		FROM scheduled_prices
+		WHERE product_id = ANY($1) AND applied_at IS NULL AND starts_at <= $2
+		ORDER BY product_id, starts_at DESC, id DESC`
+
+	return r.queryScheduledPrices(ctx, query, pq.Array(productIDs), at)
+}
+
+func (r *PriceScheduleRepositoryImpl) queryScheduledPrices(ctx context.Context, query string, args ...interface{}) ([]domain.ScheduledPrice, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prices []domain.ScheduledPrice
+	for rows.Next() {
+		var price domain.ScheduledPrice
+		if err := rows.Scan(&price.ID, &price.ProductID, &price.Price, &price.StartsAt, &price.AppliedAt); err != nil {
+			return nil, err
+		}
+		prices = append(prices, price)
+	}
+	return prices, rows.Err()
+}
+
+func (r *PriceScheduleRepositoryImpl) CreateScheduledPrice(ctx context.Context, price *domain.ScheduledPrice) (*domain.ScheduledPrice, error) {
+	err := r.db.QueryRowContext(
+		ctx,
+		"INSERT INTO scheduled_prices (product_id, price, starts_at) VALUES ($1, $2, $3) RETURNING id",
+		price.ProductID,
+		price.Price,
+		price.StartsAt,
+	).Scan(&price.ID)
+	if err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+// DeleteScheduledPrice cancels a scheduled price that has not been applied.
+func (r *PriceScheduleRepositoryImpl) DeleteScheduledPrice(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM scheduled_prices WHERE id = $1 AND applied_at IS NULL", id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ApplyDuePrices writes every scheduled price due at the given time to its
+// product, oldest first, and records the change in the price history. Rows
+// being applied by another server are skipped.
+func (r *PriceScheduleRepositoryImpl) ApplyDuePrices(ctx context.Context, at time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(
+		ctx,
+		`SELECT id, product_id, price FROM scheduled_prices
+		WHERE applied_at IS NULL AND starts_at <= $1
+		ORDER BY starts_at, id
+		FOR UPDATE SKIP LOCKED`,
+		at,
+	)
+	if err != nil {
+		return err
+	}
+
+	var due []domain.ScheduledPrice
+	for rows.Next() {
+		var price domain.ScheduledPrice
+		if err := rows.Scan(&price.ID, &price.ProductID, &price.Price); err != nil {
+			rows.Close()
+			return err
+		}
+		due = append(due, price)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(due) == 0 {
+		return nil
+	}
+
+	for _, price := range due {
+		var oldPrice int
+		err := tx.QueryRowContext(ctx, "SELECT price FROM products WHERE id = $1 FOR UPDATE", price.ProductID).Scan(&oldPrice)
+		if err != nil {
+			return err
+		}
+
+		if oldPrice != price.Price {
+			if _, err := tx.ExecContext(
+				ctx,
+				"UPDATE products SET price = $1, version = version + 1 WHERE id = $2",
+				price.Price,
+				price.ProductID,
+			); err != nil {
+				return err
+			}
+			if err := recordPriceChange(
+				ctx,
+				tx,
+				price.ProductID,
+				nil,
+				oldPrice,
+				price.Price,
+				domain.PriceSourceSchedule,
+				domain.PriceChangedBySystem,
+			); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, "UPDATE scheduled_prices SET applied_at = $1 WHERE id = $2", at, price.ID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+const priceWindowSelectQuery = `
+	SELECT
+		id, name, product_id, category_id, days_of_week,
+		to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI'),
+		adjustment_type, value, active
+	FROM price_windows`
+
+func (r *PriceScheduleRepositoryImpl) GetPriceWindows(ctx context.Context) ([]domain.PriceWindow, error) {
+	return r.queryPriceWindows(ctx, priceWindowSelectQuery+" ORDER BY id")
+}
+
+func (r *PriceScheduleRepositoryImpl) GetPriceWindowByID(ctx context.Context, id int) (*domain.PriceWindow, error) {
+	windows, err := r.queryPriceWindows(ctx, priceWindowSelectQuery+" WHERE id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(windows) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &windows[0], nil
+}
+
+// GetActivePriceWindows returns the active windows for any of the given
+// products or categories. Whether a window is running is decided by the
+// caller at sale time.
+func (r *PriceScheduleRepositoryImpl) GetActivePriceWindows(ctx context.Context, productIDs []int, categoryIDs []int) ([]domain.PriceWindow, error) {
+	query := priceWindowSelectQuery + `
+		WHERE active AND (product_id = ANY($1) OR category_id = ANY($2))
+		ORDER BY id`
+
+	return r.queryPriceWindows(ctx, query, pq.Array(productIDs), pq.Array(categoryIDs))
+}
+
+func (r *PriceScheduleRepositoryImpl) queryPriceWindows(ctx context.Context, query string, args ...interface{}) ([]domain.PriceWindow, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var windows []domain.PriceWindow
+	for rows.Next() {
+		var window domain.PriceWindow
+		var days pq.Int64Array
+		if err := rows.Scan(
+			&window.ID,
+			&window.Name,
+			&window.ProductID,
+			&window.CategoryID,
+			&days,
+			&window.StartTime,
+			&window.EndTime,
+			&window.AdjustmentType,
+			&window.Value,
+			&window.Active,
+		); err != nil {
+			return nil, err
+		}
+		window.DaysOfWeek = make([]int, len(days))
+		for i, day := range days {
+			window.DaysOfWeek[i] = int(day)
+		}
+		windows = append(windows, window)
+	}
+	return windows, rows.Err()
+}
+
+func (r *PriceScheduleRepositoryImpl) CreatePriceWindow(ctx context.Context, window *domain.PriceWindow) (*domain.PriceWindow, error) {
+	err := r.db.QueryRowContext(
+		ctx,
+		`INSERT INTO price_windows
+			(name, product_id, category_id, days_of_week, start_time, end_time, adjustment_type, value, active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`,
+		window.Name,
+		window.ProductID,
+		window.CategoryID,
+		pq.Array(daysOfWeek(window.DaysOfWeek)),
+		window.StartTime,
+		window.EndTime,
+		window.AdjustmentType,
+		window.Value,
+		window.Active,
+	).Scan(&window.ID)
+	if err != nil {
+		return nil, err
+	}
+	return window, nil
+}
+
+func (r *PriceScheduleRepositoryImpl) UpdatePriceWindow(ctx context.Context, id int, window *domain.PriceWindow) (*domain.PriceWindow, error) {
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE price_windows SET
+			name = $1, product_id = $2, category_id = $3, days_of_week = $4, start_time = $5,
+			end_time = $6, adjustment_type = $7, value = $8, active = $9
+		WHERE id = $10`,
+		window.Name,
+		window.ProductID,
+		window.CategoryID,
+		pq.Array(daysOfWeek(window.DaysOfWeek)),
+		window.StartTime,
+		window.EndTime,
+		window.AdjustmentType,
+		window.Value,
+		window.Active,
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	window.ID = id
+	return window, nil
+}
+
+func (r *PriceScheduleRepositoryImpl) DeletePriceWindow(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM price_windows WHERE id = $1", id)
+	return err
+}
+
+func daysOfWeek(days []int) []int64 {
+	values := make([]int64, len(days))
+	for i, day := range days {
+		values[i] = int64(day)
+	}
+	return values
+}
@@ -3,15 +3,28 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+	"slices"
+	"strings"
+
+	"github.com/lib/pq"
 )
 
 type ProductRepository interface {
-	GetProducts(ctx context.Context, page int, pageSize int) ([]domain.Product, int, error)
+	GetProducts(ctx context.Context, filter domain.ProductFilter, page int, pageSize int) ([]domain.Product, int, error)
+	StreamProducts(ctx context.Context, filter domain.ProductFilter, fn func(*domain.Product) error) error
 	GetProductByID(ctx context.Context, id int) (*domain.Product, error)
 	CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error)
-	UpdateProduct(ctx context.Context, id int, product *domain.Product) (*domain.Product, error)
-	DeleteProduct(ctx context.Context, id int) error
+	UpdateProduct(ctx context.Context, id int, product *domain.Product, versions []int) (*domain.Product, error)
+	PatchProduct(ctx context.Context, id int, patch *domain.ProductPatch, versions []int) (*domain.Product, error)
+	DeleteProduct(ctx context.Context, id int, versions []int) error
+	GetComponents(ctx context.Context, productIDs []int) ([]domain.ProductComponent, error)
+	IsComponent(ctx context.Context, productID int) (bool, error)
+	ReplaceComponents(ctx context.Context, productID int, components []domain.ProductComponent) error
+	BulkUpdateProducts(ctx context.Context, filter domain.ProductFilter, apply func(*domain.Product) error, source string, preview bool) ([]domain.BulkProductUpdateItem, error)
 }
 
 type ProductRepositoryImpl struct {
@@ -22,30 +35,87 @@ func NewProductRepository(db *sql.DB) ProductRepository {
 	return &ProductRepositoryImpl{db: db}
 }
 
-func (p *ProductRepositoryImpl) GetProducts(ctx context.Context, page int, pageSize int) ([]domain.Product, int, error) {
+// The stock of a bundle or recipe is how many units can be assembled from
+// the current stock of its components.
+const productSelectQuery = `
+	SELECT
+		products.id,
+		products.name,
+		products.type,
+		products.price,
+		products.cost,
+		CASE WHEN products.type = 'standard' THEN products.stock ELSE COALESCE((
+			SELECT MIN(component.stock / pc.quantity)
+			FROM product_components pc
+			JOIN products component ON component.id = pc.component_product_id
+			WHERE pc.product_id = products.id
+		), 0) END AS stock,
+		products.base_unit,
+		products.tax_rate_id,
+		products.tax_exempt,
+		products.version,
+		categories.id AS category_id,
+		categories.name AS category_name
+	FROM products
+	JOIN categories ON products.category_id = categories.id`
+
+func buildProductFilter(filter domain.ProductFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if len(filter.IDs) > 0 {
+		args = append(args, pq.Array(filter.IDs))
+		conditions = append(conditions, fmt.Sprintf("products.id = ANY($%d)", len(args)))
+	}
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		conditions = append(conditions, fmt.Sprintf("products.name ILIKE $%d", len(args)))
+	}
+	if filter.CategoryID > 0 {
+		args = append(args, filter.CategoryID)
+		conditions = append(conditions, fmt.Sprintf("products.category_id = $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanProduct(scanner rowScanner, product *domain.Product) error {
+	return scanner.Scan(
+		&product.ID,
+		&product.Name,
+		&product.Type,
+		&product.Price,
+		&product.Cost,
+		&product.Stock,
+		&product.BaseUnit,
+		&product.TaxRateID,
+		&product.TaxExempt,
+		&product.Version,
+		&product.Category.ID,
+		&product.Category.Name,
+	)
+}
+
+func (p *ProductRepositoryImpl) GetProducts(ctx context.Context, filter domain.ProductFilter, page int, pageSize int) ([]domain.Product, int, error) {
+	where, args := buildProductFilter(filter)
+
 	var total int
-	err := p.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM products").Scan(&total)
+	err := p.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM products"+where, args...).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	query := `
-		SELECT
-			products.id,
-			products.name,
-			products.price,
-			products.stock,
-			categories.id as category_id,
-			categories.name as category_name
-		FROM products
-		JOIN categories ON products.category_id = categories.id LIMIT $1 OFFSET $2`
-
-	rows, err := p.db.QueryContext(
-		ctx,
-		query,
-		pageSize,
-		(page-1)*pageSize,
-	)
+	query := fmt.Sprintf("%s%s ORDER BY products.id LIMIT $%d OFFSET $%d", productSelectQuery, where, len(args)+1, len(args)+2)
+	args = append(args, pageSize, (page-1)*pageSize)
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -54,44 +124,41 @@ func (p *ProductRepositoryImpl) GetProducts(ctx context.Context, page int, pageS
 	var products []domain.Product
 	for rows.Next() {
 		var product domain.Product
-		if err := rows.Scan(
-			&product.ID,
-			&product.Name,
-			&product.Price,
-			&product.Stock,
-			&product.Category.ID,
-			&product.Category.Name,
-		); err != nil {
+		if err := scanProduct(rows, &product); err != nil {
 			return nil, 0, err
 		}
 		products = append(products, product)
 	}
-	return products, total, nil
+	return products, total, rows.Err()
+}
+
+func (p *ProductRepositoryImpl) StreamProducts(ctx context.Context, filter domain.ProductFilter, fn func(*domain.Product) error) error {
+	where, args := buildProductFilter(filter)
+
+	rows, err := p.db.QueryContext(ctx, productSelectQuery+where+" ORDER BY products.id", args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product domain.Product
+		if err := scanProduct(rows, &product); err != nil {
+			return err
+		}
+		if err := fn(&product); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
 }
 
 func (p *ProductRepositoryImpl) GetProductByID(ctx context.Context, id int) (*domain.Product, error) {
 	var product domain.Product
 
-	query := `
-		SELECT 
-			products.id,
-			products.name,
-			products.price,
-			products.stock,
-			categories.id AS category_id,
-			categories.name AS category_name
-		FROM products
-		JOIN categories ON products.category_id = categories.id
-		WHERE products.id = $1`
-
-	err := p.db.QueryRowContext(ctx, query, id).Scan(
-		&product.ID,
-		&product.Name,
-		&product.Price,
-		&product.Stock,
-		&product.Category.ID,
-		&product.Category.Name,
-	)
+	query := productSelectQuery + " WHERE products.id = $1"
+
+	err := scanProduct(p.db.QueryRowContext(ctx, query, id), &product)
 	if err != nil {
 		return nil, err
 	}
@@ -100,15 +167,18 @@ func (p *ProductRepositoryImpl) GetProductByID(ctx context.Context, id int) (*do
 }
 
 func (p *ProductRepositoryImpl) CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
-	query := `INSERT INTO products (name, price, stock) VALUES ($1, $2, $3) RETURNING id`
+	query := `INSERT INTO products (name, type, price, cost, stock, base_unit) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, version`
 
 	err := p.db.QueryRowContext(
 		ctx,
 		query,
 		product.Name,
+		product.Type,
 		product.Price,
+		product.Cost,
 		product.Stock,
-	).Scan(&product.ID)
+		product.BaseUnit,
+	).Scan(&product.ID, &product.Version)
 
 	if err != nil {
 		return nil, err
@@ -117,30 +187,382 @@ func (p *ProductRepositoryImpl) CreateProduct(ctx context.Context, product *doma
 	return product, nil
 }
 
-func (p *ProductRepositoryImpl) UpdateProduct(ctx context.Context, id int, product *domain.Product) (*domain.Product, error) {
-	query := `UPDATE products SET name = $1, price = $2, stock = $3 WHERE id = $4 RETURNING id`
+// Nil versions skip the optimistic concurrency check. A changed price is
+// recorded in the price history as a manual change, and a changed stock as
+// an adjustment. The stock of a bundle or recipe comes from its components,
+// so the stock given for one is ignored.
+func (p *ProductRepositoryImpl) UpdateProduct(ctx context.Context, id int, product *domain.Product, versions []int) (*domain.Product, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
-	err := p.db.QueryRowContext(
+	old, err := lockProduct(ctx, tx, id, versions)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.IsComposite() {
+		product.Stock = old.Stock
+	}
+	if err := adjustStock(ctx, tx, id, nil, old.Stock, product.Stock); err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE products
+		SET name = $1, type = $2, price = $3, cost = $4, base_unit = $5, version = version + 1
+		WHERE id = $6
+		RETURNING id, tax_rate_id, tax_exempt, version`
+
+	err = tx.QueryRowContext(
 		ctx,
 		query,
 		product.Name,
+		product.Type,
 		product.Price,
-		product.Stock,
+		product.Cost,
+		product.BaseUnit,
 		id,
-	).Scan(&product.ID)
-
+	).Scan(&product.ID, &product.TaxRateID, &product.TaxExempt, &product.Version)
 	if err != nil {
 		return nil, err
 	}
 
+	if product.Price != old.Price {
+		changedBy := utils.ActorFromContext(ctx)
+		if err := recordPriceChange(ctx, tx, id, nil, old.Price, product.Price, domain.PriceSourceManual, changedBy); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return product, nil
 }
 
-func (p *ProductRepositoryImpl) DeleteProduct(ctx context.Context, id int) error {
-	query := "DELETE FROM products WHERE id = $1"
-	_, err := p.db.ExecContext(ctx, query, id)
+// lockProduct locks a product row for update and returns its price, stock
+// and type. A missing product is reported as not found, and a version other
+// than the expected ones, unless versions is nil, as a version mismatch.
+func lockProduct(ctx context.Context, tx *sql.Tx, id int, versions []int) (*domain.Product, error) {
+	product := domain.Product{ID: id}
+	err := tx.QueryRowContext(
+		ctx,
+		"SELECT price, stock, type, version FROM products WHERE id = $1 FOR UPDATE",
+		id,
+	).Scan(&product.Price, &product.Stock, &product.Type, &product.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, utils.ErrProductNotFound
+	}
 	if err != nil {
+		return nil, err
+	}
+	if versions != nil && !slices.Contains(versions, product.Version) {
+		return nil, utils.ErrVersionMismatch
+	}
+	return &product, nil
+}
+
+// adjustStock records a manual change of stock as an adjustment for the
+// difference, so it shows up in the stock movements like any other change.
+func adjustStock(ctx context.Context, tx *sql.Tx, productID int, variantID *int, oldStock int, newStock int) error {
+	if newStock == oldStock {
+		return nil
+	}
+	return applyStockMovements(ctx, tx, []domain.StockMovement{{
+		ProductID:     productID,
+		VariantID:     variantID,
+		Quantity:      newStock - oldStock,
+		Reason:        domain.StockReasonAdjustment,
+		ReferenceType: domain.PriceSourceManual,
+	}})
+}
+
+// recordPriceChange adds a row to the price history. variantID is nil for
+// a change of the product price itself.
+func recordPriceChange(ctx context.Context, tx *sql.Tx, productID int, variantID *int, oldPrice int, newPrice int, source string, changedBy string) error {
+	_, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO price_history (product_id, variant_id, old_price, new_price, source, changed_by) VALUES ($1, $2, $3, $4, $5, $6)",
+		productID,
+		variantID,
+		oldPrice,
+		newPrice,
+		source,
+		changedBy,
+	)
+	return err
+}
+
+func (p *ProductRepositoryImpl) PatchProduct(ctx context.Context, id int, patch *domain.ProductPatch, versions []int) (*domain.Product, error) {
+	var sets []string
+	var args []interface{}
+
+	set := func(column string, value interface{}) {
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	if patch.Name != nil {
+		set("name", *patch.Name)
+	}
+	if patch.Type != nil {
+		set("type", *patch.Type)
+	}
+	if patch.Price != nil {
+		set("price", *patch.Price)
+	}
+	if patch.Cost != nil {
+		set("cost", *patch.Cost)
+	}
+	if patch.BaseUnit != nil {
+		set("base_unit", *patch.BaseUnit)
+	}
+
+	if len(sets) == 0 && patch.Stock == nil {
+		product, err := p.GetProductByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if versions != nil && !slices.Contains(versions, product.Version) {
+			return nil, utils.ErrVersionMismatch
+		}
+		return product, nil
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	old, err := lockProduct(ctx, tx, id, versions)
+	if err != nil {
+		return nil, err
+	}
+
+	// stock is changed by an adjustment, and left alone for a product that
+	// is a bundle or recipe after the patch
+	after := domain.Product{Type: old.Type}
+	if patch.Type != nil {
+		after.Type = *patch.Type
+	}
+	if patch.Stock != nil && !after.IsComposite() {
+		if err := adjustStock(ctx, tx, id, nil, old.Stock, *patch.Stock); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(sets) > 0 {
+		args = append(args, id)
+		query := fmt.Sprintf(
+			"UPDATE products SET %s, version = version + 1 WHERE id = $%d",
+			strings.Join(sets, ", "),
+			len(args),
+		)
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.Price != nil && *patch.Price != old.Price {
+		changedBy := utils.ActorFromContext(ctx)
+		if err := recordPriceChange(ctx, tx, id, nil, old.Price, *patch.Price, domain.PriceSourceManual, changedBy); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return p.GetProductByID(ctx, id)
+}
+
+func (p *ProductRepositoryImpl) DeleteProduct(ctx context.Context, id int, versions []int) error {
+	query := "DELETE FROM products WHERE id = $1 AND ($2::int[] IS NULL OR version = ANY($2::int[]))"
+	result, err := p.db.ExecContext(ctx, query, id, pq.Array(versions))
+	if isForeignKeyViolation(err) {
+		return utils.ErrProductInUse
+	}
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
 		return err
+	} else if affected == 0 {
+		var exists bool
+		if err := p.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)", id).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return utils.ErrProductNotFound
+		}
+		return utils.ErrVersionMismatch
 	}
 	return nil
 }
+
+func (p *ProductRepositoryImpl) BulkUpdateProducts(
+	ctx context.Context,
+	filter domain.ProductFilter,
+	apply func(*domain.Product) error,
+	source string,
+	preview bool,
+) ([]domain.BulkProductUpdateItem, error) {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	where, args := buildProductFilter(filter)
+	rows, err := tx.QueryContext(ctx, productSelectQuery+where+" ORDER BY products.id FOR UPDATE OF products", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var products []domain.Product
+	for rows.Next() {
+		var product domain.Product
+		if err := scanProduct(rows, &product); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	changedBy := utils.ActorFromContext(ctx)
+	items := make([]domain.BulkProductUpdateItem, 0, len(products))
+	for _, product := range products {
+		updated := product
+		if err := apply(&updated); err != nil {
+			return nil, err
+		}
+
+		items = append(items, domain.BulkProductUpdateItem{
+			ProductID: product.ID,
+			Name:      product.Name,
+			OldPrice:  product.Price,
+			NewPrice:  updated.Price,
+			OldStock:  product.Stock,
+			NewStock:  updated.Stock,
+		})
+
+		if preview {
+			continue
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			"UPDATE products SET price = $1, stock = $2, version = version + 1 WHERE id = $3",
+			updated.Price,
+			updated.Stock,
+			product.ID,
+		); err != nil {
+			return nil, err
+		}
+
+		if updated.Stock != product.Stock {
+			if _, err := tx.ExecContext(
+				ctx,
+				"INSERT INTO stock_movements (product_id, quantity, reason, reference_type) VALUES ($1, $2, $3, $4)",
+				product.ID,
+				updated.Stock-product.Stock,
+				domain.StockReasonAdjustment,
+				source,
+			); err != nil {
+				return nil, err
+			}
+		}
+
+		if updated.Price != product.Price {
+			if err := recordPriceChange(ctx, tx, product.ID, nil, product.Price, updated.Price, source, changedBy); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if preview {
+		return items, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (p *ProductRepositoryImpl) GetComponents(ctx context.Context, productIDs []int) ([]domain.ProductComponent, error) {
+	query := `
+		SELECT pc.product_id, pc.component_product_id, products.name, pc.quantity, products.stock
+		FROM product_components pc
+		JOIN products ON products.id = pc.component_product_id
+		WHERE pc.product_id = ANY($1)
+		ORDER BY pc.product_id, pc.component_product_id`
+
+	rows, err := p.db.QueryContext(ctx, query, pq.Array(productIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var components []domain.ProductComponent
+	for rows.Next() {
+		var component domain.ProductComponent
+		if err := rows.Scan(
+			&component.ProductID,
+			&component.ComponentID,
+			&component.Name,
+			&component.Quantity,
+			&component.Stock,
+		); err != nil {
+			return nil, err
+		}
+		components = append(components, component)
+	}
+	return components, rows.Err()
+}
+
+// IsComponent reports whether the product is a component of a bundle or
+// recipe, or the ingredient of a modifier.
+func (p *ProductRepositoryImpl) IsComponent(ctx context.Context, productID int) (bool, error) {
+	var isComponent bool
+	err := p.db.QueryRowContext(
+		ctx,
+		`SELECT EXISTS (SELECT 1 FROM product_components WHERE component_product_id = $1)
+			OR EXISTS (SELECT 1 FROM modifiers WHERE ingredient_product_id = $1)`,
+		productID,
+	).Scan(&isComponent)
+	return isComponent, err
+}
+
+func (p *ProductRepositoryImpl) ReplaceComponents(ctx context.Context, productID int, components []domain.ProductComponent) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM product_components WHERE product_id = $1", productID); err != nil {
+		return err
+	}
+
+	for _, component := range components {
+		if _, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO product_components (product_id, component_product_id, quantity) VALUES ($1, $2, $3)",
+			productID,
+			component.ComponentID,
+			component.Quantity,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
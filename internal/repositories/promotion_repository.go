@@ -0,0 +1,274 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	domain "kasir-api/internal/domains"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type PromotionRepository interface {
+	GetPromotions(ctx context.Context, page int, pageSize int) ([]domain.Promotion, int, error)
+	GetPromotionByID(ctx context.Context, id int) (*domain.Promotion, error)
+	GetActivePromotions(ctx context.Context, at time.Time) ([]domain.Promotion, error)
+	CreatePromotion(ctx context.Context, promotion *domain.Promotion) (*domain.Promotion, error)
+	UpdatePromotion(ctx context.Context, id int, promotion *domain.Promotion) (*domain.Promotion, error)
+	DeletePromotion(ctx context.Context, id int) error
+}
+
+type PromotionRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewPromotionRepository(db *sql.DB) PromotionRepository {
+	return &PromotionRepositoryImpl{db: db}
+}
+
+const promotionSelectQuery = `
+	SELECT
+		id, name, type, scope, value, buy_quantity, get_quantity, bundle_quantity, bundle_price,
+		min_spend, starts_at, ends_at, priority, stackable, active
+	FROM promotions`
+
+func (r *PromotionRepositoryImpl) GetPromotions(ctx context.Context, page int, pageSize int) ([]domain.Promotion, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM promotions").Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	promotions, err := r.queryPromotions(ctx, promotionSelectQuery+" ORDER BY id LIMIT $1 OFFSET $2", pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	return promotions, total, nil
+}
+
+func (r *PromotionRepositoryImpl) GetPromotionByID(ctx context.Context, id int) (*domain.Promotion, error) {
+	promotions, err := r.queryPromotions(ctx, promotionSelectQuery+" WHERE id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(promotions) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &promotions[0], nil
+}
+
+// GetActivePromotions returns the promotions valid at the given time, in the
+// order checkout should evaluate them.
+func (r *PromotionRepositoryImpl) GetActivePromotions(ctx context.Context, at time.Time) ([]domain.Promotion, error) {
+	query := promotionSelectQuery + `
+		WHERE active
+			AND (starts_at IS NULL OR starts_at <= $1)
+			AND (ends_at IS NULL OR ends_at > $1)
+		ORDER BY priority DESC, id`
+
+	return r.queryPromotions(ctx, query, at)
+}
+
+func (r *PromotionRepositoryImpl) queryPromotions(ctx context.Context, query string, args ...interface{}) ([]domain.Promotion, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promotions []domain.Promotion
+	for rows.Next() {
+		var promotion domain.Promotion
+		if err := rows.Scan(
+			&promotion.ID,
+			&promotion.Name,
+			&promotion.Type,
+			&promotion.Scope,
+			&promotion.Value,
+			&promotion.BuyQuantity,
+			&promotion.GetQuantity,
+			&promotion.BundleQuantity,
+			&promotion.BundlePrice,
+			&promotion.MinSpend,
+			&promotion.StartsAt,
+			&promotion.EndsAt,
+			&promotion.Priority,
+			&promotion.Stackable,
+			&promotion.Active,
+		); err != nil {
+			return nil, err
+		}
+		promotion.ProductIDs = []int{}
+		promotion.CategoryIDs = []int{}
+		promotions = append(promotions, promotion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(promotions) == 0 {
+		return promotions, nil
+	}
+	return promotions, r.attachTargets(ctx, promotions)
+}
+
+func (r *PromotionRepositoryImpl) attachTargets(ctx context.Context, promotions []domain.Promotion) error {
+	ids := make([]int, len(promotions))
+	index := make(map[int]int, len(promotions))
+	for i, promotion := range promotions {
+		ids[i] = promotion.ID
+		index[promotion.ID] = i
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT promotion_id, product_id, 'product' FROM promotion_products WHERE promotion_id = ANY($1)
+		UNION ALL
+		SELECT promotion_id, category_id, 'category' FROM promotion_categories WHERE promotion_id = ANY($1)
+		ORDER BY 1, 2`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var promotionID, targetID int
+		var kind string
+		if err := rows.Scan(&promotionID, &targetID, &kind); err != nil {
+			return err
+		}
+		promotion := &promotions[index[promotionID]]
+		if kind == "product" {
+			promotion.ProductIDs = append(promotion.ProductIDs, targetID)
+		} else {
+			promotion.CategoryIDs = append(promotion.CategoryIDs, targetID)
+		}
+	}
+	return rows.Err()
+}
+
+func (r *PromotionRepositoryImpl) CreatePromotion(ctx context.Context, promotion *domain.Promotion) (*domain.Promotion, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO promotions (
+			name, type, scope, value, buy_quantity, get_quantity, bundle_quantity, bundle_price,
+			min_spend, starts_at, ends_at, priority, stackable, active
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING id`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		promotion.Name,
+		promotion.Type,
+		promotion.Scope,
+		promotion.Value,
+		promotion.BuyQuantity,
+		promotion.GetQuantity,
+		promotion.BundleQuantity,
+		promotion.BundlePrice,
+		promotion.MinSpend,
+		promotion.StartsAt,
+		promotion.EndsAt,
+		promotion.Priority,
+		promotion.Stackable,
+		promotion.Active,
+	).Scan(&promotion.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := replacePromotionTargets(ctx, tx, promotion); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+func (r *PromotionRepositoryImpl) UpdatePromotion(ctx context.Context, id int, promotion *domain.Promotion) (*domain.Promotion, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE promotions
+		SET name = $1, type = $2, scope = $3, value = $4, buy_quantity = $5, get_quantity = $6,
+			bundle_quantity = $7, bundle_price = $8, min_spend = $9, starts_at = $10, ends_at = $11,
+			priority = $12, stackable = $13, active = $14
+		WHERE id = $15`
+
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		promotion.Name,
+		promotion.Type,
+		promotion.Scope,
+		promotion.Value,
+		promotion.BuyQuantity,
+		promotion.GetQuantity,
+		promotion.BundleQuantity,
+		promotion.BundlePrice,
+		promotion.MinSpend,
+		promotion.StartsAt,
+		promotion.EndsAt,
+		promotion.Priority,
+		promotion.Stackable,
+		promotion.Active,
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	promotion.ID = id
+	if err := replacePromotionTargets(ctx, tx, promotion); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+func replacePromotionTargets(ctx context.Context, tx *sql.Tx, promotion *domain.Promotion) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM promotion_products WHERE promotion_id = $1", promotion.ID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM promotion_categories WHERE promotion_id = $1", promotion.ID); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO promotion_products (promotion_id, product_id) SELECT $1, unnest($2::int[]) ON CONFLICT DO NOTHING",
+		promotion.ID,
+		pq.Array(promotion.ProductIDs),
+	); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO promotion_categories (promotion_id, category_id) SELECT $1, unnest($2::int[]) ON CONFLICT DO NOTHING",
+		promotion.ID,
+		pq.Array(promotion.CategoryIDs),
+	)
+	return err
+}
+
+func (r *PromotionRepositoryImpl) DeletePromotion(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM promotions WHERE id = $1", id)
+	return err
+}
@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	domain "kasir-api/internal/domains"
+)
+
+type PurchaseRepository interface {
+	CreatePurchase(ctx context.Context, purchase *domain.Purchase) (*domain.Purchase, error)
+	GetPurchases(ctx context.Context, page int, pageSize int) ([]domain.Purchase, int, error)
+	GetPurchaseByID(ctx context.Context, id int) (*domain.Purchase, error)
+}
+
+type PurchaseRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewPurchaseRepository(db *sql.DB) PurchaseRepository {
+	return &PurchaseRepositoryImpl{db: db}
+}
+
+// CreatePurchase stores the purchase and adds its stock in a single database
+// transaction.
+func (r *PurchaseRepositoryImpl) CreatePurchase(ctx context.Context, purchase *domain.Purchase) (*domain.Purchase, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(
+		ctx,
+		"INSERT INTO purchases (supplier, total_cost) VALUES ($1, $2) RETURNING id, created_at",
+		purchase.Supplier,
+		purchase.TotalCost,
+	).Scan(&purchase.ID, &purchase.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range purchase.Items {
+		item := &purchase.Items[i]
+		item.PurchaseID = purchase.ID
+		err := tx.QueryRowContext(
+			ctx,
+			`INSERT INTO purchase_items
+				(purchase_id, product_id, variant_id, unit_id, unit_name, quantity, base_quantity, unit_cost, subtotal)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`,
+			item.PurchaseID,
+			item.ProductID,
+			item.VariantID,
+			item.UnitID,
+			item.UnitName,
+			item.Quantity,
+			item.BaseQuantity,
+			item.UnitCost,
+			item.Subtotal,
+		).Scan(&item.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range purchase.StockMovements {
+		purchase.StockMovements[i].ReferenceType = "purchase"
+		purchase.StockMovements[i].ReferenceID = &purchase.ID
+	}
+	if err := applyStockMovements(ctx, tx, purchase.StockMovements); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return purchase, nil
+}
+
+func (r *PurchaseRepositoryImpl) GetPurchases(ctx context.Context, page int, pageSize int) ([]domain.Purchase, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM purchases").Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		"SELECT id, supplier, total_cost, created_at FROM purchases ORDER BY id DESC LIMIT $1 OFFSET $2",
+		pageSize,
+		(page-1)*pageSize,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var purchases []domain.Purchase
+	for rows.Next() {
+		var purchase domain.Purchase
+		if err := rows.Scan(&purchase.ID, &purchase.Supplier, &purchase.TotalCost, &purchase.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		purchases = append(purchases, purchase)
+	}
+	return purchases, total, rows.Err()
+}
+
+func (r *PurchaseRepositoryImpl) GetPurchaseByID(ctx context.Context, id int) (*domain.Purchase, error) {
+	var purchase domain.Purchase
+	err := r.db.QueryRowContext(
+		ctx,
+		"SELECT id, supplier, total_cost, created_at FROM purchases WHERE id = $1",
+		id,
+	).Scan(&purchase.ID, &purchase.Supplier, &purchase.TotalCost, &purchase.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, purchase_id, product_id, variant_id, unit_id, unit_name, quantity, base_quantity, unit_cost, subtotal
+		FROM purchase_items WHERE purchase_id = $1 ORDER BY id`,
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.PurchaseItem
+		if err := rows.Scan(
+			&item.ID,
+			&item.PurchaseID,
+			&item.ProductID,
+			&item.VariantID,
+			&item.UnitID,
+			&item.UnitName,
+			&item.Quantity,
+			&item.BaseQuantity,
+			&item.UnitCost,
+			&item.Subtotal,
+		); err != nil {
+			return nil, err
+		}
+		purchase.Items = append(purchase.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &purchase, nil
+}
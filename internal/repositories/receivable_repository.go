@@ -0,0 +1,304 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+	"time"
+)
+
+type ReceivableRepository interface {
+	SetCreditLimit(ctx context.Context, customerID int, limit int) error
+	GetReceivableAccount(ctx context.Context, customerID int, page int, pageSize int) (*domain.ReceivableAccount, int, error)
+	GetOpenReceivables(ctx context.Context, asOf time.Time) ([]domain.OpenReceivable, error)
+	RecordRepayment(ctx context.Context, repayment *domain.Repayment) ([]domain.ReceivableEntry, error)
+}
+
+type ReceivableRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewReceivableRepository(db *sql.DB) ReceivableRepository {
+	return &ReceivableRepositoryImpl{db: db}
+}
+
+// openReceivablesQuery sums the ledger per transaction before $2, optionally
+// for the single customer $1, and keeps the transactions still owed.
+const openReceivablesQuery = `
+	SELECT
+		receivable_ledger.transaction_id,
+		receivable_ledger.customer_id,
+		customers.name,
+		COALESCE(SUM(receivable_ledger.amount) FILTER (WHERE receivable_ledger.type = 'charge'), 0),
+		SUM(receivable_ledger.amount),
+		transactions.created_at
+	FROM receivable_ledger
+	JOIN transactions ON transactions.id = receivable_ledger.transaction_id
+	JOIN customers ON customers.id = receivable_ledger.customer_id
+	WHERE ($1::int IS NULL OR receivable_ledger.customer_id = $1) AND receivable_ledger.created_at < $2
+	GROUP BY receivable_ledger.transaction_id, receivable_ledger.customer_id, customers.name, transactions.created_at
+	HAVING SUM(receivable_ledger.amount) > 0
+	ORDER BY transactions.created_at, receivable_ledger.transaction_id`
+
+func scanOpenReceivables(rows *sql.Rows) ([]domain.OpenReceivable, error) {
+	defer rows.Close()
+
+	var open []domain.OpenReceivable
+	for rows.Next() {
+		var receivable domain.OpenReceivable
+		if err := rows.Scan(
+			&receivable.TransactionID,
+			&receivable.CustomerID,
+			&receivable.CustomerName,
+			&receivable.Amount,
+			&receivable.Outstanding,
+			&receivable.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		open = append(open, receivable)
+	}
+	return open, rows.Err()
+}
+
+func (r *ReceivableRepositoryImpl) SetCreditLimit(ctx context.Context, customerID int, limit int) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE customers SET credit_limit = $1 WHERE id = $2", limit, customerID)
+	return err
+}
+
+// GetReceivableAccount returns what a customer owes, their open
+// transactions oldest first and a page of their ledger, newest first.
+func (r *ReceivableRepositoryImpl) GetReceivableAccount(ctx context.Context, customerID int, page int, pageSize int) (*domain.ReceivableAccount, int, error) {
+	account := &domain.ReceivableAccount{CustomerID: customerID}
+	err := r.db.QueryRowContext(
+		ctx,
+		"SELECT credit_limit, credit_balance FROM customers WHERE id = $1",
+		customerID,
+	).Scan(&account.CreditLimit, &account.Balance)
+	if err != nil {
+		return nil, 0, err
+	}
+	account.AvailableCredit = max(0, account.CreditLimit-account.Balance)
+
+	rows, err := r.db.QueryContext(ctx, openReceivablesQuery, customerID, time.Now())
+	if err != nil {
+		return nil, 0, err
+	}
+	if account.Open, err = scanOpenReceivables(rows); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM receivable_ledger WHERE customer_id = $1", customerID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err = r.db.QueryContext(
+		ctx,
+		`SELECT id, customer_id, transaction_id, type, amount, note, created_by, created_at
+		FROM receivable_ledger WHERE customer_id = $1
+		ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`,
+		customerID,
+		pageSize,
+		(page-1)*pageSize,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry domain.ReceivableEntry
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.CustomerID,
+			&entry.TransactionID,
+			&entry.Type,
+			&entry.Amount,
+			&entry.Note,
+			&entry.CreatedBy,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		account.Entries = append(account.Entries, entry)
+	}
+	return account, total, rows.Err()
+}
+
+// GetOpenReceivables returns every transaction of every customer that was
+// still owed just before the given time, oldest first.
+func (r *ReceivableRepositoryImpl) GetOpenReceivables(ctx context.Context, asOf time.Time) ([]domain.OpenReceivable, error) {
+	rows, err := r.db.QueryContext(ctx, openReceivablesQuery, nil, asOf)
+	if err != nil {
+		return nil, err
+	}
+	return scanOpenReceivables(rows)
+}
+
+// RecordRepayment settles the given transaction, or else the customer's
+// oldest open transactions first, and returns one ledger entry per
+// transaction paid. Paying more than is owed fails with
+// ErrRepaymentTooLarge.
+func (r *ReceivableRepositoryImpl) RecordRepayment(ctx context.Context, repayment *domain.Repayment) ([]domain.ReceivableEntry, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, _, err := lockCredit(ctx, tx, repayment.CustomerID); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, openReceivablesQuery, repayment.CustomerID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	open, err := scanOpenReceivables(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if repayment.TransactionID != nil {
+		var matched []domain.OpenReceivable
+		for _, receivable := range open {
+			if receivable.TransactionID == *repayment.TransactionID {
+				matched = append(matched, receivable)
+			}
+		}
+		if len(matched) == 0 {
+			return nil, utils.ErrReceivableNotFound
+		}
+		open = matched
+	}
+
+	owed := 0
+	for _, receivable := range open {
+		owed += receivable.Outstanding
+	}
+	if repayment.Amount > owed {
+		return nil, utils.ErrRepaymentTooLarge
+	}
+
+	var entries []domain.ReceivableEntry
+	left := repayment.Amount
+	for _, receivable := range open {
+		if left == 0 {
+			break
+		}
+		paid := min(left, receivable.Outstanding)
+		entry, err := insertReceivableEntry(
+			ctx,
+			tx,
+			repayment.CustomerID,
+			receivable.TransactionID,
+			domain.ReceivableEntryRepayment,
+			-paid,
+			repayment.Note,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+		left -= paid
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// lockCredit locks the customer row, serialising every change to what they
+// owe, and returns their credit limit and balance.
+func lockCredit(ctx context.Context, tx *sql.Tx, customerID int) (int, int, error) {
+	var limit, balance int
+	err := tx.QueryRowContext(
+		ctx,
+		"SELECT credit_limit, credit_balance FROM customers WHERE id = $1 FOR UPDATE",
+		customerID,
+	).Scan(&limit, &balance)
+	return limit, balance, err
+}
+
+// chargeAccount puts the on-account part of a sale on the customer's tab,
+// failing with ErrCreditLimitExceeded when it would go over their limit.
+func chargeAccount(ctx context.Context, tx *sql.Tx, customerID int, transactionID int, amount int) error {
+	limit, balance, err := lockCredit(ctx, tx, customerID)
+	if err != nil {
+		return err
+	}
+	if exceedsCreditLimit(limit, balance, amount) {
+		return utils.ErrCreditLimitExceeded
+	}
+	_, err = insertReceivableEntry(ctx, tx, customerID, transactionID, domain.ReceivableEntryCharge, amount, "")
+	return err
+}
+
+// exceedsCreditLimit reports whether charging amount to a customer who owes
+// balance would take them over their limit. Reaching it exactly is allowed.
+func exceedsCreditLimit(limit int, balance int, amount int) bool {
+	return balance+amount > limit
+}
+
+// reverseReceivable writes off what is still owed on a refunded
+// transaction. Repayments already made are left in the ledger.
+func reverseReceivable(ctx context.Context, tx *sql.Tx, customerID int, transactionID int) error {
+	if _, _, err := lockCredit(ctx, tx, customerID); err != nil {
+		return err
+	}
+
+	var outstanding int
+	err := tx.QueryRowContext(
+		ctx,
+		"SELECT COALESCE(SUM(amount), 0) FROM receivable_ledger WHERE transaction_id = $1",
+		transactionID,
+	).Scan(&outstanding)
+	if err != nil || outstanding <= 0 {
+		return err
+	}
+	_, err = insertReceivableEntry(ctx, tx, customerID, transactionID, domain.ReceivableEntryReverse, -outstanding, "refund")
+	return err
+}
+
+// insertReceivableEntry records a ledger entry by the current actor and
+// moves the customer's balance by its amount.
+func insertReceivableEntry(
+	ctx context.Context,
+	tx *sql.Tx,
+	customerID int,
+	transactionID int,
+	entryType string,
+	amount int,
+	note string,
+) (*domain.ReceivableEntry, error) {
+	entry := &domain.ReceivableEntry{
+		CustomerID:    customerID,
+		TransactionID: transactionID,
+		Type:          entryType,
+		Amount:        amount,
+		Note:          note,
+		CreatedBy:     utils.ActorFromContext(ctx),
+	}
+	err := tx.QueryRowContext(
+		ctx,
+		`INSERT INTO receivable_ledger (customer_id, transaction_id, type, amount, note, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`,
+		entry.CustomerID,
+		entry.TransactionID,
+		entry.Type,
+		entry.Amount,
+		entry.Note,
+		entry.CreatedBy,
+	).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE customers SET credit_balance = credit_balance + $1 WHERE id = $2", amount, customerID); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
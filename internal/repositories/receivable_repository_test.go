@@ -0,0 +1,25 @@
+package repositories
+
+import "testing"
+
+func TestExceedsCreditLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int
+		balance int
+		amount  int
+		exceeds bool
+	}{
+		{name: "within the limit", limit: 100000, balance: 20000, amount: 50000},
+		{name: "up to the limit", limit: 100000, balance: 20000, amount: 80000},
+		{name: "over the limit", limit: 100000, balance: 20000, amount: 80001, exceeds: true},
+		{name: "no credit", limit: 0, amount: 1, exceeds: true},
+		{name: "already over a lowered limit", limit: 10000, balance: 20000, amount: 1, exceeds: true},
+	}
+
+	for _, test := range tests {
+		if exceeds := exceedsCreditLimit(test.limit, test.balance, test.amount); exceeds != test.exceeds {
+			t.Errorf("%s: exceeds %v, want %v", test.name, exceeds, test.exceeds)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+
+	"github.com/lib/pq"
+)
+
+type RoleRepository interface {
+	GetRoles(ctx context.Context) ([]domain.Role, error)
+	GetRoleByID(ctx context.Context, id int) (*domain.Role, error)
+	CreateRole(ctx context.Context, role *domain.Role) (*domain.Role, error)
+	UpdateRole(ctx context.Context, role *domain.Role) (*domain.Role, error)
+	DeleteRole(ctx context.Context, id int) error
+	SetUserRoles(ctx context.Context, userID int, roles []string) error
+}
+
+type RoleRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewRoleRepository(db *sql.DB) RoleRepository {
+	return &RoleRepositoryImpl{db: db}
+}
+
+const roleSelectQuery = `
+	SELECT
+		roles.id, roles.name, roles.description, roles.created_at,
+		ARRAY(SELECT permission FROM role_permissions WHERE role_id = roles.id ORDER BY permission),
+		(SELECT COUNT(*) FROM user_roles WHERE role_id = roles.id)
+	FROM roles`
+
+func scanRole(scanner rowScanner, role *domain.Role) error {
+	return scanner.Scan(
+		&role.ID,
+		&role.Name,
+		&role.Description,
+		&role.CreatedAt,
+		pq.Array(&role.Permissions),
+		&role.Users,
+	)
+}
+
+func (r *RoleRepositoryImpl) GetRoles(ctx context.Context) ([]domain.Role, error) {
+	rows, err := r.db.QueryContext(ctx, roleSelectQuery+" ORDER BY roles.name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []domain.Role
+	for rows.Next() {
+		var role domain.Role
+		if err := scanRole(rows, &role); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+func (r *RoleRepositoryImpl) GetRoleByID(ctx context.Context, id int) (*domain.Role, error) {
+	var role domain.Role
+	if err := scanRole(r.db.QueryRowContext(ctx, roleSelectQuery+" WHERE roles.id = $1", id), &role); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *RoleRepositoryImpl) CreateRole(ctx context.Context, role *domain.Role) (*domain.Role, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(
+		ctx,
+		"INSERT INTO roles (name, description) VALUES ($1, $2) RETURNING id, created_at",
+		role.Name,
+		role.Description,
+	).Scan(&role.ID, &role.CreatedAt)
+	if isUniqueViolation(err) {
+		return nil, utils.ErrDuplicateRole
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := insertRolePermissions(ctx, tx, role.ID, role.Permissions); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// UpdateRole renames a role and replaces its permissions. Users holding
+// the role get the new permissions on their next request.
+func (r *RoleRepositoryImpl) UpdateRole(ctx context.Context, role *domain.Role) (*domain.Role, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(
+		ctx,
+		"UPDATE roles SET name = $1, description = $2 WHERE id = $3 RETURNING created_at",
+		role.Name,
+		role.Description,
+		role.ID,
+	).Scan(&role.CreatedAt)
+	if isUniqueViolation(err) {
+		return nil, utils.ErrDuplicateRole
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, utils.ErrRoleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM role_permissions WHERE role_id = $1", role.ID); err != nil {
+		return nil, err
+	}
+	if err := insertRolePermissions(ctx, tx, role.ID, role.Permissions); err != nil {
+		return nil, err
+	}
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM user_roles WHERE role_id = $1", role.ID).Scan(&role.Users); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// DeleteRole removes a role that no user holds anymore, failing with
+// ErrRoleInUse otherwise.
+func (r *RoleRepositoryImpl) DeleteRole(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(
+		ctx,
+		"DELETE FROM roles WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM user_roles WHERE role_id = $1)",
+		id,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		if _, err := r.GetRoleByID(ctx, id); err != nil {
+			return utils.ErrRoleNotFound
+		}
+		return utils.ErrRoleInUse
+	}
+	return nil
+}
+
+func (r *RoleRepositoryImpl) SetUserRoles(ctx context.Context, userID int, roles []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := setUserRoles(ctx, tx, userID, roles); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// setUserRoles replaces the roles of a user with the distinct role names
+// given, failing with ErrRoleNotFound if any of them does not exist.
+func setUserRoles(ctx context.Context, tx *sql.Tx, userID int, roles []string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM user_roles WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO user_roles (user_id, role_id) SELECT $1, id FROM roles WHERE name = ANY($2)",
+		userID,
+		pq.Array(roles),
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if int(affected) != len(roles) {
+		return utils.ErrRoleNotFound
+	}
+	return nil
+}
+
+func insertRolePermissions(ctx context.Context, tx *sql.Tx, roleID int, permissions []string) error {
+	_, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO role_permissions (role_id, permission) SELECT $1, UNNEST($2::text[])",
+		roleID,
+		pq.Array(permissions),
+	)
+	return err
+}
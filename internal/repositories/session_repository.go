@@ -0,0 +1,219 @@
+package repositories
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type SessionRepository interface {
+	CreateSession(ctx context.Context, session *domain.Session, tokenHash string, expiresAt time.Time) error
+	RotateRefreshToken(ctx context.Context, tokenHash string, terminalKeyHash string, newTokenHash string, expiresAt time.Time) (*domain.Session, error)
+	GetActiveSession(ctx context.Context, id string) (*domain.Session, *domain.User, error)
+	RevokeSession(ctx context.Context, id string) error
+	RevokeUserSessions(ctx context.Context, userID int) error
+}
+
+type SessionRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewSessionRepository(db *sql.DB) SessionRepository {
+	return &SessionRepositoryImpl{db: db}
+}
+
+// CreateSession stores a new session with its first refresh token. A
+// session on a terminal replaces the one of the cashier before.
+func (r *SessionRepositoryImpl) CreateSession(ctx context.Context, session *domain.Session, tokenHash string, expiresAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if session.TerminalID != nil {
+		_, err := tx.ExecContext(
+			ctx,
+			"UPDATE sessions SET revoked_at = NOW() WHERE terminal_id = $1 AND revoked_at IS NULL",
+			*session.TerminalID,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.QueryRowContext(
+		ctx,
+		"INSERT INTO sessions (id, tenant_id, user_id, terminal_id) VALUES ($1, $2, $3, $4) RETURNING created_at",
+		session.ID,
+		session.TenantID,
+		session.UserID,
+		session.TerminalID,
+	).Scan(&session.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if err := insertRefreshToken(ctx, tx, session.ID, tokenHash, expiresAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RotateRefreshToken spends a refresh token and stores its replacement in
+// the same session. A token that was already spent means it was stolen or
+// replayed, so the whole session is revoked. Every failure is reported as
+// ErrInvalidRefreshToken. The token of a PIN session is only spent together
+// with the key hash of its still active terminal, read in the session's
+// tenant; with a wrong or missing key the token is left as it was.
+func (r *SessionRepositoryImpl) RotateRefreshToken(ctx context.Context, tokenHash string, terminalKeyHash string, newTokenHash string, expiresAt time.Time) (*domain.Session, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var tokenID int
+	var tokenExpiresAt time.Time
+	var usedAt *time.Time
+	var session domain.Session
+	err = tx.QueryRowContext(
+		ctx,
+		`SELECT
+			refresh_tokens.id, refresh_tokens.expires_at, refresh_tokens.used_at,
+			sessions.id, sessions.tenant_id, sessions.user_id, sessions.terminal_id, sessions.created_at, sessions.revoked_at
+		FROM refresh_tokens
+		JOIN sessions ON sessions.id = refresh_tokens.session_id
+		WHERE refresh_tokens.token_hash = $1
+		FOR UPDATE OF refresh_tokens, sessions`,
+		tokenHash,
+	).Scan(
+		&tokenID,
+		&tokenExpiresAt,
+		&usedAt,
+		&session.ID,
+		&session.TenantID,
+		&session.UserID,
+		&session.TerminalID,
+		&session.CreatedAt,
+		&session.RevokedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, utils.ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if session.TerminalID != nil {
+		err := tx.QueryRowContext(
+			utils.WithTenantID(ctx, session.TenantID),
+			"SELECT key_hash FROM terminals WHERE id = $1 AND active",
+			*session.TerminalID,
+		).Scan(&session.TerminalKeyHash)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		if session.TerminalKeyHash == "" || subtle.ConstantTimeCompare([]byte(terminalKeyHash), []byte(session.TerminalKeyHash)) != 1 {
+			return nil, utils.ErrInvalidRefreshToken
+		}
+	}
+	if session.RevokedAt != nil {
+		return nil, utils.ErrInvalidRefreshToken
+	}
+
+	if usedAt != nil {
+		if _, err := tx.ExecContext(ctx, "UPDATE sessions SET revoked_at = NOW() WHERE id = $1", session.ID); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		return nil, utils.ErrInvalidRefreshToken
+	}
+	if !tokenExpiresAt.After(time.Now()) {
+		return nil, utils.ErrInvalidRefreshToken
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE refresh_tokens SET used_at = NOW() WHERE id = $1", tokenID); err != nil {
+		return nil, err
+	}
+	if err := insertRefreshToken(ctx, tx, session.ID, newTokenHash, expiresAt); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetActiveSession returns a session that is not revoked together with its
+// user and the permissions of all their roles, as long as the user and the
+// terminal of a PIN session are still active.
+func (r *SessionRepositoryImpl) GetActiveSession(ctx context.Context, id string) (*domain.Session, *domain.User, error) {
+	var session domain.Session
+	var user domain.User
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT
+			sessions.id, sessions.user_id, sessions.terminal_id, sessions.created_at, COALESCE(terminals.key_hash, ''),
+			users.id, users.tenant_id, users.username, users.name, users.active, users.created_at,
+			ARRAY(
+				SELECT DISTINCT role_permissions.permission FROM user_roles
+				JOIN role_permissions ON role_permissions.role_id = user_roles.role_id
+				WHERE user_roles.user_id = users.id
+				ORDER BY role_permissions.permission
+			)
+		FROM sessions
+		JOIN users ON users.id = sessions.user_id
+		LEFT JOIN terminals ON terminals.id = sessions.terminal_id
+		WHERE sessions.id = $1 AND sessions.revoked_at IS NULL AND users.active
+			AND (sessions.terminal_id IS NULL OR terminals.active)`,
+		id,
+	).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.TerminalID,
+		&session.CreatedAt,
+		&session.TerminalKeyHash,
+		&user.ID,
+		&user.TenantID,
+		&user.Username,
+		&user.Name,
+		&user.Active,
+		&user.CreatedAt,
+		pq.Array(&user.Permissions),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &session, &user, nil
+}
+
+func (r *SessionRepositoryImpl) RevokeSession(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL", id)
+	return err
+}
+
+func (r *SessionRepositoryImpl) RevokeUserSessions(ctx context.Context, userID int) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL", userID)
+	return err
+}
+
+func insertRefreshToken(ctx context.Context, tx *sql.Tx, sessionID string, tokenHash string, expiresAt time.Time) error {
+	_, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO refresh_tokens (session_id, token_hash, expires_at) VALUES ($1, $2, $3)",
+		sessionID,
+		tokenHash,
+		expiresAt,
+	)
+	return err
+}
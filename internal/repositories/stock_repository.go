@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+)
+
+type StockRepository interface {
+	GetMovements(ctx context.Context, productID int, page int, pageSize int) ([]domain.StockMovement, int, error)
+}
+
+type StockRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewStockRepository(db *sql.DB) StockRepository {
+	return &StockRepositoryImpl{db: db}
+}
+
+func (r *StockRepositoryImpl) GetMovements(ctx context.Context, productID int, page int, pageSize int) ([]domain.StockMovement, int, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM stock_movements WHERE product_id = $1", productID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT id, product_id, variant_id, quantity, reason, COALESCE(reference_type, ''), reference_id, created_at
+		FROM stock_movements
+		WHERE product_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.QueryContext(ctx, query, productID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var movements []domain.StockMovement
+	for rows.Next() {
+		var movement domain.StockMovement
+		if err := rows.Scan(
+			&movement.ID,
+			&movement.ProductID,
+			&movement.VariantID,
+			&movement.Quantity,
+			&movement.Reason,
+			&movement.ReferenceType,
+			&movement.ReferenceID,
+			&movement.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		movements = append(movements, movement)
+	}
+	return movements, total, rows.Err()
+}
+
+// applyStockMovements writes ledger entries inside tx and updates the stock
+// they affect. Outgoing movements are guarded so stock never goes negative.
+// The audit log records the movements rather than the stock they change.
+func applyStockMovements(ctx context.Context, tx *sql.Tx, movements []domain.StockMovement) error {
+	if _, err := tx.ExecContext(ctx, "SELECT set_config('app.stock_movement', 'on', true)"); err != nil {
+		return err
+	}
+	for _, movement := range movements {
+		var result sql.Result
+		var err error
+		if movement.VariantID != nil {
+			result, err = tx.ExecContext(
+				ctx,
+				"UPDATE product_variants SET stock = stock + $1 WHERE id = $2 AND stock + $1 >= 0",
+				movement.Quantity,
+				*movement.VariantID,
+			)
+		} else {
+			result, err = tx.ExecContext(
+				ctx,
+				"UPDATE products SET stock = stock + $1, version = version + 1 WHERE id = $2 AND stock + $1 >= 0",
+				movement.Quantity,
+				movement.ProductID,
+			)
+		}
+		if err != nil {
+			return err
+		}
+		if affected, err := result.RowsAffected(); err != nil {
+			return err
+		} else if affected == 0 {
+			return fmt.Errorf("%w: product %d", utils.ErrInsufficientStock, movement.ProductID)
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO stock_movements (product_id, variant_id, quantity, reason, reference_type, reference_id)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			movement.ProductID,
+			movement.VariantID,
+			movement.Quantity,
+			movement.Reason,
+			nullableString(movement.ReferenceType),
+			movement.ReferenceID,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	domain "kasir-api/internal/domains"
+
+	"github.com/lib/pq"
+)
+
+type TaxRepository interface {
+	GetTaxRates(ctx context.Context) ([]domain.TaxRate, error)
+	GetTaxRateByID(ctx context.Context, id int) (*domain.TaxRate, error)
+	CreateTaxRate(ctx context.Context, rate *domain.TaxRate) (*domain.TaxRate, error)
+	UpdateTaxRate(ctx context.Context, id int, rate *domain.TaxRate) (*domain.TaxRate, error)
+	DeleteTaxRate(ctx context.Context, id int) error
+	GetStoreSettings(ctx context.Context) (*domain.StoreSettings, error)
+	UpdateStoreSettings(ctx context.Context, settings *domain.StoreSettings) (*domain.StoreSettings, error)
+	SetProductTax(ctx context.Context, productID int, tax *domain.ProductTax) error
+	SetCategoryTax(ctx context.Context, categoryID int, taxRateID *int) error
+	GetEffectiveTaxRates(ctx context.Context, productIDs []int) (map[int]domain.TaxRate, error)
+}
+
+type TaxRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewTaxRepository(db *sql.DB) TaxRepository {
+	return &TaxRepositoryImpl{db: db}
+}
+
+func (r *TaxRepositoryImpl) GetTaxRates(ctx context.Context) ([]domain.TaxRate, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT id, name, rate FROM tax_rates ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rates []domain.TaxRate
+	for rows.Next() {
+		var rate domain.TaxRate
+		if err := rows.Scan(&rate.ID, &rate.Name, &rate.Rate); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+func (r *TaxRepositoryImpl) GetTaxRateByID(ctx context.Context, id int) (*domain.TaxRate, error) {
+	var rate domain.TaxRate
+	err := r.db.QueryRowContext(ctx, "SELECT id, name, rate FROM tax_rates WHERE id = $1", id).Scan(&rate.ID, &rate.Name, &rate.Rate)
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *TaxRepositoryImpl) CreateTaxRate(ctx context.Context, rate *domain.TaxRate) (*domain.TaxRate, error) {
+	err := r.db.QueryRowContext(
+		ctx,
+		"INSERT INTO tax_rates (name, rate) VALUES ($1, $2) RETURNING id",
+		rate.Name,
+		rate.Rate,
+	).Scan(&rate.ID)
+	if err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+func (r *TaxRepositoryImpl) UpdateTaxRate(ctx context.Context, id int, rate *domain.TaxRate) (*domain.TaxRate, error) {
+	err := r.db.QueryRowContext(
+		ctx,
+		"UPDATE tax_rates SET name = $1, rate = $2 WHERE id = $3 RETURNING id",
+		rate.Name,
+		rate.Rate,
+		id,
+	).Scan(&rate.ID)
+	if err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+func (r *TaxRepositoryImpl) DeleteTaxRate(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM tax_rates WHERE id = $1", id)
+	return err
+}
+
+func (r *TaxRepositoryImpl) GetStoreSettings(ctx context.Context) (*domain.StoreSettings, error) {
+	var settings domain.StoreSettings
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT default_tax_rate_id, tax_inclusive, service_charge_rate, service_charge_order
+		FROM store_settings WHERE tenant_id = current_tenant_id()`,
+	).Scan(
+		&settings.DefaultTaxRateID,
+		&settings.TaxInclusive,
+		&settings.ServiceChargeRate,
+		&settings.ServiceChargeOrder,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *TaxRepositoryImpl) UpdateStoreSettings(ctx context.Context, settings *domain.StoreSettings) (*domain.StoreSettings, error) {
+	query := `
+		INSERT INTO store_settings (default_tax_rate_id, tax_inclusive, service_charge_rate, service_charge_order)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			default_tax_rate_id = EXCLUDED.default_tax_rate_id,
+			tax_inclusive = EXCLUDED.tax_inclusive,
+			service_charge_rate = EXCLUDED.service_charge_rate,
+			service_charge_order = EXCLUDED.service_charge_order`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		settings.DefaultTaxRateID,
+		settings.TaxInclusive,
+		settings.ServiceChargeRate,
+		settings.ServiceChargeOrder,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (r *TaxRepositoryImpl) SetProductTax(ctx context.Context, productID int, tax *domain.ProductTax) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		"UPDATE products SET tax_rate_id = $1, tax_exempt = $2, version = version + 1 WHERE id = $3",
+		tax.TaxRateID,
+		tax.TaxExempt,
+		productID,
+	)
+	return err
+}
+
+func (r *TaxRepositoryImpl) SetCategoryTax(ctx context.Context, categoryID int, taxRateID *int) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		"UPDATE categories SET tax_rate_id = $1, version = version + 1 WHERE id = $2",
+		taxRateID,
+		categoryID,
+	)
+	return err
+}
+
+// GetEffectiveTaxRates returns the tax rate of each product, taken from the
+// product itself, then its category, then the store default. Tax-exempt
+// products and products without any rate are left out of the map.
+func (r *TaxRepositoryImpl) GetEffectiveTaxRates(ctx context.Context, productIDs []int) (map[int]domain.TaxRate, error) {
+	query := `
+		SELECT products.id, tax_rates.id, tax_rates.name, tax_rates.rate
+		FROM products
+		JOIN categories ON categories.id = products.category_id
+		LEFT JOIN store_settings ON store_settings.tenant_id = products.tenant_id
+		JOIN tax_rates ON tax_rates.id = COALESCE(
+			products.tax_rate_id,
+			categories.tax_rate_id,
+			store_settings.default_tax_rate_id
+		)
+		WHERE products.id = ANY($1) AND NOT products.tax_exempt`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(productIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := make(map[int]domain.TaxRate, len(productIDs))
+	for rows.Next() {
+		var productID int
+		var rate domain.TaxRate
+		if err := rows.Scan(&productID, &rate.ID, &rate.Name, &rate.Rate); err != nil {
+			return nil, err
+		}
+		rates[productID] = rate
+	}
+	return rates, rows.Err()
+}
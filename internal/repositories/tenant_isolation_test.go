@@ -0,0 +1,231 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/internal/config"
+	"kasir-api/internal/database"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// These tests need a migrated database in TEST_DATABASE_URL and connect to
+// it as the API does, with a role row level security applies to. They seed
+// two fresh tenants and check that one cannot reach the other's rows.
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, closeDB, err := database.NewPostgres(&config.DatabaseConfig{URL: url, MaxOpenConns: 4, MaxIdleConns: 4})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { closeDB() })
+	return db
+}
+
+// seededTenant is a tenant with one row of each kind the tests look for.
+type seededTenant struct {
+	ctx           context.Context
+	id            int
+	username      string
+	productID     int
+	customerID    int
+	transactionID int
+	giftCardCode  string
+	apiKeyID      int
+	apiKeyHash    string
+}
+
+func seedTenant(t *testing.T, db *sql.DB, name string) seededTenant {
+	t.Helper()
+	suffix := fmt.Sprintf("%s-%d", name, time.Now().UnixNano())
+	owner := &domain.User{Username: "owner-" + suffix, Name: name, PasswordHash: "-", Active: true, Roles: []string{domain.RoleOwner}}
+
+	tenant, err := NewTenantRepository(db).CreateTenant(
+		utils.WithTenantID(context.Background(), domain.DefaultTenantID),
+		&domain.Tenant{Name: "tenant-" + suffix},
+		owner,
+	)
+	if err != nil {
+		t.Fatalf("create tenant %s: %v", name, err)
+	}
+
+	s := seededTenant{
+		ctx:          utils.WithTenantID(context.Background(), tenant.ID),
+		id:           tenant.ID,
+		username:     owner.Username,
+		giftCardCode: "GC-" + suffix,
+		apiKeyHash:   fmt.Sprintf("%064d", time.Now().UnixNano()),
+	}
+	insert := func(query string, args ...interface{}) int {
+		t.Helper()
+		var id int
+		if err := db.QueryRowContext(s.ctx, query+" RETURNING id", args...).Scan(&id); err != nil {
+			t.Fatalf("seed %s: %v", name, err)
+		}
+		return id
+	}
+	s.productID = insert("INSERT INTO products (name, price, stock) VALUES ($1, 1000, 5)", "product-"+suffix)
+	s.customerID = insert("INSERT INTO customers (name) VALUES ($1)", "customer-"+suffix)
+	s.transactionID = insert("INSERT INTO transactions (total_amount) VALUES (1000)")
+	insert("INSERT INTO gift_cards (code, balance) VALUES ($1, 5000)", s.giftCardCode)
+	s.apiKeyID = insert(
+		"INSERT INTO api_keys (name, prefix, key_hash, permissions) VALUES ($1, 'kasir_test', $2, $3)",
+		"key-"+suffix,
+		s.apiKeyHash,
+		pq.Array([]string{domain.PermissionProductRead}),
+	)
+	return s
+}
+
+func TestTenantCannotReadOtherTenant(t *testing.T) {
+	db := openTestDB(t)
+	a := seedTenant(t, db, "a")
+	b := seedTenant(t, db, "b")
+
+	if _, err := NewProductRepository(db).GetProductByID(b.ctx, a.productID); err == nil {
+		t.Error("tenant b read a product of tenant a")
+	}
+	if _, err := NewCustomerRepository(db).GetCustomerByID(b.ctx, a.customerID); err == nil {
+		t.Error("tenant b read a customer of tenant a")
+	}
+	if _, err := NewTransactionRepository(db).GetTransactionByID(b.ctx, a.transactionID); err == nil {
+		t.Error("tenant b read a transaction of tenant a")
+	}
+	if _, err := NewGiftCardRepository(db).GetGiftCardByCode(b.ctx, a.giftCardCode); err == nil {
+		t.Error("tenant b read a gift card of tenant a")
+	}
+	if _, err := NewAPIKeyRepository(db).GetAPIKeyByID(b.ctx, a.apiKeyID); err == nil {
+		t.Error("tenant b read an API key of tenant a")
+	}
+	if _, err := NewAPIKeyRepository(db).GetActiveAPIKeyByHash(b.ctx, a.apiKeyHash); err == nil {
+		t.Error("tenant b found an API key of tenant a by its hash")
+	}
+	if _, err := NewUserRepository(db).GetUserByUsername(b.ctx, a.username); err == nil {
+		t.Error("tenant b found a user of tenant a by username")
+	}
+
+	// and the rows are there for their own tenant
+	if _, err := NewProductRepository(db).GetProductByID(a.ctx, a.productID); err != nil {
+		t.Errorf("tenant a cannot read its own product: %v", err)
+	}
+	if _, err := NewGiftCardRepository(db).GetGiftCardByCode(a.ctx, a.giftCardCode); err != nil {
+		t.Errorf("tenant a cannot read its own gift card: %v", err)
+	}
+}
+
+func TestTenantListsOnlyOwnRows(t *testing.T) {
+	db := openTestDB(t)
+	a := seedTenant(t, db, "a")
+	b := seedTenant(t, db, "b")
+
+	for _, table := range []string{"products", "customers", "transactions", "gift_cards", "api_keys", "users", "roles"} {
+		var others int
+		err := db.QueryRowContext(b.ctx, "SELECT COUNT(*) FROM "+table+" WHERE tenant_id <> $1", b.id).Scan(&others)
+		if err != nil {
+			t.Fatalf("%s: %v", table, err)
+		}
+		if others != 0 {
+			t.Errorf("%s: tenant b sees %d rows of other tenants", table, others)
+		}
+	}
+
+	keys, err := NewAPIKeyRepository(db).GetAPIKeys(b.ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys {
+		if key.ID == a.apiKeyID {
+			t.Error("tenant b lists an API key of tenant a")
+		}
+	}
+}
+
+func TestLoginLooksUpTenantOnlyWithoutOne(t *testing.T) {
+	db := openTestDB(t)
+	a := seedTenant(t, db, "a")
+
+	user, err := NewUserRepository(db).GetUserByUsername(context.Background(), a.username)
+	if err != nil {
+		t.Fatalf("login lookup: %v", err)
+	}
+	if user.TenantID != a.id {
+		t.Errorf("user tenant = %d, want %d", user.TenantID, a.id)
+	}
+
+	key, err := NewAPIKeyRepository(db).GetActiveAPIKeyByHash(context.Background(), a.apiKeyHash)
+	if err != nil {
+		t.Fatalf("API key lookup: %v", err)
+	}
+	if key.TenantID != a.id {
+		t.Errorf("API key tenant = %d, want %d", key.TenantID, a.id)
+	}
+
+	if _, err := NewUserRepository(db).GetUserByUsername(context.Background(), "no-such-user"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown username: error %v, want sql.ErrNoRows", err)
+	}
+}
+
+func isRowSecurityViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "42501"
+}
+
+func TestTenantCannotWriteOtherTenant(t *testing.T) {
+	db := openTestDB(t)
+	a := seedTenant(t, db, "a")
+	b := seedTenant(t, db, "b")
+
+	inserts := map[string]string{
+		"products":     "INSERT INTO products (tenant_id, name, price, stock) VALUES ($1, 'stolen', 1, 1)",
+		"customers":    "INSERT INTO customers (tenant_id, name) VALUES ($1, 'stolen')",
+		"transactions": "INSERT INTO transactions (tenant_id, total_amount) VALUES ($1, 1)",
+		"gift_cards":   "INSERT INTO gift_cards (tenant_id, code) VALUES ($1, 'stolen-" + b.username + "')",
+		"api_keys":     "INSERT INTO api_keys (tenant_id, name, prefix, key_hash) VALUES ($1, 'stolen', 'x', repeat('0', 64))",
+		"users":        "INSERT INTO users (tenant_id, username, name, password_hash) VALUES ($1, 'stolen-" + b.username + "', 'x', 'x')",
+	}
+	for table, query := range inserts {
+		if _, err := db.ExecContext(b.ctx, query, a.id); !isRowSecurityViolation(err) {
+			t.Errorf("%s: insert into tenant a from tenant b: error %v, want a row security violation", table, err)
+		}
+	}
+
+	updates := map[string]int{
+		"products":     b.productID,
+		"customers":    b.customerID,
+		"transactions": b.transactionID,
+	}
+	for table, id := range updates {
+		_, err := db.ExecContext(b.ctx, "UPDATE "+table+" SET tenant_id = $1 WHERE id = $2", a.id, id)
+		if !isRowSecurityViolation(err) {
+			t.Errorf("%s: moving a row to tenant a: error %v, want a row security violation", table, err)
+		}
+	}
+
+	result, err := db.ExecContext(b.ctx, "UPDATE products SET price = 1 WHERE id = $1", a.productID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if affected, _ := result.RowsAffected(); affected != 0 {
+		t.Errorf("tenant b updated %d products of tenant a", affected)
+	}
+	result, err = db.ExecContext(b.ctx, "DELETE FROM customers WHERE id = $1", a.customerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if affected, _ := result.RowsAffected(); affected != 0 {
+		t.Errorf("tenant b deleted %d customers of tenant a", affected)
+	}
+}
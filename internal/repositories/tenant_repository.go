@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+)
+
+type TenantRepository interface {
+	GetTenants(ctx context.Context) ([]domain.Tenant, error)
+	CreateTenant(ctx context.Context, tenant *domain.Tenant, owner *domain.User) (*domain.Tenant, error)
+}
+
+type TenantRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewTenantRepository(db *sql.DB) TenantRepository {
+	return &TenantRepositoryImpl{db: db}
+}
+
+func (r *TenantRepositoryImpl) GetTenants(ctx context.Context) ([]domain.Tenant, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT id, name, created_at FROM tenants ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tenants []domain.Tenant
+	for rows.Next() {
+		var tenant domain.Tenant
+		if err := rows.Scan(&tenant.ID, &tenant.Name, &tenant.CreatedAt); err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, tenant)
+	}
+	return tenants, rows.Err()
+}
+
+// CreateTenant stores a tenant with its settings, the default roles and
+// its first user, who gets the roles named in owner.Roles.
+func (r *TenantRepositoryImpl) CreateTenant(ctx context.Context, tenant *domain.Tenant, owner *domain.User) (*domain.Tenant, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(
+		ctx,
+		"INSERT INTO tenants (name) VALUES ($1) RETURNING id, created_at",
+		tenant.Name,
+	).Scan(&tenant.ID, &tenant.CreatedAt)
+	if isUniqueViolation(err) {
+		return nil, utils.ErrDuplicateTenant
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// everything else belongs to the new tenant
+	tenantCtx := utils.WithTenantID(ctx, tenant.ID)
+	if _, err := tx.ExecContext(tenantCtx, "SELECT seed_tenant($1)", tenant.ID); err != nil {
+		return nil, err
+	}
+	if err := insertUser(tenantCtx, tx, owner); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return tenant, nil
+}
+
+// withTenantOf returns ctx scoped to the tenant that lookup, one of the
+// tenant_of_* database functions, finds for key. Row level security hides
+// every row until the tenant is set, so this is how login finds a row
+// before its tenant is known. A ctx that already has a tenant is returned
+// as is, which keeps lookups within it. A key that is not found is
+// reported as sql.ErrNoRows.
+func withTenantOf(ctx context.Context, db *sql.DB, lookup string, key string) (context.Context, error) {
+	if _, ok := utils.TenantIDFromContext(ctx); ok {
+		return ctx, nil
+	}
+
+	var tenantID sql.NullInt64
+	if err := db.QueryRowContext(ctx, "SELECT "+lookup+"($1)", key).Scan(&tenantID); err != nil {
+		return nil, err
+	}
+	if !tenantID.Valid {
+		return nil, sql.ErrNoRows
+	}
+	return utils.WithTenantID(ctx, int(tenantID.Int64)), nil
+}
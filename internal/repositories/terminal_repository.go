@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+)
+
+type TerminalRepository interface {
+	GetTerminals(ctx context.Context) ([]domain.Terminal, error)
+	GetTerminalByID(ctx context.Context, id int) (*domain.Terminal, error)
+	GetTerminalByKeyHash(ctx context.Context, keyHash string) (*domain.Terminal, error)
+	CreateTerminal(ctx context.Context, terminal *domain.Terminal, keyHash string) (*domain.Terminal, error)
+	UpdateTerminal(ctx context.Context, terminal *domain.Terminal) (*domain.Terminal, error)
+	TouchTerminal(ctx context.Context, id int) error
+}
+
+type TerminalRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewTerminalRepository(db *sql.DB) TerminalRepository {
+	return &TerminalRepositoryImpl{db: db}
+}
+
+const terminalSelectQuery = `
+	SELECT id, tenant_id, name, active, last_used_at, created_at
+	FROM terminals`
+
+func scanTerminal(scanner rowScanner, terminal *domain.Terminal) error {
+	return scanner.Scan(&terminal.ID, &terminal.TenantID, &terminal.Name, &terminal.Active, &terminal.LastUsedAt, &terminal.CreatedAt)
+}
+
+func (r *TerminalRepositoryImpl) GetTerminals(ctx context.Context) ([]domain.Terminal, error) {
+	rows, err := r.db.QueryContext(ctx, terminalSelectQuery+" ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var terminals []domain.Terminal
+	for rows.Next() {
+		var terminal domain.Terminal
+		if err := scanTerminal(rows, &terminal); err != nil {
+			return nil, err
+		}
+		terminals = append(terminals, terminal)
+	}
+	return terminals, rows.Err()
+}
+
+func (r *TerminalRepositoryImpl) GetTerminalByID(ctx context.Context, id int) (*domain.Terminal, error) {
+	var terminal domain.Terminal
+	if err := scanTerminal(r.db.QueryRowContext(ctx, terminalSelectQuery+" WHERE id = $1", id), &terminal); err != nil {
+		return nil, err
+	}
+	return &terminal, nil
+}
+
+// GetTerminalByKeyHash finds a terminal in the tenant of ctx, or, without
+// one, in whichever tenant the key belongs to.
+func (r *TerminalRepositoryImpl) GetTerminalByKeyHash(ctx context.Context, keyHash string) (*domain.Terminal, error) {
+	ctx, err := withTenantOf(ctx, r.db, "tenant_of_terminal_key", keyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var terminal domain.Terminal
+	if err := scanTerminal(r.db.QueryRowContext(ctx, terminalSelectQuery+" WHERE key_hash = $1", keyHash), &terminal); err != nil {
+		return nil, err
+	}
+	return &terminal, nil
+}
+
+func (r *TerminalRepositoryImpl) CreateTerminal(ctx context.Context, terminal *domain.Terminal, keyHash string) (*domain.Terminal, error) {
+	err := r.db.QueryRowContext(
+		ctx,
+		"INSERT INTO terminals (name, key_hash, active) VALUES ($1, $2, $3) RETURNING id, created_at",
+		terminal.Name,
+		keyHash,
+		terminal.Active,
+	).Scan(&terminal.ID, &terminal.CreatedAt)
+	if isUniqueViolation(err) {
+		return nil, utils.ErrDuplicateTerminal
+	}
+	if err != nil {
+		return nil, err
+	}
+	return terminal, nil
+}
+
+// UpdateTerminal renames a terminal or switches it on or off. Switching it
+// off ends the session of its active cashier.
+func (r *TerminalRepositoryImpl) UpdateTerminal(ctx context.Context, terminal *domain.Terminal) (*domain.Terminal, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(
+		ctx,
+		"UPDATE terminals SET name = $1, active = $2 WHERE id = $3 RETURNING last_used_at, created_at",
+		terminal.Name,
+		terminal.Active,
+		terminal.ID,
+	).Scan(&terminal.LastUsedAt, &terminal.CreatedAt)
+	if isUniqueViolation(err) {
+		return nil, utils.ErrDuplicateTerminal
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, utils.ErrTerminalNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !terminal.Active {
+		if _, err := tx.ExecContext(ctx, "UPDATE sessions SET revoked_at = NOW() WHERE terminal_id = $1 AND revoked_at IS NULL", terminal.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return terminal, nil
+}
+
+func (r *TerminalRepositoryImpl) TouchTerminal(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE terminals SET last_used_at = NOW() WHERE id = $1", id)
+	return err
+}
@@ -0,0 +1,630 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type TransactionRepository interface {
+	CreateTransaction(ctx context.Context, transaction *domain.Transaction) (*domain.Transaction, error)
+	GetTransactions(ctx context.Context, filter domain.TransactionFilter, page int, pageSize int) ([]domain.Transaction, int, error)
+	GetTransactionByID(ctx context.Context, id int) (*domain.Transaction, error)
+	RefundTransaction(ctx context.Context, id int, pointsExpireAt *time.Time, approvals []domain.Approval) (*domain.Transaction, error)
+}
+
+type TransactionRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewTransactionRepository(db *sql.DB) TransactionRepository {
+	return &TransactionRepositoryImpl{db: db}
+}
+
+// CreateTransaction stores the transaction and applies its stock movements
+// in a single database transaction, so a sale either fully happens or
+// leaves stock untouched.
+func (r *TransactionRepositoryImpl) CreateTransaction(ctx context.Context, transaction *domain.Transaction) (*domain.Transaction, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	transaction.Status = domain.TransactionStatusCompleted
+	err = tx.QueryRowContext(
+		ctx,
+		`INSERT INTO transactions
+			(customer_id, status, subtotal, discount_amount, service_charge, tax_amount, tax_inclusive, gift_card_amount,
+			total_amount, change_amount, on_account_amount, points_earned, points_redeemed, cashier_id, terminal_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id, created_at`,
+		transaction.CustomerID,
+		transaction.Status,
+		transaction.Subtotal,
+		transaction.DiscountAmount,
+		transaction.ServiceCharge,
+		transaction.TaxAmount,
+		transaction.TaxInclusive,
+		transaction.GiftCardAmount,
+		transaction.TotalAmount,
+		transaction.ChangeAmount,
+		transaction.OnAccountAmount,
+		transaction.PointsEarned,
+		transaction.PointsRedeemed,
+		transaction.CashierID,
+		transaction.TerminalID,
+	).Scan(&transaction.ID, &transaction.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range transaction.Items {
+		item := &transaction.Items[i]
+		item.TransactionID = transaction.ID
+		err := tx.QueryRowContext(
+			ctx,
+			`INSERT INTO transaction_items
+				(transaction_id, product_id, variant_id, name, unit_id, unit_name, quantity, base_quantity,
+				unit_price, price_list_id, subtotal, discount_amount, tax_rate, service_charge, tax_amount, original_unit_price)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16) RETURNING id`,
+			item.TransactionID,
+			item.ProductID,
+			item.VariantID,
+			item.Name,
+			item.UnitID,
+			item.UnitName,
+			item.Quantity,
+			item.BaseQuantity,
+			item.UnitPrice,
+			item.PriceListID,
+			item.Subtotal,
+			item.DiscountAmount,
+			item.TaxRate,
+			item.ServiceCharge,
+			item.TaxAmount,
+			item.OriginalUnitPrice,
+		).Scan(&item.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for j := range item.Modifiers {
+			modifier := &item.Modifiers[j]
+			err := tx.QueryRowContext(
+				ctx,
+				`INSERT INTO transaction_item_modifiers (transaction_item_id, modifier_id, name, price_delta)
+				VALUES ($1, $2, $3, $4) RETURNING id`,
+				item.ID,
+				modifier.ModifierID,
+				modifier.Name,
+				modifier.PriceDelta,
+			).Scan(&modifier.ID)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for i := range transaction.Discounts {
+		discount := &transaction.Discounts[i]
+		if discount.ItemIndex >= 0 {
+			discount.TransactionItemID = &transaction.Items[discount.ItemIndex].ID
+		}
+		err := tx.QueryRowContext(
+			ctx,
+			`INSERT INTO transaction_discounts (transaction_id, transaction_item_id, promotion_id, voucher_id, name, amount)
+			VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+			transaction.ID,
+			discount.TransactionItemID,
+			discount.PromotionID,
+			discount.VoucherID,
+			discount.Name,
+			discount.Amount,
+		).Scan(&discount.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if discount.VoucherID != nil {
+			if err := redeemVoucher(ctx, tx, *discount.VoucherID, transaction.CustomerID, transaction.ID, discount.Amount); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for i := range transaction.Payments {
+		payment := &transaction.Payments[i]
+		payment.TransactionID = transaction.ID
+		err := tx.QueryRowContext(
+			ctx,
+			`INSERT INTO transaction_payments (transaction_id, method, amount, points, gift_card_id)
+			VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+			payment.TransactionID,
+			payment.Method,
+			payment.Amount,
+			payment.Points,
+			payment.GiftCardID,
+		).Scan(&payment.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if payment.GiftCardID != nil {
+			_, err := debitGiftCard(ctx, tx, *payment.GiftCardID, &transaction.ID, domain.GiftCardEntryRedeem, payment.Amount)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := sellGiftCards(ctx, tx, transaction.ID, transaction.GiftCards); err != nil {
+		return nil, err
+	}
+	if err := insertApprovals(ctx, tx, transaction.ID, transaction.Approvals); err != nil {
+		return nil, err
+	}
+
+	if transaction.CustomerID != nil {
+		if err := settleLoyaltyPoints(ctx, tx, transaction); err != nil {
+			return nil, err
+		}
+		if transaction.OnAccountAmount > 0 {
+			if err := chargeAccount(ctx, tx, *transaction.CustomerID, transaction.ID, transaction.OnAccountAmount); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for i := range transaction.StockMovements {
+		transaction.StockMovements[i].ReferenceType = "transaction"
+		transaction.StockMovements[i].ReferenceID = &transaction.ID
+	}
+	if err := applyStockMovements(ctx, tx, transaction.StockMovements); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// settleLoyaltyPoints redeems the points paid with and then credits the
+// points earned, so points earned on a sale cannot pay for the same sale.
+func settleLoyaltyPoints(ctx context.Context, tx *sql.Tx, transaction *domain.Transaction) error {
+	customerID := *transaction.CustomerID
+
+	if transaction.PointsRedeemed > 0 {
+		if _, err := lockLoyaltyPoints(ctx, tx, customerID); err != nil {
+			return err
+		}
+		if err := expirePoints(ctx, tx, customerID, transaction.CreatedAt); err != nil {
+			return err
+		}
+		if err := spendPoints(
+			ctx,
+			tx,
+			customerID,
+			&transaction.ID,
+			domain.LoyaltyEntryRedeem,
+			transaction.PointsRedeemed,
+			nil,
+			false,
+		); err != nil {
+			return err
+		}
+	}
+
+	if transaction.PointsEarned > 0 {
+		return addPoints(
+			ctx,
+			tx,
+			customerID,
+			&transaction.ID,
+			domain.LoyaltyEntryEarn,
+			transaction.PointsEarned,
+			transaction.PointsExpireAt,
+		)
+	}
+	return nil
+}
+
+func (r *TransactionRepositoryImpl) GetTransactions(ctx context.Context, filter domain.TransactionFilter, page int, pageSize int) ([]domain.Transaction, int, error) {
+	where := " WHERE ($1::int IS NULL OR customer_id = $1) AND ($2::int IS NULL OR cashier_id = $2)"
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM transactions"+where, filter.CustomerID, filter.CashierID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		transactionSelectQuery+where+" ORDER BY id DESC LIMIT $3 OFFSET $4",
+		filter.CustomerID,
+		filter.CashierID,
+		pageSize,
+		(page-1)*pageSize,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var transactions []domain.Transaction
+	for rows.Next() {
+		var transaction domain.Transaction
+		if err := scanTransaction(rows, &transaction); err != nil {
+			return nil, 0, err
+		}
+		transactions = append(transactions, transaction)
+	}
+	return transactions, total, rows.Err()
+}
+
+const transactionSelectQuery = `
+	SELECT
+		id, customer_id, cashier_id, terminal_id, status, subtotal, discount_amount, service_charge, tax_amount,
+		tax_inclusive, gift_card_amount, total_amount, change_amount, on_account_amount, points_earned, points_redeemed,
+		created_at, refunded_at
+	FROM transactions`
+
+func scanTransaction(scanner rowScanner, transaction *domain.Transaction) error {
+	return scanner.Scan(
+		&transaction.ID,
+		&transaction.CustomerID,
+		&transaction.CashierID,
+		&transaction.TerminalID,
+		&transaction.Status,
+		&transaction.Subtotal,
+		&transaction.DiscountAmount,
+		&transaction.ServiceCharge,
+		&transaction.TaxAmount,
+		&transaction.TaxInclusive,
+		&transaction.GiftCardAmount,
+		&transaction.TotalAmount,
+		&transaction.ChangeAmount,
+		&transaction.OnAccountAmount,
+		&transaction.PointsEarned,
+		&transaction.PointsRedeemed,
+		&transaction.CreatedAt,
+		&transaction.RefundedAt,
+	)
+}
+
+func (r *TransactionRepositoryImpl) GetTransactionByID(ctx context.Context, id int) (*domain.Transaction, error) {
+	var transaction domain.Transaction
+	if err := scanTransaction(r.db.QueryRowContext(ctx, transactionSelectQuery+" WHERE id = $1", id), &transaction); err != nil {
+		return nil, err
+	}
+
+	items, err := r.getTransactionItems(ctx, []int{id})
+	if err != nil {
+		return nil, err
+	}
+	transaction.Items = items
+
+	discounts, err := r.getTransactionDiscounts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	transaction.Discounts = discounts
+
+	payments, err := r.getTransactionPayments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	transaction.Payments = payments
+
+	approvals, err := r.getTransactionApprovals(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	transaction.Approvals = approvals
+
+	return &transaction, nil
+}
+
+// RefundTransaction fully refunds a transaction: its stock movements are
+// reversed, its voucher use is released, gift card movements are undone,
+// what is still owed on account is written off and loyalty points are
+// reversed.
+// Points earned on the sale are taken back even if already spent, leaving a
+// negative balance, except those that expired unspent, and points paid with are credited again as a new lot
+// expiring at pointsExpireAt. The approvals of the void are recorded with
+// it.
+func (r *TransactionRepositoryImpl) RefundTransaction(ctx context.Context, id int, pointsExpireAt *time.Time, approvals []domain.Approval) (*domain.Transaction, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	var customerID *int
+	var pointsEarned, pointsRedeemed int
+	err = tx.QueryRowContext(
+		ctx,
+		"SELECT status, customer_id, points_earned, points_redeemed FROM transactions WHERE id = $1 FOR UPDATE",
+		id,
+	).Scan(&status, &customerID, &pointsEarned, &pointsRedeemed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, utils.ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if status == domain.TransactionStatusRefunded {
+		return nil, utils.ErrTransactionRefunded
+	}
+
+	if err := reverseGiftCards(ctx, tx, id); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(
+		ctx,
+		`SELECT product_id, variant_id, quantity FROM stock_movements
+		WHERE reference_type = 'transaction' AND reference_id = $1 ORDER BY id`,
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var movements []domain.StockMovement
+	for rows.Next() {
+		movement := domain.StockMovement{
+			Reason:        domain.StockReasonRefund,
+			ReferenceType: "transaction",
+			ReferenceID:   &id,
+		}
+		if err := rows.Scan(&movement.ProductID, &movement.VariantID, &movement.Quantity); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		movement.Quantity = -movement.Quantity
+		movements = append(movements, movement)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := applyStockMovements(ctx, tx, movements); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`UPDATE vouchers SET used_count = used_count - 1
+		FROM voucher_redemptions
+		WHERE voucher_redemptions.voucher_id = vouchers.id AND voucher_redemptions.transaction_id = $1`,
+		id,
+	); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM voucher_redemptions WHERE transaction_id = $1", id); err != nil {
+		return nil, err
+	}
+
+	if customerID != nil {
+		if err := reverseReceivable(ctx, tx, *customerID, id); err != nil {
+			return nil, err
+		}
+		if pointsEarned > 0 {
+			if _, err := lockLoyaltyPoints(ctx, tx, *customerID); err != nil {
+				return nil, err
+			}
+			if err := expirePoints(ctx, tx, *customerID, time.Now()); err != nil {
+				return nil, err
+			}
+			expired, err := expiredPoints(ctx, tx, *customerID, id)
+			if err != nil {
+				return nil, err
+			}
+			if reversed := pointsToReverse(pointsEarned, expired); reversed > 0 {
+				if err := spendPoints(ctx, tx, *customerID, &id, domain.LoyaltyEntryReverse, reversed, &id, true); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if pointsRedeemed > 0 {
+			if err := addPoints(ctx, tx, *customerID, &id, domain.LoyaltyEntryReverse, pointsRedeemed, pointsExpireAt); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		"UPDATE transactions SET status = $1, refunded_at = NOW() WHERE id = $2",
+		domain.TransactionStatusRefunded,
+		id,
+	); err != nil {
+		return nil, err
+	}
+	if err := insertApprovals(ctx, tx, id, approvals); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetTransactionByID(ctx, id)
+}
+
+func (r *TransactionRepositoryImpl) getTransactionPayments(ctx context.Context, transactionID int) ([]domain.TransactionPayment, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		"SELECT id, transaction_id, method, amount, points, gift_card_id FROM transaction_payments WHERE transaction_id = $1 ORDER BY id",
+		transactionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []domain.TransactionPayment
+	for rows.Next() {
+		var payment domain.TransactionPayment
+		if err := rows.Scan(
+			&payment.ID,
+			&payment.TransactionID,
+			&payment.Method,
+			&payment.Amount,
+			&payment.Points,
+			&payment.GiftCardID,
+		); err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+	return payments, rows.Err()
+}
+
+func (r *TransactionRepositoryImpl) getTransactionApprovals(ctx context.Context, transactionID int) ([]domain.Approval, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, transaction_id, action, details, requested_by, approved_by, method, grant_id, created_at
+		FROM approvals WHERE transaction_id = $1 ORDER BY id`,
+		transactionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var approvals []domain.Approval
+	for rows.Next() {
+		var approval domain.Approval
+		if err := rows.Scan(
+			&approval.ID,
+			&approval.TransactionID,
+			&approval.Action,
+			&approval.Details,
+			&approval.RequestedBy,
+			&approval.ApprovedBy,
+			&approval.Method,
+			&approval.GrantID,
+			&approval.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		approvals = append(approvals, approval)
+	}
+	return approvals, rows.Err()
+}
+
+func (r *TransactionRepositoryImpl) getTransactionDiscounts(ctx context.Context, transactionID int) ([]domain.TransactionDiscount, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, transaction_item_id, promotion_id, voucher_id, name, amount
+		FROM transaction_discounts WHERE transaction_id = $1 ORDER BY id`,
+		transactionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var discounts []domain.TransactionDiscount
+	for rows.Next() {
+		var discount domain.TransactionDiscount
+		if err := rows.Scan(
+			&discount.ID,
+			&discount.TransactionItemID,
+			&discount.PromotionID,
+			&discount.VoucherID,
+			&discount.Name,
+			&discount.Amount,
+		); err != nil {
+			return nil, err
+		}
+		discounts = append(discounts, discount)
+	}
+	return discounts, rows.Err()
+}
+
+func (r *TransactionRepositoryImpl) getTransactionItems(ctx context.Context, transactionIDs []int) ([]domain.TransactionItem, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, transaction_id, product_id, variant_id, name, unit_id, unit_name, quantity, base_quantity, unit_price, price_list_id,
+			subtotal, discount_amount, tax_rate, service_charge, tax_amount, original_unit_price
+		FROM transaction_items WHERE transaction_id = ANY($1) ORDER BY id`,
+		pq.Array(transactionIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []domain.TransactionItem
+	for rows.Next() {
+		var item domain.TransactionItem
+		if err := rows.Scan(
+			&item.ID,
+			&item.TransactionID,
+			&item.ProductID,
+			&item.VariantID,
+			&item.Name,
+			&item.UnitID,
+			&item.UnitName,
+			&item.Quantity,
+			&item.BaseQuantity,
+			&item.UnitPrice,
+			&item.PriceListID,
+			&item.Subtotal,
+			&item.DiscountAmount,
+			&item.TaxRate,
+			&item.ServiceCharge,
+			&item.TaxAmount,
+			&item.OriginalUnitPrice,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, r.attachItemModifiers(ctx, items)
+}
+
+func (r *TransactionRepositoryImpl) attachItemModifiers(ctx context.Context, items []domain.TransactionItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(items))
+	index := make(map[int]int, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+		index[item.ID] = i
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, transaction_item_id, COALESCE(modifier_id, 0), name, price_delta
+		FROM transaction_item_modifiers WHERE transaction_item_id = ANY($1) ORDER BY id`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var itemID int
+		var modifier domain.TransactionItemModifier
+		if err := rows.Scan(&modifier.ID, &itemID, &modifier.ModifierID, &modifier.Name, &modifier.PriceDelta); err != nil {
+			return err
+		}
+		item := &items[index[itemID]]
+		item.Modifiers = append(item.Modifiers, modifier)
+	}
+	return rows.Err()
+}
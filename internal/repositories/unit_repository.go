@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+)
+
+type UnitRepository interface {
+	GetUnitsByProductID(ctx context.Context, productID int) ([]domain.ProductUnit, error)
+	GetUnitByID(ctx context.Context, id int) (*domain.ProductUnit, error)
+	CreateUnit(ctx context.Context, unit *domain.ProductUnit) (*domain.ProductUnit, error)
+	UpdateUnit(ctx context.Context, id int, unit *domain.ProductUnit) (*domain.ProductUnit, error)
+	DeleteUnit(ctx context.Context, id int) error
+}
+
+type UnitRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewUnitRepository(db *sql.DB) UnitRepository {
+	return &UnitRepositoryImpl{db: db}
+}
+
+const unitSelectQuery = `SELECT id, product_id, name, conversion_factor, price, COALESCE(barcode, '') FROM product_units`
+
+func scanUnit(scanner rowScanner, unit *domain.ProductUnit) error {
+	return scanner.Scan(
+		&unit.ID,
+		&unit.ProductID,
+		&unit.Name,
+		&unit.ConversionFactor,
+		&unit.Price,
+		&unit.Barcode,
+	)
+}
+
+func (r *UnitRepositoryImpl) GetUnitsByProductID(ctx context.Context, productID int) ([]domain.ProductUnit, error) {
+	rows, err := r.db.QueryContext(ctx, unitSelectQuery+" WHERE product_id = $1 ORDER BY conversion_factor, id", productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var units []domain.ProductUnit
+	for rows.Next() {
+		var unit domain.ProductUnit
+		if err := scanUnit(rows, &unit); err != nil {
+			return nil, err
+		}
+		units = append(units, unit)
+	}
+	return units, rows.Err()
+}
+
+func (r *UnitRepositoryImpl) GetUnitByID(ctx context.Context, id int) (*domain.ProductUnit, error) {
+	var unit domain.ProductUnit
+	if err := scanUnit(r.db.QueryRowContext(ctx, unitSelectQuery+" WHERE id = $1", id), &unit); err != nil {
+		return nil, err
+	}
+	return &unit, nil
+}
+
+func (r *UnitRepositoryImpl) CreateUnit(ctx context.Context, unit *domain.ProductUnit) (*domain.ProductUnit, error) {
+	query := `
+		INSERT INTO product_units (product_id, name, conversion_factor, price, barcode)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		unit.ProductID,
+		unit.Name,
+		unit.ConversionFactor,
+		unit.Price,
+		nullableString(unit.Barcode),
+	).Scan(&unit.ID)
+
+	if isUniqueViolation(err) {
+		return nil, utils.ErrDuplicateUnit
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return unit, nil
+}
+
+func (r *UnitRepositoryImpl) UpdateUnit(ctx context.Context, id int, unit *domain.ProductUnit) (*domain.ProductUnit, error) {
+	query := `
+		UPDATE product_units
+		SET name = $1, conversion_factor = $2, price = $3, barcode = $4
+		WHERE id = $5
+		RETURNING id`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		unit.Name,
+		unit.ConversionFactor,
+		unit.Price,
+		nullableString(unit.Barcode),
+		id,
+	).Scan(&unit.ID)
+
+	if isUniqueViolation(err) {
+		return nil, utils.ErrDuplicateUnit
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return unit, nil
+}
+
+func (r *UnitRepositoryImpl) DeleteUnit(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM product_units WHERE id = $1", id)
+	return err
+}
@@ -0,0 +1,212 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type UserRepository interface {
+	GetUsers(ctx context.Context, page int, pageSize int) ([]domain.User, int, error)
+	GetUserByID(ctx context.Context, id int) (*domain.User, error)
+	GetUserByUsername(ctx context.Context, username string) (*domain.User, error)
+	CreateUser(ctx context.Context, user *domain.User) (*domain.User, error)
+	CountUsers(ctx context.Context) (int, error)
+	GetUserPermissions(ctx context.Context, id int) ([]string, error)
+	SetPIN(ctx context.Context, id int, pinHash string) error
+	ClaimPINAttempt(ctx context.Context, id int, maxAttempts int, lockout time.Duration) (bool, error)
+	ResetPINFailures(ctx context.Context, id int) error
+}
+
+type UserRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) UserRepository {
+	return &UserRepositoryImpl{db: db}
+}
+
+const userSelectQuery = `
+	SELECT
+		id, tenant_id, username, name, password_hash, COALESCE(pin_hash, ''), pin_locked_until, active, created_at,
+		ARRAY(
+			SELECT roles.name FROM user_roles
+			JOIN roles ON roles.id = user_roles.role_id
+			WHERE user_roles.user_id = users.id
+			ORDER BY roles.name
+		)
+	FROM users`
+
+func scanUser(scanner rowScanner, user *domain.User) error {
+	if err := scanner.Scan(
+		&user.ID,
+		&user.TenantID,
+		&user.Username,
+		&user.Name,
+		&user.PasswordHash,
+		&user.PINHash,
+		&user.PINLockedUntil,
+		&user.Active,
+		&user.CreatedAt,
+		pq.Array(&user.Roles),
+	); err != nil {
+		return err
+	}
+	user.PINSet = user.PINHash != ""
+	if user.PINLockedUntil != nil && !user.PINLockedUntil.After(time.Now()) {
+		user.PINLockedUntil = nil
+	}
+	return nil
+}
+
+func (r *UserRepositoryImpl) GetUsers(ctx context.Context, page int, pageSize int) ([]domain.User, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users").Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, userSelectQuery+" ORDER BY username LIMIT $1 OFFSET $2", pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		var user domain.User
+		if err := scanUser(rows, &user); err != nil {
+			return nil, 0, err
+		}
+		users = append(users, user)
+	}
+	return users, total, rows.Err()
+}
+
+func (r *UserRepositoryImpl) GetUserByID(ctx context.Context, id int) (*domain.User, error) {
+	var user domain.User
+	if err := scanUser(r.db.QueryRowContext(ctx, userSelectQuery+" WHERE id = $1", id), &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetUserByUsername finds a user in the tenant of ctx, or, without one, in
+// whichever tenant the username belongs to.
+func (r *UserRepositoryImpl) GetUserByUsername(ctx context.Context, username string) (*domain.User, error) {
+	ctx, err := withTenantOf(ctx, r.db, "tenant_of_username", username)
+	if err != nil {
+		return nil, err
+	}
+
+	var user domain.User
+	if err := scanUser(r.db.QueryRowContext(ctx, userSelectQuery+" WHERE username = $1", username), &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// CreateUser stores a user together with the roles named in user.Roles.
+func (r *UserRepositoryImpl) CreateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := insertUser(ctx, tx, user); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// insertUser stores a user, in the tenant of ctx, together with the roles
+// named in user.Roles.
+func insertUser(ctx context.Context, tx *sql.Tx, user *domain.User) error {
+	err := tx.QueryRowContext(
+		ctx,
+		"INSERT INTO users (username, name, password_hash, active) VALUES ($1, $2, $3, $4) RETURNING id, tenant_id, created_at",
+		user.Username,
+		user.Name,
+		user.PasswordHash,
+		user.Active,
+	).Scan(&user.ID, &user.TenantID, &user.CreatedAt)
+	if isUniqueViolation(err) {
+		return utils.ErrDuplicateUser
+	}
+	if err != nil {
+		return err
+	}
+	return setUserRoles(ctx, tx, user.ID, user.Roles)
+}
+
+func (r *UserRepositoryImpl) CountUsers(ctx context.Context) (int, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users").Scan(&total)
+	return total, err
+}
+
+// GetUserPermissions returns the permissions of all the user's roles.
+func (r *UserRepositoryImpl) GetUserPermissions(ctx context.Context, id int) ([]string, error) {
+	var permissions []string
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT ARRAY(
+			SELECT DISTINCT role_permissions.permission FROM user_roles
+			JOIN role_permissions ON role_permissions.role_id = user_roles.role_id
+			WHERE user_roles.user_id = $1
+			ORDER BY role_permissions.permission
+		)`,
+		id,
+	).Scan(pq.Array(&permissions))
+	return permissions, err
+}
+
+// SetPIN replaces the PIN hash of a user and lifts any lockout.
+func (r *UserRepositoryImpl) SetPIN(ctx context.Context, id int, pinHash string) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		"UPDATE users SET pin_hash = $1, pin_failed_attempts = 0, pin_locked_until = NULL WHERE id = $2",
+		pinHash,
+		id,
+	)
+	return err
+}
+
+// ClaimPINAttempt counts a PIN attempt before the PIN is compared, so
+// concurrent attempts cannot get past the limit. The maxAttempts-th attempt
+// in a row locks the PIN for the lockout duration and starts counting again;
+// it reports whether this attempt was that one. While the PIN is locked it
+// fails with ErrPINLocked. A correct PIN lifts the count and lock again
+// through ResetPINFailures.
+func (r *UserRepositoryImpl) ClaimPINAttempt(ctx context.Context, id int, maxAttempts int, lockout time.Duration) (bool, error) {
+	var last bool
+	err := r.db.QueryRowContext(
+		ctx,
+		`UPDATE users SET
+			pin_failed_attempts = CASE WHEN pin_failed_attempts + 1 >= $2 THEN 0 ELSE pin_failed_attempts + 1 END,
+			pin_locked_until = CASE WHEN pin_failed_attempts + 1 >= $2 THEN NOW() + make_interval(secs => $3) END
+		WHERE id = $1 AND (pin_locked_until IS NULL OR pin_locked_until <= NOW())
+		RETURNING pin_locked_until IS NOT NULL`,
+		id,
+		maxAttempts,
+		lockout.Seconds(),
+	).Scan(&last)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, utils.ErrPINLocked
+	}
+	return last, err
+}
+
+func (r *UserRepositoryImpl) ResetPINFailures(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE users SET pin_failed_attempts = 0, pin_locked_until = NULL WHERE id = $1", id)
+	return err
+}
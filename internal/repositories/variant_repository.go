@@ -0,0 +1,254 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+
+	"github.com/lib/pq"
+)
+
+type VariantRepository interface {
+	GetVariantOptions(ctx context.Context, productID int) ([]domain.VariantOption, error)
+	ReplaceVariantOptions(ctx context.Context, productID int, options []domain.VariantOption) ([]domain.VariantOption, error)
+	GetVariantsByProductIDs(ctx context.Context, productIDs []int) ([]domain.ProductVariant, error)
+	GetVariantByID(ctx context.Context, id int) (*domain.ProductVariant, error)
+	CreateVariant(ctx context.Context, variant *domain.ProductVariant) (*domain.ProductVariant, error)
+	UpdateVariant(ctx context.Context, id int, variant *domain.ProductVariant) (*domain.ProductVariant, error)
+	DeleteVariant(ctx context.Context, id int) error
+}
+
+type VariantRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewVariantRepository(db *sql.DB) VariantRepository {
+	return &VariantRepositoryImpl{db: db}
+}
+
+const variantSelectQuery = `SELECT id, product_id, sku, COALESCE(barcode, ''), name, options, price, stock FROM product_variants`
+
+func scanVariant(scanner rowScanner, variant *domain.ProductVariant) error {
+	var options []byte
+	if err := scanner.Scan(
+		&variant.ID,
+		&variant.ProductID,
+		&variant.SKU,
+		&variant.Barcode,
+		&variant.Name,
+		&options,
+		&variant.Price,
+		&variant.Stock,
+	); err != nil {
+		return err
+	}
+	return json.Unmarshal(options, &variant.Options)
+}
+
+func nullableString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// isForeignKeyViolation reports whether a row could not be deleted because
+// other rows, such as sold transaction items, still reference it.
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
+
+func (r *VariantRepositoryImpl) GetVariantOptions(ctx context.Context, productID int) ([]domain.VariantOption, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, product_id, name, "values" FROM product_variant_options WHERE product_id = $1 ORDER BY position, id`,
+		productID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	options := []domain.VariantOption{}
+	for rows.Next() {
+		var option domain.VariantOption
+		if err := rows.Scan(&option.ID, &option.ProductID, &option.Name, pq.Array(&option.Values)); err != nil {
+			return nil, err
+		}
+		options = append(options, option)
+	}
+	return options, rows.Err()
+}
+
+func (r *VariantRepositoryImpl) ReplaceVariantOptions(ctx context.Context, productID int, options []domain.VariantOption) ([]domain.VariantOption, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM product_variant_options WHERE product_id = $1", productID); err != nil {
+		return nil, err
+	}
+
+	for i := range options {
+		options[i].ProductID = productID
+		err := tx.QueryRowContext(
+			ctx,
+			`INSERT INTO product_variant_options (product_id, name, "values", position) VALUES ($1, $2, $3, $4) RETURNING id`,
+			productID,
+			options[i].Name,
+			pq.Array(options[i].Values),
+			i,
+		).Scan(&options[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+func (r *VariantRepositoryImpl) GetVariantsByProductIDs(ctx context.Context, productIDs []int) ([]domain.ProductVariant, error) {
+	rows, err := r.db.QueryContext(ctx, variantSelectQuery+" WHERE product_id = ANY($1) ORDER BY product_id, id", pq.Array(productIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var variants []domain.ProductVariant
+	for rows.Next() {
+		var variant domain.ProductVariant
+		if err := scanVariant(rows, &variant); err != nil {
+			return nil, err
+		}
+		variants = append(variants, variant)
+	}
+	return variants, rows.Err()
+}
+
+func (r *VariantRepositoryImpl) GetVariantByID(ctx context.Context, id int) (*domain.ProductVariant, error) {
+	var variant domain.ProductVariant
+	if err := scanVariant(r.db.QueryRowContext(ctx, variantSelectQuery+" WHERE id = $1", id), &variant); err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+func (r *VariantRepositoryImpl) CreateVariant(ctx context.Context, variant *domain.ProductVariant) (*domain.ProductVariant, error) {
+	options, err := json.Marshal(variant.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO product_variants (product_id, sku, barcode, name, options, price, stock)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`
+
+	err = r.db.QueryRowContext(
+		ctx,
+		query,
+		variant.ProductID,
+		variant.SKU,
+		nullableString(variant.Barcode),
+		variant.Name,
+		options,
+		variant.Price,
+		variant.Stock,
+	).Scan(&variant.ID)
+
+	if isUniqueViolation(err) {
+		return nil, utils.ErrDuplicateVariant
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return variant, nil
+}
+
+// UpdateVariant records a changed price in the price history and a changed
+// stock as an adjustment, like product updates do.
+func (r *VariantRepositoryImpl) UpdateVariant(ctx context.Context, id int, variant *domain.ProductVariant) (*domain.ProductVariant, error) {
+	options, err := json.Marshal(variant.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var productID, oldPrice, oldStock int
+	err = tx.QueryRowContext(
+		ctx,
+		"SELECT product_id, price, stock FROM product_variants WHERE id = $1 FOR UPDATE",
+		id,
+	).Scan(&productID, &oldPrice, &oldStock)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, utils.ErrVariantNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := adjustStock(ctx, tx, productID, &id, oldStock, variant.Stock); err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE product_variants
+		SET sku = $1, barcode = $2, name = $3, options = $4, price = $5
+		WHERE id = $6
+		RETURNING id`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		variant.SKU,
+		nullableString(variant.Barcode),
+		variant.Name,
+		options,
+		variant.Price,
+		id,
+	).Scan(&variant.ID)
+
+	if isUniqueViolation(err) {
+		return nil, utils.ErrDuplicateVariant
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if variant.Price != oldPrice {
+		changedBy := utils.ActorFromContext(ctx)
+		if err := recordPriceChange(ctx, tx, productID, &id, oldPrice, variant.Price, domain.PriceSourceManual, changedBy); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return variant, nil
+}
+
+func (r *VariantRepositoryImpl) DeleteVariant(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM product_variants WHERE id = $1", id)
+	if isForeignKeyViolation(err) {
+		return utils.ErrVariantInUse
+	}
+	return err
+}
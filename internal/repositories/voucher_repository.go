@@ -0,0 +1,226 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+)
+
+type VoucherRepository interface {
+	GetVouchers(ctx context.Context, page int, pageSize int) ([]domain.Voucher, int, error)
+	GetVoucherByID(ctx context.Context, id int) (*domain.Voucher, error)
+	GetVoucherByCode(ctx context.Context, code string) (*domain.Voucher, error)
+	CountCustomerRedemptions(ctx context.Context, voucherID int, customerID int) (int, error)
+	CreateVoucher(ctx context.Context, voucher *domain.Voucher) (*domain.Voucher, error)
+	UpdateVoucher(ctx context.Context, id int, voucher *domain.Voucher) (*domain.Voucher, error)
+	DeleteVoucher(ctx context.Context, id int) error
+}
+
+type VoucherRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewVoucherRepository(db *sql.DB) VoucherRepository {
+	return &VoucherRepositoryImpl{db: db}
+}
+
+const voucherSelectQuery = `
+	SELECT
+		id, code, name, discount_type, value, max_discount, min_purchase, starts_at, ends_at,
+		usage_limit, per_customer_limit, used_count, active
+	FROM vouchers`
+
+func scanVoucher(scanner rowScanner, voucher *domain.Voucher) error {
+	return scanner.Scan(
+		&voucher.ID,
+		&voucher.Code,
+		&voucher.Name,
+		&voucher.DiscountType,
+		&voucher.Value,
+		&voucher.MaxDiscount,
+		&voucher.MinPurchase,
+		&voucher.StartsAt,
+		&voucher.EndsAt,
+		&voucher.UsageLimit,
+		&voucher.PerCustomerLimit,
+		&voucher.UsedCount,
+		&voucher.Active,
+	)
+}
+
+func (r *VoucherRepositoryImpl) GetVouchers(ctx context.Context, page int, pageSize int) ([]domain.Voucher, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM vouchers").Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, voucherSelectQuery+" ORDER BY id LIMIT $1 OFFSET $2", pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var vouchers []domain.Voucher
+	for rows.Next() {
+		var voucher domain.Voucher
+		if err := scanVoucher(rows, &voucher); err != nil {
+			return nil, 0, err
+		}
+		vouchers = append(vouchers, voucher)
+	}
+	return vouchers, total, rows.Err()
+}
+
+func (r *VoucherRepositoryImpl) GetVoucherByID(ctx context.Context, id int) (*domain.Voucher, error) {
+	var voucher domain.Voucher
+	if err := scanVoucher(r.db.QueryRowContext(ctx, voucherSelectQuery+" WHERE id = $1", id), &voucher); err != nil {
+		return nil, err
+	}
+	return &voucher, nil
+}
+
+func (r *VoucherRepositoryImpl) GetVoucherByCode(ctx context.Context, code string) (*domain.Voucher, error) {
+	var voucher domain.Voucher
+	if err := scanVoucher(r.db.QueryRowContext(ctx, voucherSelectQuery+" WHERE code = $1", code), &voucher); err != nil {
+		return nil, err
+	}
+	return &voucher, nil
+}
+
+func (r *VoucherRepositoryImpl) CountCustomerRedemptions(ctx context.Context, voucherID int, customerID int) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(
+		ctx,
+		"SELECT COUNT(*) FROM voucher_redemptions WHERE voucher_id = $1 AND customer_id = $2",
+		voucherID,
+		customerID,
+	).Scan(&count)
+	return count, err
+}
+
+func (r *VoucherRepositoryImpl) CreateVoucher(ctx context.Context, voucher *domain.Voucher) (*domain.Voucher, error) {
+	query := `
+		INSERT INTO vouchers (
+			code, name, discount_type, value, max_discount, min_purchase, starts_at, ends_at,
+			usage_limit, per_customer_limit, active
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, used_count`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		voucher.Code,
+		voucher.Name,
+		voucher.DiscountType,
+		voucher.Value,
+		voucher.MaxDiscount,
+		voucher.MinPurchase,
+		voucher.StartsAt,
+		voucher.EndsAt,
+		voucher.UsageLimit,
+		voucher.PerCustomerLimit,
+		voucher.Active,
+	).Scan(&voucher.ID, &voucher.UsedCount)
+
+	if isUniqueViolation(err) {
+		return nil, utils.ErrDuplicateVoucher
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return voucher, nil
+}
+
+func (r *VoucherRepositoryImpl) UpdateVoucher(ctx context.Context, id int, voucher *domain.Voucher) (*domain.Voucher, error) {
+	query := `
+		UPDATE vouchers
+		SET code = $1, name = $2, discount_type = $3, value = $4, max_discount = $5, min_purchase = $6,
+			starts_at = $7, ends_at = $8, usage_limit = $9, per_customer_limit = $10, active = $11
+		WHERE id = $12
+		RETURNING id, used_count`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		voucher.Code,
+		voucher.Name,
+		voucher.DiscountType,
+		voucher.Value,
+		voucher.MaxDiscount,
+		voucher.MinPurchase,
+		voucher.StartsAt,
+		voucher.EndsAt,
+		voucher.UsageLimit,
+		voucher.PerCustomerLimit,
+		voucher.Active,
+		id,
+	).Scan(&voucher.ID, &voucher.UsedCount)
+
+	if isUniqueViolation(err) {
+		return nil, utils.ErrDuplicateVoucher
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return voucher, nil
+}
+
+func (r *VoucherRepositoryImpl) DeleteVoucher(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM vouchers WHERE id = $1", id)
+	return err
+}
+
+// redeemVoucher claims one use of a voucher inside tx. The guarded update
+// locks the voucher row, so concurrent checkouts with the same code queue up
+// behind each other and the usage limits are re-checked against committed
+// redemptions.
+func redeemVoucher(ctx context.Context, tx *sql.Tx, voucherID int, customerID *int, transactionID int, amount int) error {
+	var perCustomerLimit int
+	err := tx.QueryRowContext(
+		ctx,
+		`UPDATE vouchers SET used_count = used_count + 1
+		WHERE id = $1 AND active AND (usage_limit = 0 OR used_count < usage_limit)
+		RETURNING per_customer_limit`,
+		voucherID,
+	).Scan(&perCustomerLimit)
+	if errors.Is(err, sql.ErrNoRows) {
+		return utils.ErrVoucherLimitReached
+	}
+	if err != nil {
+		return err
+	}
+
+	if perCustomerLimit > 0 {
+		if customerID == nil {
+			return utils.ErrVoucherCustomerRequired
+		}
+		var used int
+		err := tx.QueryRowContext(
+			ctx,
+			"SELECT COUNT(*) FROM voucher_redemptions WHERE voucher_id = $1 AND customer_id = $2",
+			voucherID,
+			*customerID,
+		).Scan(&used)
+		if err != nil {
+			return err
+		}
+		if used >= perCustomerLimit {
+			return utils.ErrVoucherLimitReached
+		}
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO voucher_redemptions (voucher_id, transaction_id, customer_id, amount) VALUES ($1, $2, $3, $4)",
+		voucherID,
+		transactionID,
+		customerID,
+		amount,
+	)
+	return err
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+	"time"
+)
+
+// apiKeyPrefixLength is how much of a key is kept in clear text so it can
+// be recognised in the list of keys.
+const apiKeyPrefixLength = len(domain.APIKeyPrefix) + 8
+
+type APIKeyService interface {
+	GetAPIKeys(ctx context.Context) ([]domain.APIKey, error)
+	CreateAPIKey(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error)
+	RotateAPIKey(ctx context.Context, id int) (*domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int) (*domain.APIKey, error)
+}
+
+type APIKeyServiceImpl struct {
+	apiKeyRepository repository.APIKeyRepository
+}
+
+func NewAPIKeyService(apiKeyRepository repository.APIKeyRepository) APIKeyService {
+	return &APIKeyServiceImpl{apiKeyRepository: apiKeyRepository}
+}
+
+func (s *APIKeyServiceImpl) GetAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	keys, err := s.apiKeyRepository.GetAPIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if keys == nil {
+		keys = []domain.APIKey{}
+	}
+	return keys, nil
+}
+
+// CreateAPIKey issues a new key. Its permissions must be ones the creator
+// holds, so a key can never do more than the user who made it. The key
+// itself is only returned here; just its hash is stored.
+func (s *APIKeyServiceImpl) CreateAPIKey(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+	creator := utils.AuthUserFromContext(ctx)
+	for _, permission := range key.Permissions {
+		if !domain.IsPermission(permission) {
+			return nil, fmt.Errorf("%w: %s", utils.ErrUnknownPermission, permission)
+		}
+		if creator == nil || !creator.Can(permission) {
+			return nil, fmt.Errorf("%w: %s", utils.ErrAPIKeyScope, permission)
+		}
+	}
+	key.Permissions = uniqueStrings(key.Permissions)
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now()) {
+		return nil, utils.ErrInvalidAPIKeyExpiry
+	}
+	if creator != nil {
+		key.CreatedBy = creator.UserID()
+	}
+
+	secret, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	key.Prefix = secret[:apiKeyPrefixLength]
+
+	created, err := s.apiKeyRepository.CreateAPIKey(ctx, key, hashToken(secret))
+	if err != nil {
+		return nil, err
+	}
+	created.Key = secret
+	return created, nil
+}
+
+// RotateAPIKey replaces the key of an API key, keeping its name and
+// permissions. The old key stops working at once.
+func (s *APIKeyServiceImpl) RotateAPIKey(ctx context.Context, id int) (*domain.APIKey, error) {
+	if _, err := s.apiKeyRepository.GetAPIKeyByID(ctx, id); err != nil {
+		return nil, utils.ErrAPIKeyNotFound
+	}
+
+	secret, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.apiKeyRepository.RotateAPIKey(ctx, id, secret[:apiKeyPrefixLength], hashToken(secret))
+	if err != nil {
+		return nil, err
+	}
+	key.Key = secret
+	return key, nil
+}
+
+func (s *APIKeyServiceImpl) RevokeAPIKey(ctx context.Context, id int) (*domain.APIKey, error) {
+	return s.apiKeyRepository.RevokeAPIKey(ctx, id)
+}
+
+func newAPIKey() (string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	return domain.APIKeyPrefix + token, nil
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+	"slices"
+)
+
+type ApprovalService interface {
+	GetApprovalSettings(ctx context.Context) (*domain.ApprovalSettings, error)
+	UpdateApprovalSettings(ctx context.Context, settings *domain.ApprovalSettings) (*domain.ApprovalSettings, error)
+}
+
+type ApprovalServiceImpl struct {
+	approvalRepository repository.ApprovalRepository
+}
+
+func NewApprovalService(approvalRepository repository.ApprovalRepository) ApprovalService {
+	return &ApprovalServiceImpl{approvalRepository: approvalRepository}
+}
+
+func (s *ApprovalServiceImpl) GetApprovalSettings(ctx context.Context) (*domain.ApprovalSettings, error) {
+	return s.approvalRepository.GetApprovalSettings(ctx)
+}
+
+func (s *ApprovalServiceImpl) UpdateApprovalSettings(ctx context.Context, settings *domain.ApprovalSettings) (*domain.ApprovalSettings, error) {
+	return s.approvalRepository.UpdateApprovalSettings(ctx, settings)
+}
+
+// authorizeApprovals fills in who requested and who approved each action.
+// The requester approves an action themselves when their roles allow it,
+// otherwise the supervisor who approved the request must be allowed to and,
+// when they approved with a grant, the grant must cover the action. A
+// supervisor of another tenant approves nothing. Actions neither may do
+// fail the whole operation with an ApprovalRequiredError.
+func authorizeApprovals(ctx context.Context, approvals []domain.Approval) error {
+	requester := utils.AuthUserFromContext(ctx)
+	approver := utils.ApproverFromContext(ctx)
+	if approver != nil && (requester == nil || approver.TenantID != requester.TenantID) {
+		approver = nil
+	}
+
+	var missing []string
+	for i := range approvals {
+		approval := &approvals[i]
+		permission := domain.ApprovalPermissions[approval.Action]
+		if requester != nil {
+			approval.RequestedBy = requester.UserID()
+		}
+
+		switch {
+		case requester != nil && requester.Can(permission):
+			approval.ApprovedBy = requester.UserID()
+			approval.Method = domain.ApprovalMethodSelf
+		case approver != nil && approver.Can(permission) && approver.Covers(approval.Action, approval.TransactionID):
+			approval.ApprovedBy = &approver.ID
+			approval.Method = approver.Method
+			approval.GrantID = approver.GrantID
+		case !slices.Contains(missing, approval.Action):
+			missing = append(missing, approval.Action)
+		}
+	}
+
+	if len(missing) > 0 {
+		return &utils.ApprovalRequiredError{Actions: missing}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"errors"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+	"testing"
+)
+
+func TestAuthorizeApprovals(t *testing.T) {
+	cashier := &utils.AuthUser{ID: 1, TenantID: 1, Username: "cashier", Permissions: []string{domain.PermissionTransactionCreate}}
+	supervisor := func(tenantID int) *utils.Approver {
+		return &utils.Approver{
+			AuthUser: utils.AuthUser{ID: 2, TenantID: tenantID, Username: "supervisor", Permissions: []string{domain.PermissionTransactionVoid}},
+			Method:   domain.ApprovalMethodPIN,
+		}
+	}
+	tests := []struct {
+		name     string
+		approver *utils.Approver
+		wantErr  bool
+		method   string
+	}{
+		{name: "no approver", wantErr: true},
+		{name: "supervisor of the same tenant", approver: supervisor(1), method: domain.ApprovalMethodPIN},
+		{name: "supervisor of another tenant", approver: supervisor(2), wantErr: true},
+	}
+
+	for _, test := range tests {
+		ctx := utils.WithAuthUser(context.Background(), cashier)
+		if test.approver != nil {
+			ctx = utils.WithApprover(ctx, test.approver)
+		}
+		approvals := []domain.Approval{{Action: domain.ApprovalActionVoid, TransactionID: 5}}
+
+		err := authorizeApprovals(ctx, approvals)
+		if test.wantErr {
+			var approval *utils.ApprovalRequiredError
+			if !errors.As(err, &approval) {
+				t.Errorf("%s: error %v, want an ApprovalRequiredError", test.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: error %v", test.name, err)
+			continue
+		}
+		if approvals[0].Method != test.method || approvals[0].ApprovedBy == nil || *approvals[0].ApprovedBy != test.approver.ID {
+			t.Errorf("%s: approval %+v, want approved by %d with %s", test.name, approvals[0], test.approver.ID, test.method)
+		}
+	}
+}
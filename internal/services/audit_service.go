@@ -0,0 +1,32 @@
+package services
+
+import (
+	"context"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+)
+
+type AuditService interface {
+	GetAuditLogs(ctx context.Context, filter domain.AuditLogFilter, page int, pageSize int) ([]domain.AuditLog, int, error)
+}
+
+type AuditServiceImpl struct {
+	auditRepository repository.AuditRepository
+}
+
+func NewAuditService(auditRepository repository.AuditRepository) AuditService {
+	return &AuditServiceImpl{auditRepository: auditRepository}
+}
+
+func (s *AuditServiceImpl) GetAuditLogs(ctx context.Context, filter domain.AuditLogFilter, page int, pageSize int) ([]domain.AuditLog, int, error) {
+	logs, total, err := s.auditRepository.GetAuditLogs(ctx, filter, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if logs == nil {
+		logs = []domain.AuditLog{}
+	}
+
+	return logs, total, nil
+}
@@ -0,0 +1,455 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"kasir-api/internal/config"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type AuthService interface {
+	Login(ctx context.Context, username string, password string) (*domain.TokenPair, error)
+	PINLogin(ctx context.Context, terminalKey string, username string, pin string) (*domain.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string, terminalKey string) (*domain.TokenPair, error)
+	Logout(ctx context.Context, sessionID string) error
+	Authenticate(ctx context.Context, accessToken string, terminalKey string) (*utils.AuthUser, error)
+	Approve(ctx context.Context, approvalToken string, username string, pin string, terminalKey string) (*utils.Approver, error)
+	IssueApprovalGrant(ctx context.Context, actions []string, transactionID *int) (*domain.ApprovalGrant, error)
+	EnsureAdmin(ctx context.Context, username string, password string) error
+}
+
+type AuthServiceImpl struct {
+	userRepository     repository.UserRepository
+	sessionRepository  repository.SessionRepository
+	terminalRepository repository.TerminalRepository
+	apiKeyRepository   repository.APIKeyRepository
+	approvalRepository repository.ApprovalRepository
+	secret             []byte
+	accessTokenTTL     time.Duration
+	refreshTokenTTL    time.Duration
+	pinMaxAttempts     int
+	pinLockout         time.Duration
+	approvalGrantTTL   time.Duration
+}
+
+// NewAuthService signs access tokens with the configured secret. Without
+// one a random secret is used, so tokens stop working when the server
+// restarts.
+func NewAuthService(
+	userRepository repository.UserRepository,
+	sessionRepository repository.SessionRepository,
+	terminalRepository repository.TerminalRepository,
+	apiKeyRepository repository.APIKeyRepository,
+	approvalRepository repository.ApprovalRepository,
+	config *config.AuthConfig,
+) AuthService {
+	secret := []byte(config.JWTSecret)
+	if len(secret) == 0 {
+		log.Println("auth jwt secret is empty, using a random secret")
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			panic("failed to generate jwt secret")
+		}
+	}
+
+	return &AuthServiceImpl{
+		userRepository:     userRepository,
+		sessionRepository:  sessionRepository,
+		terminalRepository: terminalRepository,
+		apiKeyRepository:   apiKeyRepository,
+		approvalRepository: approvalRepository,
+		secret:             secret,
+		accessTokenTTL:     config.AccessTokenTTL,
+		refreshTokenTTL:    config.RefreshTokenTTL,
+		pinMaxAttempts:     max(1, config.PINMaxAttempts),
+		pinLockout:         config.PINLockout,
+		approvalGrantTTL:   config.ApprovalGrantTTL,
+	}
+}
+
+type accessClaims struct {
+	Username  string `json:"username"`
+	SessionID string `json:"sid"`
+	TenantID  int    `json:"tid"`
+	jwt.RegisteredClaims
+}
+
+// dummyPasswordHash is compared against when the username does not exist,
+// so a failed login takes as long whether or not the user is real.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("kasir-api"), bcrypt.DefaultCost)
+
+func (s *AuthServiceImpl) Login(ctx context.Context, username string, password string) (*domain.TokenPair, error) {
+	user, err := s.userRepository.GetUserByUsername(ctx, username)
+	if err != nil {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return nil, utils.ErrInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, utils.ErrInvalidCredentials
+	}
+	if !user.Active {
+		return nil, utils.ErrInvalidCredentials
+	}
+	return s.startSession(ctx, user, nil)
+}
+
+// PINLogin makes a user the active cashier of a registered terminal.
+func (s *AuthServiceImpl) PINLogin(ctx context.Context, terminalKey string, username string, pin string) (*domain.TokenPair, error) {
+	user, terminal, err := s.checkPIN(ctx, terminalKey, username, pin)
+	if err != nil {
+		return nil, err
+	}
+	ctx = utils.WithTenantID(ctx, terminal.TenantID)
+	if err := s.terminalRepository.TouchTerminal(ctx, terminal.ID); err != nil {
+		return nil, err
+	}
+	return s.startSession(ctx, user, &terminal.ID)
+}
+
+// Approve verifies the credentials a supervisor approves an operation with:
+// an approval grant they issued, or their username and PIN entered on the
+// terminal the request comes from. Grants are only found within the
+// requester's tenant and are used up when the operation is recorded.
+func (s *AuthServiceImpl) Approve(ctx context.Context, approvalToken string, username string, pin string, terminalKey string) (*utils.Approver, error) {
+	if approvalToken != "" {
+		grant, err := s.approvalRepository.GetActiveApprovalGrantByHash(ctx, hashToken(approvalToken))
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, utils.ErrInvalidApprover
+		}
+		if err != nil {
+			return nil, err
+		}
+		user, err := s.userRepository.GetUserByID(ctx, grant.ApproverID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, utils.ErrInvalidApprover
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !user.Active {
+			return nil, utils.ErrInvalidApprover
+		}
+		permissions, err := s.userRepository.GetUserPermissions(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &utils.Approver{
+			AuthUser: utils.AuthUser{
+				ID:          user.ID,
+				TenantID:    user.TenantID,
+				Username:    user.Username,
+				Permissions: permissions,
+			},
+			Method:        domain.ApprovalMethodToken,
+			GrantID:       &grant.ID,
+			Actions:       grant.Actions,
+			TransactionID: grant.TransactionID,
+		}, nil
+	}
+
+	user, terminal, err := s.checkPIN(ctx, terminalKey, username, pin)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := s.userRepository.GetUserPermissions(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &utils.Approver{
+		AuthUser: utils.AuthUser{
+			ID:          user.ID,
+			TenantID:    user.TenantID,
+			Username:    user.Username,
+			Permissions: permissions,
+			TerminalID:  &terminal.ID,
+		},
+		Method: domain.ApprovalMethodPIN,
+	}, nil
+}
+
+// IssueApprovalGrant lets the logged in supervisor approve actions for
+// someone else without handing over their login. They must be allowed to
+// do every action themselves. The token is returned once and only its hash
+// is stored.
+func (s *AuthServiceImpl) IssueApprovalGrant(ctx context.Context, actions []string, transactionID *int) (*domain.ApprovalGrant, error) {
+	user := utils.AuthUserFromContext(ctx)
+	for _, action := range actions {
+		permission, ok := domain.ApprovalPermissions[action]
+		if !ok || !user.Can(permission) {
+			return nil, utils.ErrApprovalGrantScope
+		}
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	grant, err := s.approvalRepository.CreateApprovalGrant(ctx, &domain.ApprovalGrant{
+		ApproverID:    user.ID,
+		Actions:       actions,
+		TransactionID: transactionID,
+		ExpiresAt:     time.Now().Add(s.approvalGrantTTL),
+	}, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	grant.Token = token
+	return grant, nil
+}
+
+// checkPIN verifies a PIN entered on a registered terminal by a user of the
+// terminal's tenant. Every attempt is counted before the PIN is compared,
+// and too many in a row without a correct one lock the user's PIN for a
+// while.
+func (s *AuthServiceImpl) checkPIN(ctx context.Context, terminalKey string, username string, pin string) (*domain.User, *domain.Terminal, error) {
+	terminal, err := s.terminalRepository.GetTerminalByKeyHash(ctx, hashToken(terminalKey))
+	if err != nil || !terminal.Active {
+		return nil, nil, utils.ErrInvalidTerminal
+	}
+	ctx = utils.WithTenantID(ctx, terminal.TenantID)
+
+	user, err := s.userRepository.GetUserByUsername(ctx, username)
+	if err != nil || user.PINHash == "" {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(pin))
+		return nil, nil, utils.ErrInvalidPIN
+	}
+	last, err := s.userRepository.ClaimPINAttempt(ctx, user.ID, s.pinMaxAttempts, s.pinLockout)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PINHash), []byte(pin)); err != nil {
+		if last {
+			return nil, nil, utils.ErrPINLocked
+		}
+		return nil, nil, utils.ErrInvalidPIN
+	}
+	if !user.Active {
+		return nil, nil, utils.ErrInvalidPIN
+	}
+
+	if err := s.userRepository.ResetPINFailures(ctx, user.ID); err != nil {
+		return nil, nil, err
+	}
+	return user, terminal, nil
+}
+
+func (s *AuthServiceImpl) startSession(ctx context.Context, user *domain.User, terminalID *int) (*domain.TokenPair, error) {
+	sessionID, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &domain.Session{ID: sessionID, TenantID: user.TenantID, UserID: user.ID, TerminalID: terminalID}
+	if err := s.sessionRepository.CreateSession(ctx, session, hashToken(refreshToken), time.Now().Add(s.refreshTokenTTL)); err != nil {
+		return nil, err
+	}
+	return s.tokenPair(user, session.ID, refreshToken)
+}
+
+// Refresh trades a refresh token for a new access and refresh token. Each
+// refresh token works once, and the one of a PIN session only together
+// with the key of its terminal.
+func (s *AuthServiceImpl) Refresh(ctx context.Context, refreshToken string, terminalKey string) (*domain.TokenPair, error) {
+	newRefreshToken, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.sessionRepository.RotateRefreshToken(
+		ctx,
+		hashToken(refreshToken),
+		hashToken(terminalKey),
+		hashToken(newRefreshToken),
+		time.Now().Add(s.refreshTokenTTL),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = utils.WithTenantID(ctx, session.TenantID)
+	user, err := s.userRepository.GetUserByID(ctx, session.UserID)
+	if err != nil || !user.Active {
+		if err := s.sessionRepository.RevokeSession(ctx, session.ID); err != nil {
+			return nil, err
+		}
+		return nil, utils.ErrInvalidRefreshToken
+	}
+	return s.tokenPair(user, session.ID, newRefreshToken)
+}
+
+func (s *AuthServiceImpl) Logout(ctx context.Context, sessionID string) error {
+	return s.sessionRepository.RevokeSession(ctx, sessionID)
+}
+
+// Authenticate verifies an access token and that its session is still
+// active, so logging out or revoking a user takes effect at once. Tokens of
+// a PIN session only work together with the key of its terminal. API keys
+// are accepted in place of an access token.
+func (s *AuthServiceImpl) Authenticate(ctx context.Context, accessToken string, terminalKey string) (*utils.AuthUser, error) {
+	if strings.HasPrefix(accessToken, domain.APIKeyPrefix) {
+		return s.authenticateAPIKey(ctx, accessToken)
+	}
+
+	var claims accessClaims
+	_, err := jwt.ParseWithClaims(
+		accessToken,
+		&claims,
+		func(*jwt.Token) (interface{}, error) { return s.secret, nil },
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, utils.ErrInvalidToken
+	}
+
+	// the session's user is only found within the tenant of the token
+	ctx = utils.WithTenantID(ctx, claims.TenantID)
+	session, user, err := s.sessionRepository.GetActiveSession(ctx, claims.SessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, utils.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !onTerminal(session, terminalKey) {
+		return nil, utils.ErrInvalidToken
+	}
+
+	return &utils.AuthUser{
+		ID:          user.ID,
+		TenantID:    user.TenantID,
+		Username:    user.Username,
+		SessionID:   claims.SessionID,
+		Permissions: user.Permissions,
+		TerminalID:  session.TerminalID,
+	}, nil
+}
+
+// authenticateAPIKey resolves an API key that is neither revoked nor
+// expired to the permissions it was given.
+func (s *AuthServiceImpl) authenticateAPIKey(ctx context.Context, key string) (*utils.AuthUser, error) {
+	apiKey, err := s.apiKeyRepository.GetActiveAPIKeyByHash(ctx, hashToken(key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, utils.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	ctx = utils.WithTenantID(ctx, apiKey.TenantID)
+	if err := s.apiKeyRepository.TouchAPIKey(ctx, apiKey.ID); err != nil {
+		return nil, err
+	}
+
+	return &utils.AuthUser{
+		TenantID:    apiKey.TenantID,
+		Username:    "api-key:" + apiKey.Name,
+		Permissions: apiKey.Permissions,
+		APIKeyID:    &apiKey.ID,
+	}, nil
+}
+
+// EnsureAdmin creates the first user, as an owner of the default tenant,
+// while the default tenant has no users, so a fresh deployment can be
+// logged into.
+func (s *AuthServiceImpl) EnsureAdmin(ctx context.Context, username string, password string) error {
+	ctx = utils.WithTenantID(ctx, domain.DefaultTenantID)
+	total, err := s.userRepository.CountUsers(ctx)
+	if err != nil || total > 0 {
+		return err
+	}
+	if username == "" || password == "" {
+		log.Println("no users yet, set AUTH_ADMIN_USERNAME and AUTH_ADMIN_PASSWORD to create the first one")
+		return nil
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	_, err = s.userRepository.CreateUser(ctx, &domain.User{
+		Username:     username,
+		Name:         username,
+		PasswordHash: hash,
+		Active:       true,
+		Roles:        []string{domain.RoleOwner},
+	})
+	return err
+}
+
+func (s *AuthServiceImpl) tokenPair(user *domain.User, sessionID string, refreshToken string) (*domain.TokenPair, error) {
+	now := time.Now()
+	claims := accessClaims{
+		Username:  user.Username,
+		SessionID: sessionID,
+		TenantID:  user.TenantID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.Itoa(user.ID),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.accessTokenTTL)),
+		},
+	}
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.accessTokenTTL.Seconds()),
+		User:         user,
+	}, nil
+}
+
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// onTerminal reports whether a request may use the session: sessions not
+// opened with a PIN work anywhere, PIN sessions only on their terminal.
+func onTerminal(session *domain.Session, terminalKey string) bool {
+	if session.TerminalID == nil {
+		return true
+	}
+	return subtle.ConstantTimeCompare([]byte(hashToken(terminalKey)), []byte(session.TerminalKeyHash)) == 1
+}
+
+// randomToken returns 32 random bytes, URL-safe base64 encoded.
+func randomToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// hashToken is how refresh tokens, API keys and approval grants are stored: a leaked table
+// cannot be used to log in.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -11,8 +11,9 @@ type CategoryService interface {
 	GetCategories(ctx context.Context, page int, pageSize int) ([]domain.Category, int, error)
 	GetCategoryByID(ctx context.Context, id int) (*domain.Category, error)
 	CreateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error)
-	UpdateCategory(ctx context.Context, id int, category *domain.Category) (*domain.Category, error)
-	DeleteCategory(ctx context.Context, id int) error
+	UpdateCategory(ctx context.Context, id int, category *domain.Category, versions []int) (*domain.Category, error)
+	PatchCategory(ctx context.Context, id int, patch *domain.CategoryPatch, versions []int) (*domain.Category, error)
+	DeleteCategory(ctx context.Context, id int, versions []int) error
 }
 
 type CategoryServiceImpl struct {
@@ -44,18 +45,26 @@ func (s *CategoryServiceImpl) CreateCategory(ctx context.Context, category *doma
 	return s.categoryRepository.CreateCategory(ctx, category)
 }
 
-func (s *CategoryServiceImpl) UpdateCategory(ctx context.Context, id int, category *domain.Category) (*domain.Category, error) {
+func (s *CategoryServiceImpl) UpdateCategory(ctx context.Context, id int, category *domain.Category, versions []int) (*domain.Category, error) {
 	_, err := s.categoryRepository.GetCategoryByID(ctx, id)
 	if err != nil {
 		return nil, utils.ErrCategoryNotFound
 	}
-	return s.categoryRepository.UpdateCategory(ctx, id, category)
+	return s.categoryRepository.UpdateCategory(ctx, id, category, versions)
 }
 
-func (s *CategoryServiceImpl) DeleteCategory(ctx context.Context, id int) error {
+func (s *CategoryServiceImpl) PatchCategory(ctx context.Context, id int, patch *domain.CategoryPatch, versions []int) (*domain.Category, error) {
+	_, err := s.categoryRepository.GetCategoryByID(ctx, id)
+	if err != nil {
+		return nil, utils.ErrCategoryNotFound
+	}
+	return s.categoryRepository.PatchCategory(ctx, id, patch, versions)
+}
+
+func (s *CategoryServiceImpl) DeleteCategory(ctx context.Context, id int, versions []int) error {
 	_, err := s.categoryRepository.GetCategoryByID(ctx, id)
 	if err != nil {
 		return utils.ErrCategoryNotFound
 	}
-	return s.categoryRepository.DeleteCategory(ctx, id)
+	return s.categoryRepository.DeleteCategory(ctx, id, versions)
 }
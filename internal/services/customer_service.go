@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+	"strings"
+)
+
+type CustomerService interface {
+	GetCustomers(ctx context.Context, filter domain.CustomerFilter, page int, pageSize int) ([]domain.Customer, int, error)
+	GetCustomerByID(ctx context.Context, id int) (*domain.Customer, error)
+	CreateCustomer(ctx context.Context, customer *domain.Customer) (*domain.Customer, error)
+	UpdateCustomer(ctx context.Context, id int, customer *domain.Customer) (*domain.Customer, error)
+	DeleteCustomer(ctx context.Context, id int) error
+	GetCustomerHistory(ctx context.Context, id int, page int, pageSize int) (*domain.CustomerHistory, int, error)
+}
+
+type CustomerServiceImpl struct {
+	customerRepository    repository.CustomerRepository
+	transactionRepository repository.TransactionRepository
+}
+
+func NewCustomerService(customerRepository repository.CustomerRepository, transactionRepository repository.TransactionRepository) CustomerService {
+	return &CustomerServiceImpl{
+		customerRepository:    customerRepository,
+		transactionRepository: transactionRepository,
+	}
+}
+
+func (s *CustomerServiceImpl) GetCustomers(ctx context.Context, filter domain.CustomerFilter, page int, pageSize int) ([]domain.Customer, int, error) {
+	filter.Phone = normalizePhone(filter.Phone)
+
+	customers, total, err := s.customerRepository.GetCustomers(ctx, filter, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	if customers == nil {
+		customers = []domain.Customer{}
+	}
+	return customers, total, nil
+}
+
+func (s *CustomerServiceImpl) GetCustomerByID(ctx context.Context, id int) (*domain.Customer, error) {
+	customer, err := s.customerRepository.GetCustomerByID(ctx, id)
+	if err != nil {
+		return nil, utils.ErrCustomerNotFound
+	}
+	return customer, nil
+}
+
+func (s *CustomerServiceImpl) CreateCustomer(ctx context.Context, customer *domain.Customer) (*domain.Customer, error) {
+	customer.Phone = normalizePhone(customer.Phone)
+	return s.customerRepository.CreateCustomer(ctx, customer)
+}
+
+func (s *CustomerServiceImpl) UpdateCustomer(ctx context.Context, id int, customer *domain.Customer) (*domain.Customer, error) {
+	if _, err := s.customerRepository.GetCustomerByID(ctx, id); err != nil {
+		return nil, utils.ErrCustomerNotFound
+	}
+	customer.Phone = normalizePhone(customer.Phone)
+	return s.customerRepository.UpdateCustomer(ctx, id, customer)
+}
+
+func (s *CustomerServiceImpl) DeleteCustomer(ctx context.Context, id int) error {
+	customer, err := s.customerRepository.GetCustomerByID(ctx, id)
+	if err != nil {
+		return utils.ErrCustomerNotFound
+	}
+	if customer.CreditBalance > 0 {
+		return utils.ErrCustomerHasCredit
+	}
+	return s.customerRepository.DeleteCustomer(ctx, id)
+}
+
+// GetCustomerHistory returns a page of the customer's transactions, newest
+// first, together with their lifetime spend and visit count.
+func (s *CustomerServiceImpl) GetCustomerHistory(ctx context.Context, id int, page int, pageSize int) (*domain.CustomerHistory, int, error) {
+	customer, err := s.customerRepository.GetCustomerByID(ctx, id)
+	if err != nil {
+		return nil, 0, utils.ErrCustomerNotFound
+	}
+
+	stats, err := s.customerRepository.GetCustomerStats(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	transactions, total, err := s.transactionRepository.GetTransactions(ctx, domain.TransactionFilter{CustomerID: &id}, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	if transactions == nil {
+		transactions = []domain.Transaction{}
+	}
+
+	return &domain.CustomerHistory{
+		Customer:     *customer,
+		Stats:        *stats,
+		Transactions: transactions,
+	}, total, nil
+}
+
+// normalizePhone drops spaces, dashes and other punctuation from a phone
+// number, keeping a leading plus sign, so searches match however the number
+// was typed.
+func normalizePhone(phone string) string {
+	phone = strings.TrimSpace(phone)
+	var b strings.Builder
+	for i, r := range phone {
+		if (r >= '0' && r <= '9') || (r == '+' && i == 0) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+	"strings"
+)
+
+type GiftCardService interface {
+	GetGiftCards(ctx context.Context, page int, pageSize int) ([]domain.GiftCard, int, error)
+	GetGiftCardStatement(ctx context.Context, code string, page int, pageSize int) (*domain.GiftCardStatement, int, error)
+}
+
+type GiftCardServiceImpl struct {
+	giftCardRepository repository.GiftCardRepository
+}
+
+func NewGiftCardService(giftCardRepository repository.GiftCardRepository) GiftCardService {
+	return &GiftCardServiceImpl{giftCardRepository: giftCardRepository}
+}
+
+// GetGiftCards lists gift cards with their codes masked, so the listing
+// cannot be used to spend someone else's card.
+func (s *GiftCardServiceImpl) GetGiftCards(ctx context.Context, page int, pageSize int) ([]domain.GiftCard, int, error) {
+	cards, total, err := s.giftCardRepository.GetGiftCards(ctx, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	if cards == nil {
+		cards = []domain.GiftCard{}
+	}
+	for i := range cards {
+		cards[i].Code = cards[i].MaskedCode()
+	}
+	return cards, total, nil
+}
+
+// GetGiftCardStatement returns the balance of the card with the given code
+// and a page of its ledger, newest first.
+func (s *GiftCardServiceImpl) GetGiftCardStatement(ctx context.Context, code string, page int, pageSize int) (*domain.GiftCardStatement, int, error) {
+	card, err := s.giftCardRepository.GetGiftCardByCode(ctx, normalizeGiftCardCode(code))
+	if err != nil {
+		return nil, 0, utils.ErrGiftCardNotFound
+	}
+
+	entries, total, err := s.giftCardRepository.GetGiftCardLedger(ctx, card.ID, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	if entries == nil {
+		entries = []domain.GiftCardEntry{}
+	}
+	return &domain.GiftCardStatement{GiftCard: *card, Entries: entries}, total, nil
+}
+
+// giftCardAlphabet leaves out 0, O, 1 and I, which are easily misread. Its
+// 32 letters divide 256 evenly, so every letter is equally likely.
+const giftCardAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// newGiftCardCode returns a random code of 16 letters in groups of four,
+// carrying 80 bits of entropy.
+func newGiftCardCode() (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	var code strings.Builder
+	for i, b := range random {
+		if i > 0 && i%4 == 0 {
+			code.WriteByte('-')
+		}
+		code.WriteByte(giftCardAlphabet[int(b)%len(giftCardAlphabet)])
+	}
+	return code.String(), nil
+}
+
+// normalizeGiftCardCode accepts a code typed in any case, with or without
+// spaces and dashes, and returns it in the stored XXXX-XXXX-XXXX-XXXX form.
+func normalizeGiftCardCode(code string) string {
+	var letters strings.Builder
+	for _, r := range strings.ToUpper(code) {
+		if r != '-' && r != ' ' {
+			letters.WriteRune(r)
+		}
+	}
+
+	var normalized strings.Builder
+	for i, r := range []rune(letters.String()) {
+		if i > 0 && i%4 == 0 {
+			normalized.WriteByte('-')
+		}
+		normalized.WriteRune(r)
+	}
+	return normalized.String()
+}
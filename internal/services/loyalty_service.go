@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+)
+
+type LoyaltyService interface {
+	GetLoyaltySettings(ctx context.Context) (*domain.LoyaltySettings, error)
+	UpdateLoyaltySettings(ctx context.Context, settings *domain.LoyaltySettings) (*domain.LoyaltySettings, error)
+	SetProductBonus(ctx context.Context, productID int, points *int) error
+	SetCategoryBonus(ctx context.Context, categoryID int, points int) error
+	GetLoyaltyAccount(ctx context.Context, customerID int, page int, pageSize int) (*domain.LoyaltyAccount, int, error)
+}
+
+type LoyaltyServiceImpl struct {
+	loyaltyRepository  repository.LoyaltyRepository
+	productRepository  repository.ProductRepository
+	categoryRepository repository.CategoryRepository
+	customerRepository repository.CustomerRepository
+}
+
+func NewLoyaltyService(
+	loyaltyRepository repository.LoyaltyRepository,
+	productRepository repository.ProductRepository,
+	categoryRepository repository.CategoryRepository,
+	customerRepository repository.CustomerRepository,
+) LoyaltyService {
+	return &LoyaltyServiceImpl{
+		loyaltyRepository:  loyaltyRepository,
+		productRepository:  productRepository,
+		categoryRepository: categoryRepository,
+		customerRepository: customerRepository,
+	}
+}
+
+func (s *LoyaltyServiceImpl) GetLoyaltySettings(ctx context.Context) (*domain.LoyaltySettings, error) {
+	return s.loyaltyRepository.GetLoyaltySettings(ctx)
+}
+
+func (s *LoyaltyServiceImpl) UpdateLoyaltySettings(ctx context.Context, settings *domain.LoyaltySettings) (*domain.LoyaltySettings, error) {
+	return s.loyaltyRepository.UpdateLoyaltySettings(ctx, settings)
+}
+
+func (s *LoyaltyServiceImpl) SetProductBonus(ctx context.Context, productID int, points *int) error {
+	if _, err := s.productRepository.GetProductByID(ctx, productID); err != nil {
+		return utils.ErrProductNotFound
+	}
+	return s.loyaltyRepository.SetProductBonus(ctx, productID, points)
+}
+
+func (s *LoyaltyServiceImpl) SetCategoryBonus(ctx context.Context, categoryID int, points int) error {
+	if _, err := s.categoryRepository.GetCategoryByID(ctx, categoryID); err != nil {
+		return utils.ErrCategoryNotFound
+	}
+	return s.loyaltyRepository.SetCategoryBonus(ctx, categoryID, points)
+}
+
+func (s *LoyaltyServiceImpl) GetLoyaltyAccount(ctx context.Context, customerID int, page int, pageSize int) (*domain.LoyaltyAccount, int, error) {
+	if _, err := s.customerRepository.GetCustomerByID(ctx, customerID); err != nil {
+		return nil, 0, utils.ErrCustomerNotFound
+	}
+
+	account, total, err := s.loyaltyRepository.GetLoyaltyAccount(ctx, customerID, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	if account.Entries == nil {
+		account.Entries = []domain.LoyaltyEntry{}
+	}
+	return account, total, nil
+}
+
+// applyPayments records the tenders of a transaction whose total is final.
+// Without any tenders the whole total is taken as exact cash. Points are
+// worth PointValue Rupiah each; together with gift cards and the amount put
+// on account they may not pay more than the total. Cash must cover the rest and any
+// excess is given back as change.
+func applyPayments(transaction *domain.Transaction, payments []domain.CheckoutPayment, loyalty *domain.LoyaltySettings) error {
+	if len(payments) == 0 {
+		transaction.Payments = []domain.TransactionPayment{{
+			Method: domain.PaymentMethodCash,
+			Amount: transaction.TotalAmount,
+		}}
+		return nil
+	}
+
+	cash, points, onAccount, giftCards := 0, 0, 0, 0
+	for _, payment := range payments {
+		switch payment.Method {
+		case domain.PaymentMethodCash:
+			if payment.Amount <= 0 {
+				return fmt.Errorf("%w: cash amount must be positive", utils.ErrInvalidPayment)
+			}
+			cash += payment.Amount
+			transaction.Payments = append(transaction.Payments, domain.TransactionPayment{
+				Method: domain.PaymentMethodCash,
+				Amount: payment.Amount,
+			})
+		case domain.PaymentMethodPoints:
+			if !loyalty.Enabled {
+				return utils.ErrLoyaltyDisabled
+			}
+			if transaction.CustomerID == nil {
+				return utils.ErrLoyaltyCustomerRequired
+			}
+			if payment.Points <= 0 {
+				return fmt.Errorf("%w: points must be positive", utils.ErrInvalidPayment)
+			}
+			points += payment.Points
+			transaction.Payments = append(transaction.Payments, domain.TransactionPayment{
+				Method: domain.PaymentMethodPoints,
+				Amount: payment.Points * loyalty.PointValue,
+				Points: payment.Points,
+			})
+		case domain.PaymentMethodOnAccount:
+			if transaction.CustomerID == nil {
+				return utils.ErrCreditCustomerRequired
+			}
+			if payment.Amount <= 0 {
+				return fmt.Errorf("%w: on account amount must be positive", utils.ErrInvalidPayment)
+			}
+			onAccount += payment.Amount
+			transaction.Payments = append(transaction.Payments, domain.TransactionPayment{
+				Method: domain.PaymentMethodOnAccount,
+				Amount: payment.Amount,
+			})
+		case domain.PaymentMethodGiftCard:
+			if payment.GiftCardID == nil {
+				return utils.ErrGiftCardNotFound
+			}
+			if payment.Amount <= 0 {
+				return fmt.Errorf("%w: gift card amount must be positive", utils.ErrInvalidPayment)
+			}
+			giftCards += payment.Amount
+			transaction.Payments = append(transaction.Payments, domain.TransactionPayment{
+				Method:     domain.PaymentMethodGiftCard,
+				Amount:     payment.Amount,
+				GiftCardID: payment.GiftCardID,
+			})
+		default:
+			return fmt.Errorf("%w: unknown method %s", utils.ErrInvalidPayment, payment.Method)
+		}
+	}
+
+	if points > 0 && points < loyalty.MinRedeemPoints {
+		return fmt.Errorf("%w: at least %d points", utils.ErrMinRedeemPoints, loyalty.MinRedeemPoints)
+	}
+	pointsAmount := points * loyalty.PointValue
+	if pointsAmount+onAccount+giftCards > transaction.TotalAmount {
+		return fmt.Errorf("%w: non-cash payments exceed the transaction total", utils.ErrInvalidPayment)
+	}
+
+	due := transaction.TotalAmount - pointsAmount - onAccount - giftCards
+	if cash < due {
+		return utils.ErrInsufficientPayment
+	}
+	transaction.PointsRedeemed = points
+	transaction.OnAccountAmount = onAccount
+	transaction.ChangeAmount = cash - due
+	return nil
+}
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"errors"
+	domain "kasir-api/internal/domains"
+	"kasir-api/internal/utils"
+	"testing"
+)
+
+func TestPointsEarned(t *testing.T) {
+	loyalty := &domain.LoyaltySettings{Enabled: true, SpendPerPoint: 10000, PointValue: 100}
+	tests := []struct {
+		name        string
+		transaction domain.Transaction
+		bonus       map[int]int
+		points      int
+	}{
+		{
+			name:        "one point per spend step, rounded down",
+			transaction: domain.Transaction{TotalAmount: 59999},
+			points:      5,
+		},
+		{
+			name:        "amount paid with points earns nothing",
+			transaction: domain.Transaction{TotalAmount: 50000, PointsRedeemed: 200},
+			points:      3,
+		},
+		{
+			name:        "gift cards sold earn nothing",
+			transaction: domain.Transaction{TotalAmount: 150000, GiftCardAmount: 100000},
+			points:      5,
+		},
+		{
+			name:        "paid fully with points",
+			transaction: domain.Transaction{TotalAmount: 10000, PointsRedeemed: 100},
+			points:      0,
+		},
+		{
+			name: "bonus points per base unit",
+			transaction: domain.Transaction{
+				TotalAmount: 20000,
+				Items: []domain.TransactionItem{
+					{ProductID: 1, BaseQuantity: 12},
+					{ProductID: 2, BaseQuantity: 3},
+				},
+			},
+			bonus:  map[int]int{1: 2},
+			points: 26,
+		},
+	}
+
+	for _, test := range tests {
+		if points := pointsEarned(&test.transaction, loyalty, test.bonus); points != test.points {
+			t.Errorf("%s: %d points, want %d", test.name, points, test.points)
+		}
+	}
+}
+
+func TestApplyPaymentsRedeemsPoints(t *testing.T) {
+	customerID := 3
+	enabled := domain.LoyaltySettings{Enabled: true, SpendPerPoint: 10000, PointValue: 100, MinRedeemPoints: 50}
+	tests := []struct {
+		name       string
+		loyalty    domain.LoyaltySettings
+		customerID *int
+		payments   []domain.CheckoutPayment
+		redeemed   int
+		change     int
+		err        error
+	}{
+		{
+			name:       "points and cash",
+			loyalty:    enabled,
+			customerID: &customerID,
+			payments: []domain.CheckoutPayment{
+				{Method: domain.PaymentMethodPoints, Points: 100},
+				{Method: domain.PaymentMethodCash, Amount: 50000},
+			},
+			redeemed: 100,
+			change:   10000,
+		},
+		{
+			name:       "points pay the whole total",
+			loyalty:    enabled,
+			customerID: &customerID,
+			payments:   []domain.CheckoutPayment{{Method: domain.PaymentMethodPoints, Points: 500}},
+			redeemed:   500,
+		},
+		{
+			name:       "points worth more than the total",
+			loyalty:    enabled,
+			customerID: &customerID,
+			payments:   []domain.CheckoutPayment{{Method: domain.PaymentMethodPoints, Points: 501}},
+			err:        utils.ErrInvalidPayment,
+		},
+		{
+			name:       "below the minimum",
+			loyalty:    enabled,
+			customerID: &customerID,
+			payments: []domain.CheckoutPayment{
+				{Method: domain.PaymentMethodPoints, Points: 49},
+				{Method: domain.PaymentMethodCash, Amount: 50000},
+			},
+			err: utils.ErrMinRedeemPoints,
+		},
+		{
+			name:     "without a customer",
+			loyalty:  enabled,
+			payments: []domain.CheckoutPayment{{Method: domain.PaymentMethodPoints, Points: 100}},
+			err:      utils.ErrLoyaltyCustomerRequired,
+		},
+		{
+			name:       "loyalty disabled",
+			customerID: &customerID,
+			payments:   []domain.CheckoutPayment{{Method: domain.PaymentMethodPoints, Points: 100}},
+			err:        utils.ErrLoyaltyDisabled,
+		},
+		{
+			name:       "cash short of the rest",
+			loyalty:    enabled,
+			customerID: &customerID,
+			payments: []domain.CheckoutPayment{
+				{Method: domain.PaymentMethodPoints, Points: 100},
+				{Method: domain.PaymentMethodCash, Amount: 39999},
+			},
+			err: utils.ErrInsufficientPayment,
+		},
+	}
+
+	for _, test := range tests {
+		transaction := &domain.Transaction{TotalAmount: 50000, CustomerID: test.customerID}
+
+		err := applyPayments(transaction, test.payments, &test.loyalty)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: error %v, want %v", test.name, err, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if transaction.PointsRedeemed != test.redeemed || transaction.ChangeAmount != test.change {
+			t.Errorf("%s: %d points redeemed, change %d, want %d and %d",
+				test.name, transaction.PointsRedeemed, transaction.ChangeAmount, test.redeemed, test.change)
+		}
+	}
+}
+
+func TestApplyPaymentsOnAccount(t *testing.T) {
+	customerID := 3
+	tests := []struct {
+		name       string
+		customerID *int
+		payments   []domain.CheckoutPayment
+		onAccount  int
+		change     int
+		err        error
+	}{
+		{
+			name:       "whole total on account",
+			customerID: &customerID,
+			payments:   []domain.CheckoutPayment{{Method: domain.PaymentMethodOnAccount, Amount: 50000}},
+			onAccount:  50000,
+		},
+		{
+			name:       "part on account, rest in cash",
+			customerID: &customerID,
+			payments: []domain.CheckoutPayment{
+				{Method: domain.PaymentMethodOnAccount, Amount: 30000},
+				{Method: domain.PaymentMethodCash, Amount: 25000},
+			},
+			onAccount: 30000,
+			change:    5000,
+		},
+		{
+			name:       "split over several entries",
+			customerID: &customerID,
+			payments: []domain.CheckoutPayment{
+				{Method: domain.PaymentMethodOnAccount, Amount: 20000},
+				{Method: domain.PaymentMethodOnAccount, Amount: 30000},
+			},
+			onAccount: 50000,
+		},
+		{
+			name:       "more than the total",
+			customerID: &customerID,
+			payments:   []domain.CheckoutPayment{{Method: domain.PaymentMethodOnAccount, Amount: 50001}},
+			err:        utils.ErrInvalidPayment,
+		},
+		{
+			name:       "not positive",
+			customerID: &customerID,
+			payments:   []domain.CheckoutPayment{{Method: domain.PaymentMethodOnAccount}},
+			err:        utils.ErrInvalidPayment,
+		},
+		{
+			name:     "without a customer",
+			payments: []domain.CheckoutPayment{{Method: domain.PaymentMethodOnAccount, Amount: 50000}},
+			err:      utils.ErrCreditCustomerRequired,
+		},
+		{
+			name:       "cash short of the rest",
+			customerID: &customerID,
+			payments: []domain.CheckoutPayment{
+				{Method: domain.PaymentMethodOnAccount, Amount: 30000},
+				{Method: domain.PaymentMethodCash, Amount: 10000},
+			},
+			err: utils.ErrInsufficientPayment,
+		},
+	}
+
+	for _, test := range tests {
+		transaction := &domain.Transaction{TotalAmount: 50000, CustomerID: test.customerID}
+
+		err := applyPayments(transaction, test.payments, &domain.LoyaltySettings{})
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: error %v, want %v", test.name, err, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if transaction.OnAccountAmount != test.onAccount || transaction.ChangeAmount != test.change {
+			t.Errorf("%s: %d on account, change %d, want %d and %d",
+				test.name, transaction.OnAccountAmount, transaction.ChangeAmount, test.onAccount, test.change)
+		}
+	}
+}
+
+func TestApplyPaymentsGiftCards(t *testing.T) {
+	customerID := 3
+	cardA, cardB := 11, 12
+	loyalty := domain.LoyaltySettings{Enabled: true, SpendPerPoint: 10000, PointValue: 100}
+	tests := []struct {
+		name     string
+		payments []domain.CheckoutPayment
+		change   int
+		err      error
+	}{
+		{
+			name:     "one card pays the whole total",
+			payments: []domain.CheckoutPayment{{Method: domain.PaymentMethodGiftCard, Amount: 50000, GiftCardID: &cardA}},
+		},
+		{
+			name: "two cards",
+			payments: []domain.CheckoutPayment{
+				{Method: domain.PaymentMethodGiftCard, Amount: 20000, GiftCardID: &cardA},
+				{Method: domain.PaymentMethodGiftCard, Amount: 30000, GiftCardID: &cardB},
+			},
+		},
+		{
+			name: "change only comes from cash",
+			payments: []domain.CheckoutPayment{
+				{Method: domain.PaymentMethodGiftCard, Amount: 30000, GiftCardID: &cardA},
+				{Method: domain.PaymentMethodCash, Amount: 30000},
+			},
+			change: 10000,
+		},
+		{
+			name:     "card worth more than the total",
+			payments: []domain.CheckoutPayment{{Method: domain.PaymentMethodGiftCard, Amount: 50001, GiftCardID: &cardA}},
+			err:      utils.ErrInvalidPayment,
+		},
+		{
+			name: "card, points and on account together over the total",
+			payments: []domain.CheckoutPayment{
+				{Method: domain.PaymentMethodGiftCard, Amount: 20000, GiftCardID: &cardA},
+				{Method: domain.PaymentMethodPoints, Points: 100},
+				{Method: domain.PaymentMethodOnAccount, Amount: 20001},
+			},
+			err: utils.ErrInvalidPayment,
+		},
+		{
+			name:     "card not resolved",
+			payments: []domain.CheckoutPayment{{Method: domain.PaymentMethodGiftCard, Amount: 50000}},
+			err:      utils.ErrGiftCardNotFound,
+		},
+		{
+			name:     "not positive",
+			payments: []domain.CheckoutPayment{{Method: domain.PaymentMethodGiftCard, GiftCardID: &cardA}},
+			err:      utils.ErrInvalidPayment,
+		},
+		{
+			name: "cash short of the rest",
+			payments: []domain.CheckoutPayment{
+				{Method: domain.PaymentMethodGiftCard, Amount: 30000, GiftCardID: &cardA},
+				{Method: domain.PaymentMethodCash, Amount: 19999},
+			},
+			err: utils.ErrInsufficientPayment,
+		},
+	}
+
+	for _, test := range tests {
+		transaction := &domain.Transaction{TotalAmount: 50000, CustomerID: &customerID}
+
+		err := applyPayments(transaction, test.payments, &loyalty)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: error %v, want %v", test.name, err, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if transaction.ChangeAmount != test.change {
+			t.Errorf("%s: change %d, want %d", test.name, transaction.ChangeAmount, test.change)
+		}
+		for i, payment := range transaction.Payments {
+			if payment.Amount != test.payments[i].Amount || payment.GiftCardID != test.payments[i].GiftCardID {
+				t.Errorf("%s: payment %d is %+v, want %+v", test.name, i, payment, test.payments[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+)
+
+type ModifierService interface {
+	GetModifierGroups(ctx context.Context, page int, pageSize int) ([]domain.ModifierGroup, int, error)
+	GetModifierGroupByID(ctx context.Context, id int) (*domain.ModifierGroup, error)
+	CreateModifierGroup(ctx context.Context, group *domain.ModifierGroup) (*domain.ModifierGroup, error)
+	UpdateModifierGroup(ctx context.Context, id int, group *domain.ModifierGroup) (*domain.ModifierGroup, error)
+	DeleteModifierGroup(ctx context.Context, id int) error
+	GetProductModifierGroups(ctx context.Context, productID int) ([]domain.ModifierGroup, error)
+	SetProductModifierGroups(ctx context.Context, productID int, groupIDs []int) ([]domain.ModifierGroup, error)
+	SetCategoryModifierGroups(ctx context.Context, categoryID int, groupIDs []int) error
+}
+
+type ModifierServiceImpl struct {
+	modifierRepository repository.ModifierRepository
+	productRepository  repository.ProductRepository
+	categoryRepository repository.CategoryRepository
+}
+
+func NewModifierService(
+	modifierRepository repository.ModifierRepository,
+	productRepository repository.ProductRepository,
+	categoryRepository repository.CategoryRepository,
+) ModifierService {
+	return &ModifierServiceImpl{
+		modifierRepository: modifierRepository,
+		productRepository:  productRepository,
+		categoryRepository: categoryRepository,
+	}
+}
+
+func (s *ModifierServiceImpl) GetModifierGroups(ctx context.Context, page int, pageSize int) ([]domain.ModifierGroup, int, error) {
+	groups, total, err := s.modifierRepository.GetModifierGroups(ctx, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if groups == nil {
+		groups = []domain.ModifierGroup{}
+	}
+
+	return groups, total, nil
+}
+
+func (s *ModifierServiceImpl) GetModifierGroupByID(ctx context.Context, id int) (*domain.ModifierGroup, error) {
+	group, err := s.modifierRepository.GetModifierGroupByID(ctx, id)
+	if err != nil {
+		return nil, utils.ErrModifierGroupNotFound
+	}
+	return group, nil
+}
+
+func (s *ModifierServiceImpl) CreateModifierGroup(ctx context.Context, group *domain.ModifierGroup) (*domain.ModifierGroup, error) {
+	if err := s.validateGroup(ctx, group); err != nil {
+		return nil, err
+	}
+	return s.modifierRepository.CreateModifierGroup(ctx, group)
+}
+
+func (s *ModifierServiceImpl) UpdateModifierGroup(ctx context.Context, id int, group *domain.ModifierGroup) (*domain.ModifierGroup, error) {
+	if _, err := s.modifierRepository.GetModifierGroupByID(ctx, id); err != nil {
+		return nil, utils.ErrModifierGroupNotFound
+	}
+	if err := s.validateGroup(ctx, group); err != nil {
+		return nil, err
+	}
+	return s.modifierRepository.UpdateModifierGroup(ctx, id, group)
+}
+
+func (s *ModifierServiceImpl) DeleteModifierGroup(ctx context.Context, id int) error {
+	if _, err := s.modifierRepository.GetModifierGroupByID(ctx, id); err != nil {
+		return utils.ErrModifierGroupNotFound
+	}
+	return s.modifierRepository.DeleteModifierGroup(ctx, id)
+}
+
+func (s *ModifierServiceImpl) GetProductModifierGroups(ctx context.Context, productID int) ([]domain.ModifierGroup, error) {
+	if _, err := s.productRepository.GetProductByID(ctx, productID); err != nil {
+		return nil, utils.ErrProductNotFound
+	}
+
+	groups, err := s.modifierRepository.GetProductModifierGroups(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if groups == nil {
+		groups = []domain.ModifierGroup{}
+	}
+	return groups, nil
+}
+
+func (s *ModifierServiceImpl) SetProductModifierGroups(ctx context.Context, productID int, groupIDs []int) ([]domain.ModifierGroup, error) {
+	if _, err := s.productRepository.GetProductByID(ctx, productID); err != nil {
+		return nil, utils.ErrProductNotFound
+	}
+	if err := s.ensureGroupsExist(ctx, groupIDs); err != nil {
+		return nil, err
+	}
+	if err := s.modifierRepository.SetProductModifierGroups(ctx, productID, groupIDs); err != nil {
+		return nil, err
+	}
+	return s.GetProductModifierGroups(ctx, productID)
+}
+
+func (s *ModifierServiceImpl) SetCategoryModifierGroups(ctx context.Context, categoryID int, groupIDs []int) error {
+	if _, err := s.categoryRepository.GetCategoryByID(ctx, categoryID); err != nil {
+		return utils.ErrCategoryNotFound
+	}
+	if err := s.ensureGroupsExist(ctx, groupIDs); err != nil {
+		return err
+	}
+	return s.modifierRepository.SetCategoryModifierGroups(ctx, categoryID, groupIDs)
+}
+
+func (s *ModifierServiceImpl) ensureGroupsExist(ctx context.Context, groupIDs []int) error {
+	for _, id := range groupIDs {
+		if _, err := s.modifierRepository.GetModifierGroupByID(ctx, id); err != nil {
+			return utils.ErrModifierGroupNotFound
+		}
+	}
+	return nil
+}
+
+// validateGroup only accepts standard products as ingredients: bundles and
+// recipes hold no stock of their own to deduct.
+func (s *ModifierServiceImpl) validateGroup(ctx context.Context, group *domain.ModifierGroup) error {
+	if group.MaxSelect != 0 && group.MaxSelect < group.MinSelect {
+		return utils.ErrInvalidModifierGroup
+	}
+	for _, modifier := range group.Modifiers {
+		if modifier.IngredientProductID == nil {
+			continue
+		}
+		ingredient, err := s.productRepository.GetProductByID(ctx, *modifier.IngredientProductID)
+		if err != nil {
+			return utils.ErrProductNotFound
+		}
+		if ingredient.IsComposite() {
+			return utils.ErrInvalidIngredient
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+)
+
+type PriceHistoryService interface {
+	GetPriceHistory(ctx context.Context, productID int, page int, pageSize int) ([]domain.PriceHistory, int, error)
+	GetPriceChanges(ctx context.Context, filter domain.PriceHistoryFilter, page int, pageSize int) ([]domain.PriceHistory, int, error)
+}
+
+type PriceHistoryServiceImpl struct {
+	priceHistoryRepository repository.PriceHistoryRepository
+	productRepository      repository.ProductRepository
+}
+
+func NewPriceHistoryService(priceHistoryRepository repository.PriceHistoryRepository, productRepository repository.ProductRepository) PriceHistoryService {
+	return &PriceHistoryServiceImpl{
+		priceHistoryRepository: priceHistoryRepository,
+		productRepository:      productRepository,
+	}
+}
+
+func (s *PriceHistoryServiceImpl) GetPriceHistory(ctx context.Context, productID int, page int, pageSize int) ([]domain.PriceHistory, int, error) {
+	if _, err := s.productRepository.GetProductByID(ctx, productID); err != nil {
+		return nil, 0, utils.ErrProductNotFound
+	}
+
+	history, total, err := s.priceHistoryRepository.GetPriceHistory(ctx, productID, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	if history == nil {
+		history = []domain.PriceHistory{}
+	}
+	return history, total, nil
+}
+
+func (s *PriceHistoryServiceImpl) GetPriceChanges(ctx context.Context, filter domain.PriceHistoryFilter, page int, pageSize int) ([]domain.PriceHistory, int, error) {
+	history, total, err := s.priceHistoryRepository.GetPriceChanges(ctx, filter, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	if history == nil {
+		history = []domain.PriceHistory{}
+	}
+	return history, total, nil
+}
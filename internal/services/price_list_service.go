@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+)
+
+type PriceListService interface {
+	GetPriceLists(ctx context.Context) ([]domain.PriceList, error)
+	GetPriceListByID(ctx context.Context, id int) (*domain.PriceList, error)
+	CreatePriceList(ctx context.Context, priceList *domain.PriceList) (*domain.PriceList, error)
+	UpdatePriceList(ctx context.Context, id int, priceList *domain.PriceList) (*domain.PriceList, error)
+	DeletePriceList(ctx context.Context, id int) error
+	SetCustomerPriceList(ctx context.Context, customerID int, priceListID *int) error
+}
+
+type PriceListServiceImpl struct {
+	priceListRepository repository.PriceListRepository
+	productRepository   repository.ProductRepository
+	variantRepository   repository.VariantRepository
+	customerRepository  repository.CustomerRepository
+}
+
+func NewPriceListService(
+	priceListRepository repository.PriceListRepository,
+	productRepository repository.ProductRepository,
+	variantRepository repository.VariantRepository,
+	customerRepository repository.CustomerRepository,
+) PriceListService {
+	return &PriceListServiceImpl{
+		priceListRepository: priceListRepository,
+		productRepository:   productRepository,
+		variantRepository:   variantRepository,
+		customerRepository:  customerRepository,
+	}
+}
+
+func (s *PriceListServiceImpl) GetPriceLists(ctx context.Context) ([]domain.PriceList, error) {
+	priceLists, err := s.priceListRepository.GetPriceLists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if priceLists == nil {
+		priceLists = []domain.PriceList{}
+	}
+	return priceLists, nil
+}
+
+func (s *PriceListServiceImpl) GetPriceListByID(ctx context.Context, id int) (*domain.PriceList, error) {
+	priceList, err := s.priceListRepository.GetPriceListByID(ctx, id)
+	if err != nil {
+		return nil, utils.ErrPriceListNotFound
+	}
+	return priceList, nil
+}
+
+func (s *PriceListServiceImpl) CreatePriceList(ctx context.Context, priceList *domain.PriceList) (*domain.PriceList, error) {
+	if err := s.validateItems(ctx, priceList.Items); err != nil {
+		return nil, err
+	}
+	return s.priceListRepository.CreatePriceList(ctx, priceList)
+}
+
+func (s *PriceListServiceImpl) UpdatePriceList(ctx context.Context, id int, priceList *domain.PriceList) (*domain.PriceList, error) {
+	if _, err := s.priceListRepository.GetPriceListByID(ctx, id); err != nil {
+		return nil, utils.ErrPriceListNotFound
+	}
+	if err := s.validateItems(ctx, priceList.Items); err != nil {
+		return nil, err
+	}
+	return s.priceListRepository.UpdatePriceList(ctx, id, priceList)
+}
+
+func (s *PriceListServiceImpl) DeletePriceList(ctx context.Context, id int) error {
+	if _, err := s.priceListRepository.GetPriceListByID(ctx, id); err != nil {
+		return utils.ErrPriceListNotFound
+	}
+	return s.priceListRepository.DeletePriceList(ctx, id)
+}
+
+func (s *PriceListServiceImpl) SetCustomerPriceList(ctx context.Context, customerID int, priceListID *int) error {
+	if _, err := s.customerRepository.GetCustomerByID(ctx, customerID); err != nil {
+		return utils.ErrCustomerNotFound
+	}
+	if priceListID != nil {
+		if _, err := s.priceListRepository.GetPriceListByID(ctx, *priceListID); err != nil {
+			return utils.ErrPriceListNotFound
+		}
+	}
+	return s.priceListRepository.SetCustomerPriceList(ctx, customerID, priceListID)
+}
+
+func (s *PriceListServiceImpl) validateItems(ctx context.Context, items []domain.PriceListItem) error {
+	for _, item := range items {
+		if _, err := s.productRepository.GetProductByID(ctx, item.ProductID); err != nil {
+			return utils.ErrProductNotFound
+		}
+		if item.VariantID == nil {
+			continue
+		}
+		variant, err := s.variantRepository.GetVariantByID(ctx, *item.VariantID)
+		if err != nil || variant.ProductID != item.ProductID {
+			return utils.ErrVariantNotFound
+		}
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+	"time"
+)
+
+type PriceScheduleService interface {
+	GetScheduledPrices(ctx context.Context, productID int) ([]domain.ScheduledPrice, error)
+	CreateScheduledPrice(ctx context.Context, price *domain.ScheduledPrice) (*domain.ScheduledPrice, error)
+	DeleteScheduledPrice(ctx context.Context, id int) error
+
+	GetPriceWindows(ctx context.Context) ([]domain.PriceWindow, error)
+	GetPriceWindowByID(ctx context.Context, id int) (*domain.PriceWindow, error)
+	CreatePriceWindow(ctx context.Context, window *domain.PriceWindow) (*domain.PriceWindow, error)
+	UpdatePriceWindow(ctx context.Context, id int, window *domain.PriceWindow) (*domain.PriceWindow, error)
+	DeletePriceWindow(ctx context.Context, id int) error
+
+	ApplyDuePrices(ctx context.Context, at time.Time) error
+}
+
+type PriceScheduleServiceImpl struct {
+	priceScheduleRepository repository.PriceScheduleRepository
+	productRepository       repository.ProductRepository
+	categoryRepository      repository.CategoryRepository
+	tenantRepository        repository.TenantRepository
+}
+
+func NewPriceScheduleService(
+	priceScheduleRepository repository.PriceScheduleRepository,
+	productRepository repository.ProductRepository,
+	categoryRepository repository.CategoryRepository,
+	tenantRepository repository.TenantRepository,
+) PriceScheduleService {
+	return &PriceScheduleServiceImpl{
+		priceScheduleRepository: priceScheduleRepository,
+		productRepository:       productRepository,
+		categoryRepository:      categoryRepository,
+		tenantRepository:        tenantRepository,
+	}
+}
+
+// ApplyDuePrices writes the scheduled prices that are due to their products,
+// tenant by tenant. It runs in the background; until it does, reads already
+// see a due price as the product price. The audit log attributes the
+// changes to the system.
+func (s *PriceScheduleServiceImpl) ApplyDuePrices(ctx context.Context, at time.Time) error {
+	tenants, err := s.tenantRepository.GetTenants(ctx)
+	if err != nil {
+		return err
+	}
+	for _, tenant := range tenants {
+		tenantCtx := utils.WithActor(utils.WithTenantID(ctx, tenant.ID), domain.PriceChangedBySystem)
+		if err := s.priceScheduleRepository.ApplyDuePrices(tenantCtx, at); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *PriceScheduleServiceImpl) GetScheduledPrices(ctx context.Context, productID int) ([]domain.ScheduledPrice, error) {
+	if _, err := s.productRepository.GetProductByID(ctx, productID); err != nil {
+		return nil, utils.ErrProductNotFound
+	}
+
+	prices, err := s.priceScheduleRepository.GetScheduledPrices(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if prices == nil {
+		prices = []domain.ScheduledPrice{}
+	}
+	return prices, nil
+}
+
+func (s *PriceScheduleServiceImpl) CreateScheduledPrice(ctx context.Context, price *domain.ScheduledPrice) (*domain.ScheduledPrice, error) {
+	if _, err := s.productRepository.GetProductByID(ctx, price.ProductID); err != nil {
+		return nil, utils.ErrProductNotFound
+	}
+	return s.priceScheduleRepository.CreateScheduledPrice(ctx, price)
+}
+
+func (s *PriceScheduleServiceImpl) DeleteScheduledPrice(ctx context.Context, id int) error {
+	if err := s.priceScheduleRepository.DeleteScheduledPrice(ctx, id); err != nil {
+		return utils.ErrScheduledPriceNotFound
+	}
+	return nil
+}
+
+func (s *PriceScheduleServiceImpl) GetPriceWindows(ctx context.Context) ([]domain.PriceWindow, error) {
+	windows, err := s.priceScheduleRepository.GetPriceWindows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if windows == nil {
+		windows = []domain.PriceWindow{}
+	}
+	return windows, nil
+}
+
+func (s *PriceScheduleServiceImpl) GetPriceWindowByID(ctx context.Context, id int) (*domain.PriceWindow, error) {
+	window, err := s.priceScheduleRepository.GetPriceWindowByID(ctx, id)
+	if err != nil {
+		return nil, utils.ErrPriceWindowNotFound
+	}
+	return window, nil
+}
+
+func (s *PriceScheduleServiceImpl) CreatePriceWindow(ctx context.Context, window *domain.PriceWindow) (*domain.PriceWindow, error) {
+	if err := s.validatePriceWindow(ctx, window); err != nil {
+		return nil, err
+	}
+	return s.priceScheduleRepository.CreatePriceWindow(ctx, window)
+}
+
+func (s *PriceScheduleServiceImpl) UpdatePriceWindow(ctx context.Context, id int, window *domain.PriceWindow) (*domain.PriceWindow, error) {
+	if _, err := s.priceScheduleRepository.GetPriceWindowByID(ctx, id); err != nil {
+		return nil, utils.ErrPriceWindowNotFound
+	}
+	if err := s.validatePriceWindow(ctx, window); err != nil {
+		return nil, err
+	}
+	return s.priceScheduleRepository.UpdatePriceWindow(ctx, id, window)
+}
+
+func (s *PriceScheduleServiceImpl) DeletePriceWindow(ctx context.Context, id int) error {
+	if _, err := s.priceScheduleRepository.GetPriceWindowByID(ctx, id); err != nil {
+		return utils.ErrPriceWindowNotFound
+	}
+	return s.priceScheduleRepository.DeletePriceWindow(ctx, id)
+}
+
+// validatePriceWindow checks that a window targets exactly one product or
+// category, and that a fixed price is only set for a single product.
+func (s *PriceScheduleServiceImpl) validatePriceWindow(ctx context.Context, window *domain.PriceWindow) error {
+	if (window.ProductID == nil) == (window.CategoryID == nil) {
+		return utils.ErrInvalidPriceWindow
+	}
+	switch window.AdjustmentType {
+	case domain.PriceWindowPercentage:
+		if window.Value <= 0 || window.Value > 100 {
+			return utils.ErrInvalidPriceWindow
+		}
+	case domain.PriceWindowPrice:
+		if window.ProductID == nil {
+			return utils.ErrInvalidPriceWindow
+		}
+	}
+	if _, err := time.Parse("15:04", window.StartTime); err != nil {
+		return utils.ErrInvalidPriceWindow
+	}
+	if _, err := time.Parse("15:04", window.EndTime); err != nil {
+		return utils.ErrInvalidPriceWindow
+	}
+
+	if window.ProductID != nil {
+		if _, err := s.productRepository.GetProductByID(ctx, *window.ProductID); err != nil {
+			return utils.ErrProductNotFound
+		}
+	}
+	if window.CategoryID != nil {
+		if _, err := s.categoryRepository.GetCategoryByID(ctx, *window.CategoryID); err != nil {
+			return utils.ErrCategoryNotFound
+		}
+	}
+	return nil
+}
+
+// windowPrice returns the lowest price any running window gives a product
+// of the given category, or price when none is running.
+func windowPrice(price int, productID int, categoryID int, windows []domain.PriceWindow, at time.Time) int {
+	best := price
+	for _, window := range windows {
+		if !window.Covers(at) {
+			continue
+		}
+		if window.ProductID != nil && *window.ProductID != productID {
+			continue
+		}
+		if window.CategoryID != nil && *window.CategoryID != categoryID {
+			continue
+		}
+		best = min(best, window.Apply(price))
+	}
+	return best
+}
+
+// windowsApply reports whether any of the windows is for the product or its
+// category, whether or not it is running now.
+func windowsApply(productID int, categoryID int, windows []domain.PriceWindow) bool {
+	for _, window := range windows {
+		if window.ProductID != nil && *window.ProductID != productID {
+			continue
+		}
+		if window.CategoryID != nil && *window.CategoryID != categoryID {
+			continue
+		}
+		return true
+	}
+	return false
+}
@@ -2,29 +2,48 @@ package services
 
 import (
 	"context"
+	"fmt"
 	domain "kasir-api/internal/domains"
 	repository "kasir-api/internal/repositories"
 	"kasir-api/internal/utils"
+	"math"
+	"time"
 )
 
 type ProductService interface {
-	GetProducts(ctx context.Context, page int, pageSize int) ([]domain.Product, int, error)
-	GetProductByID(ctx context.Context, id int) (*domain.Product, error)
+	GetProducts(ctx context.Context, filter domain.ProductFilter, page int, pageSize int) ([]domain.Product, int, error)
+	ExportProducts(ctx context.Context, filter domain.ProductFilter, fn func(*domain.Product) error) error
+	GetProductByID(ctx context.Context, id int, includeVariants bool) (*domain.Product, error)
 	CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error)
-	UpdateProduct(ctx context.Context, id int, product *domain.Product) (*domain.Product, error)
-	DeleteProduct(ctx context.Context, id int) error
+	UpdateProduct(ctx context.Context, id int, product *domain.Product, versions []int) (*domain.Product, error)
+	PatchProduct(ctx context.Context, id int, patch *domain.ProductPatch, versions []int) (*domain.Product, error)
+	DeleteProduct(ctx context.Context, id int, versions []int) error
+	BulkUpdateProducts(ctx context.Context, update domain.BulkProductUpdate) (*domain.BulkProductUpdateResult, error)
+	GetComponents(ctx context.Context, productID int) ([]domain.ProductComponent, error)
+	ReplaceComponents(ctx context.Context, productID int, components []domain.ProductComponent) ([]domain.ProductComponent, error)
 }
 
 type ProductServiceImpl struct {
-	productRepository repository.ProductRepository
+	productRepository       repository.ProductRepository
+	variantRepository       repository.VariantRepository
+	priceScheduleRepository repository.PriceScheduleRepository
 }
 
-func NewProductService(productRepository repository.ProductRepository) ProductService {
-	return &ProductServiceImpl{productRepository: productRepository}
+func NewProductService(
+	productRepository repository.ProductRepository,
+	variantRepository repository.VariantRepository,
+	priceScheduleRepository repository.PriceScheduleRepository,
+) ProductService {
+	return &ProductServiceImpl{
+		productRepository:       productRepository,
+		variantRepository:       variantRepository,
+		priceScheduleRepository: priceScheduleRepository,
+	}
 }
 
-func (s *ProductServiceImpl) GetProducts(ctx context.Context, page int, pageSize int) ([]domain.Product, int, error) {
-	products, total, err := s.productRepository.GetProducts(ctx, page, pageSize)
+func (s *ProductServiceImpl) GetProducts(ctx context.Context, filter domain.ProductFilter, page int, pageSize int) ([]domain.Product, int, error) {
+	now := time.Now()
+	products, total, err := s.productRepository.GetProducts(ctx, filter, page, pageSize)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -33,29 +52,298 @@ func (s *ProductServiceImpl) GetProducts(ctx context.Context, page int, pageSize
 		products = []domain.Product{}
 	}
 
+	if err := s.attachPrices(ctx, products, now); err != nil {
+		return nil, 0, err
+	}
+
+	if filter.IncludeVariants {
+		if err := s.attachVariants(ctx, products); err != nil {
+			return nil, 0, err
+		}
+	}
+
 	return products, total, nil
 }
 
-func (s *ProductServiceImpl) GetProductByID(ctx context.Context, id int) (*domain.Product, error) {
-	return s.productRepository.GetProductByID(ctx, id)
+func (s *ProductServiceImpl) attachVariants(ctx context.Context, products []domain.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+
+	variants, err := s.variantRepository.GetVariantsByProductIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	byProduct := make(map[int][]domain.ProductVariant)
+	for _, variant := range variants {
+		byProduct[variant.ProductID] = append(byProduct[variant.ProductID], variant)
+	}
+	for i := range products {
+		products[i].Variants = byProduct[products[i].ID]
+	}
+	return nil
+}
+
+// attachPrices sets the price each product sells for right now and the
+// scheduled prices that are still to come. A scheduled price that is due
+// but not yet written by the background job already counts as the price,
+// so reading products never writes to them.
+func (s *ProductServiceImpl) attachPrices(ctx context.Context, products []domain.Product, at time.Time) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	productIDs := make([]int, len(products))
+	categoryIDs := make([]int, len(products))
+	for i, product := range products {
+		productIDs[i] = product.ID
+		categoryIDs[i] = product.Category.ID
+	}
+
+	windows, err := s.priceScheduleRepository.GetActivePriceWindows(ctx, productIDs, categoryIDs)
+	if err != nil {
+		return err
+	}
+	upcoming, err := s.priceScheduleRepository.GetUpcomingPrices(ctx, productIDs, at)
+	if err != nil {
+		return err
+	}
+	due, err := s.priceScheduleRepository.GetDuePrices(ctx, productIDs, at)
+	if err != nil {
+		return err
+	}
+	duePrices := make(map[int]int, len(due))
+	for _, price := range due {
+		duePrices[price.ProductID] = price.Price
+	}
+
+	byProduct := make(map[int][]domain.ScheduledPrice)
+	for _, price := range upcoming {
+		byProduct[price.ProductID] = append(byProduct[price.ProductID], price)
+	}
+	for i := range products {
+		price, due := duePrices[products[i].ID]
+		if due {
+			products[i].Price = price
+		}
+		current := windowPrice(products[i].Price, products[i].ID, products[i].Category.ID, windows, at)
+		products[i].CurrentPrice = &current
+		products[i].UpcomingPrices = byProduct[products[i].ID]
+		products[i].TimedPrice = due || len(products[i].UpcomingPrices) > 0 ||
+			windowsApply(products[i].ID, products[i].Category.ID, windows)
+	}
+	return nil
+}
+
+func (s *ProductServiceImpl) ExportProducts(ctx context.Context, filter domain.ProductFilter, fn func(*domain.Product) error) error {
+	return s.productRepository.StreamProducts(ctx, filter, fn)
+}
+
+func (s *ProductServiceImpl) GetProductByID(ctx context.Context, id int, includeVariants bool) (*domain.Product, error) {
+	now := time.Now()
+	product, err := s.productRepository.GetProductByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	products := []domain.Product{*product}
+	if err := s.attachPrices(ctx, products, now); err != nil {
+		return nil, err
+	}
+	product = &products[0]
+	if !includeVariants {
+		return product, nil
+	}
+
+	options, err := s.variantRepository.GetVariantOptions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	product.VariantOptions = options
+
+	products = []domain.Product{*product}
+	if err := s.attachVariants(ctx, products); err != nil {
+		return nil, err
+	}
+	return &products[0], nil
 }
 
 func (s *ProductServiceImpl) CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
+	if product.Type == "" {
+		product.Type = domain.ProductTypeStandard
+	}
+	if product.BaseUnit == "" {
+		product.BaseUnit = domain.DefaultBaseUnit
+	}
 	return s.productRepository.CreateProduct(ctx, product)
 }
 
-func (s *ProductServiceImpl) UpdateProduct(ctx context.Context, id int, product *domain.Product) (*domain.Product, error) {
-	_, err := s.productRepository.GetProductByID(ctx, id)
+func (s *ProductServiceImpl) UpdateProduct(ctx context.Context, id int, product *domain.Product, versions []int) (*domain.Product, error) {
+	before, err := s.productRepository.GetProductByID(ctx, id)
+	if err != nil {
+		return nil, utils.ErrProductNotFound
+	}
+	if product.Type == "" {
+		product.Type = before.Type
+	}
+	if product.BaseUnit == "" {
+		product.BaseUnit = before.BaseUnit
+	}
+	if err := s.checkProductType(ctx, before, product.Type); err != nil {
+		return nil, err
+	}
+	return s.productRepository.UpdateProduct(ctx, id, product, versions)
+}
+
+func (s *ProductServiceImpl) PatchProduct(ctx context.Context, id int, patch *domain.ProductPatch, versions []int) (*domain.Product, error) {
+	before, err := s.productRepository.GetProductByID(ctx, id)
 	if err != nil {
 		return nil, utils.ErrProductNotFound
 	}
-	return s.productRepository.UpdateProduct(ctx, id, product)
+	if patch.Type != nil {
+		if err := s.checkProductType(ctx, before, *patch.Type); err != nil {
+			return nil, err
+		}
+	}
+	return s.productRepository.PatchProduct(ctx, id, patch, versions)
 }
 
-func (s *ProductServiceImpl) DeleteProduct(ctx context.Context, id int) error {
+func (s *ProductServiceImpl) DeleteProduct(ctx context.Context, id int, versions []int) error {
 	_, err := s.productRepository.GetProductByID(ctx, id)
 	if err != nil {
 		return utils.ErrProductNotFound
 	}
-	return s.productRepository.DeleteProduct(ctx, id)
+	return s.productRepository.DeleteProduct(ctx, id, versions)
+}
+
+// checkProductType keeps composites one level deep, as ReplaceComponents
+// does: a standard product can only become a bundle or recipe while it is
+// not a component of another product or a modifier ingredient, and has no
+// variants.
+func (s *ProductServiceImpl) checkProductType(ctx context.Context, before *domain.Product, productType string) error {
+	after := domain.Product{Type: productType}
+	if before.IsComposite() || !after.IsComposite() {
+		return nil
+	}
+
+	isComponent, err := s.productRepository.IsComponent(ctx, before.ID)
+	if err != nil {
+		return err
+	}
+	if isComponent {
+		return utils.ErrNestedComposite
+	}
+
+	variants, err := s.variantRepository.GetVariantsByProductIDs(ctx, []int{before.ID})
+	if err != nil {
+		return err
+	}
+	if len(variants) > 0 {
+		return utils.ErrCompositeVariants
+	}
+	return nil
+}
+
+func (s *ProductServiceImpl) BulkUpdateProducts(ctx context.Context, update domain.BulkProductUpdate) (*domain.BulkProductUpdateResult, error) {
+	filter := update.Filter
+	if len(filter.IDs) == 0 && filter.CategoryID == 0 && filter.Search == "" {
+		return nil, utils.ErrBulkUpdateNoSelector
+	}
+	if update.PriceChangeType == "" && update.StockChangeType == "" {
+		return nil, utils.ErrBulkUpdateNoChange
+	}
+
+	apply := func(product *domain.Product) error {
+		switch update.PriceChangeType {
+		case domain.PriceChangePercentage:
+			product.Price = int(math.Round(float64(product.Price) * (1 + update.PriceValue/100)))
+		case domain.PriceChangeFixed:
+			product.Price += int(math.Round(update.PriceValue))
+		}
+		if product.Price < 0 {
+			return fmt.Errorf("%w: product %d", utils.ErrNegativePrice, product.ID)
+		}
+
+		// composite stock is derived from components and cannot be set
+		switch update.StockChangeType {
+		case domain.StockChangeSet:
+			if !product.IsComposite() {
+				product.Stock = update.StockValue
+			}
+		case domain.StockChangeAdjust:
+			if !product.IsComposite() {
+				product.Stock += update.StockValue
+			}
+		}
+		if product.Stock < 0 {
+			return fmt.Errorf("%w: product %d", utils.ErrNegativeStock, product.ID)
+		}
+		return nil
+	}
+
+	items, err := s.productRepository.BulkUpdateProducts(ctx, filter, apply, domain.PriceSourceBulk, update.Preview)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.BulkProductUpdateResult{
+		Preview:  update.Preview,
+		Affected: len(items),
+		Items:    items,
+	}, nil
+}
+
+func (s *ProductServiceImpl) GetComponents(ctx context.Context, productID int) ([]domain.ProductComponent, error) {
+	product, err := s.productRepository.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, utils.ErrProductNotFound
+	}
+	if !product.IsComposite() {
+		return nil, utils.ErrNotComposite
+	}
+
+	components, err := s.productRepository.GetComponents(ctx, []int{productID})
+	if err != nil {
+		return nil, err
+	}
+	if components == nil {
+		components = []domain.ProductComponent{}
+	}
+	return components, nil
+}
+
+// ReplaceComponents only accepts standard products as components, which keeps
+// composites one level deep and rules out cycles.
+func (s *ProductServiceImpl) ReplaceComponents(ctx context.Context, productID int, components []domain.ProductComponent) ([]domain.ProductComponent, error) {
+	product, err := s.productRepository.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, utils.ErrProductNotFound
+	}
+	if !product.IsComposite() {
+		return nil, utils.ErrNotComposite
+	}
+
+	seen := make(map[int]bool)
+	for _, component := range components {
+		if component.ComponentID == productID || seen[component.ComponentID] {
+			return nil, utils.ErrInvalidComponent
+		}
+		seen[component.ComponentID] = true
+
+		componentProduct, err := s.productRepository.GetProductByID(ctx, component.ComponentID)
+		if err != nil || componentProduct.IsComposite() {
+			return nil, utils.ErrInvalidComponent
+		}
+	}
+
+	if err := s.productRepository.ReplaceComponents(ctx, productID, components); err != nil {
+		return nil, err
+	}
+	return s.GetComponents(ctx, productID)
 }
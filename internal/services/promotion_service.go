@@ -0,0 +1,290 @@
+package services
+
+import (
+	"context"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+	"slices"
+)
+
+type PromotionService interface {
+	GetPromotions(ctx context.Context, page int, pageSize int) ([]domain.Promotion, int, error)
+	GetPromotionByID(ctx context.Context, id int) (*domain.Promotion, error)
+	CreatePromotion(ctx context.Context, promotion *domain.Promotion) (*domain.Promotion, error)
+	UpdatePromotion(ctx context.Context, id int, promotion *domain.Promotion) (*domain.Promotion, error)
+	DeletePromotion(ctx context.Context, id int) error
+}
+
+type PromotionServiceImpl struct {
+	promotionRepository repository.PromotionRepository
+	productRepository   repository.ProductRepository
+	categoryRepository  repository.CategoryRepository
+}
+
+func NewPromotionService(
+	promotionRepository repository.PromotionRepository,
+	productRepository repository.ProductRepository,
+	categoryRepository repository.CategoryRepository,
+) PromotionService {
+	return &PromotionServiceImpl{
+		promotionRepository: promotionRepository,
+		productRepository:   productRepository,
+		categoryRepository:  categoryRepository,
+	}
+}
+
+func (s *PromotionServiceImpl) GetPromotions(ctx context.Context, page int, pageSize int) ([]domain.Promotion, int, error) {
+	promotions, total, err := s.promotionRepository.GetPromotions(ctx, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if promotions == nil {
+		promotions = []domain.Promotion{}
+	}
+
+	return promotions, total, nil
+}
+
+func (s *PromotionServiceImpl) GetPromotionByID(ctx context.Context, id int) (*domain.Promotion, error) {
+	promotion, err := s.promotionRepository.GetPromotionByID(ctx, id)
+	if err != nil {
+		return nil, utils.ErrPromotionNotFound
+	}
+	return promotion, nil
+}
+
+func (s *PromotionServiceImpl) CreatePromotion(ctx context.Context, promotion *domain.Promotion) (*domain.Promotion, error) {
+	if err := s.validatePromotion(ctx, promotion); err != nil {
+		return nil, err
+	}
+	return s.promotionRepository.CreatePromotion(ctx, promotion)
+}
+
+func (s *PromotionServiceImpl) UpdatePromotion(ctx context.Context, id int, promotion *domain.Promotion) (*domain.Promotion, error) {
+	if _, err := s.promotionRepository.GetPromotionByID(ctx, id); err != nil {
+		return nil, utils.ErrPromotionNotFound
+	}
+	if err := s.validatePromotion(ctx, promotion); err != nil {
+		return nil, err
+	}
+	return s.promotionRepository.UpdatePromotion(ctx, id, promotion)
+}
+
+func (s *PromotionServiceImpl) DeletePromotion(ctx context.Context, id int) error {
+	if _, err := s.promotionRepository.GetPromotionByID(ctx, id); err != nil {
+		return utils.ErrPromotionNotFound
+	}
+	return s.promotionRepository.DeletePromotion(ctx, id)
+}
+
+// validatePromotion checks the fields each promotion type relies on and that
+// every targeted product and category exists.
+func (s *PromotionServiceImpl) validatePromotion(ctx context.Context, promotion *domain.Promotion) error {
+	switch promotion.Type {
+	case domain.PromotionTypePercentage:
+		if promotion.Value <= 0 || promotion.Value > 100 {
+			return utils.ErrInvalidPromotion
+		}
+	case domain.PromotionTypeFixed:
+		if promotion.Value <= 0 {
+			return utils.ErrInvalidPromotion
+		}
+	case domain.PromotionTypeBuyXGetY:
+		if promotion.BuyQuantity <= 0 || promotion.GetQuantity <= 0 {
+			return utils.ErrInvalidPromotion
+		}
+	case domain.PromotionTypeBundle:
+		if promotion.BundleQuantity < 2 || promotion.BundlePrice <= 0 {
+			return utils.ErrInvalidPromotion
+		}
+	}
+	if promotion.StartsAt != nil && promotion.EndsAt != nil && !promotion.EndsAt.After(*promotion.StartsAt) {
+		return utils.ErrInvalidPromotion
+	}
+
+	for _, id := range promotion.ProductIDs {
+		if _, err := s.productRepository.GetProductByID(ctx, id); err != nil {
+			return utils.ErrProductNotFound
+		}
+	}
+	for _, id := range promotion.CategoryIDs {
+		if _, err := s.categoryRepository.GetCategoryByID(ctx, id); err != nil {
+			return utils.ErrCategoryNotFound
+		}
+	}
+	return nil
+}
+
+// applyPromotions evaluates promotions, already sorted by priority, against
+// the priced lines of a transaction. Every discount is recorded with the
+// promotion that produced it and the transaction totals are updated.
+// categories maps each product on the transaction to its category.
+//
+// A cart discount is spread over its eligible lines in proportion to what
+// they still cost, so later promotions, line or cart, only discount what is
+// left and the total never drops below zero. The part of the cart discounts
+// each line took is returned.
+func applyPromotions(transaction *domain.Transaction, promotions []domain.Promotion, categories map[int]int) []int {
+	items := transaction.Items
+	discounted := make([]bool, len(items))
+	locked := make([]bool, len(items))
+	cartShares := make([]int, len(items))
+	cartDiscounted, cartLocked := false, false
+
+	net := func(i int) int {
+		return items[i].Subtotal - items[i].DiscountAmount - cartShares[i]
+	}
+
+	for _, promotion := range promotions {
+		if transaction.Subtotal < promotion.MinSpend {
+			continue
+		}
+
+		var eligible []int
+		for i, item := range items {
+			if locked[i] || (!promotion.Stackable && discounted[i]) {
+				continue
+			}
+			if promotionTargets(promotion, item.ProductID, categories[item.ProductID]) {
+				eligible = append(eligible, i)
+			}
+		}
+		if len(eligible) == 0 {
+			continue
+		}
+
+		if promotion.Scope == domain.PromotionScopeCart || promotion.Type == domain.PromotionTypeBundle {
+			if cartLocked || (!promotion.Stackable && cartDiscounted) {
+				continue
+			}
+
+			base := 0
+			for _, i := range eligible {
+				base += net(i)
+			}
+			amount := cartDiscount(promotion, items, eligible, base)
+			if amount <= 0 {
+				continue
+			}
+
+			transaction.Discounts = append(transaction.Discounts, domain.TransactionDiscount{
+				PromotionID: &promotion.ID,
+				Name:        promotion.Name,
+				Amount:      amount,
+				ItemIndex:   -1,
+			})
+			transaction.DiscountAmount += amount
+			shareCartDiscount(cartShares, eligible, net, amount, base)
+			cartDiscounted = true
+			cartLocked = !promotion.Stackable
+			for _, i := range eligible {
+				discounted[i] = true
+				locked[i] = locked[i] || !promotion.Stackable
+			}
+			continue
+		}
+
+		for _, i := range eligible {
+			amount := min(lineDiscount(promotion, items[i]), net(i))
+			if amount <= 0 {
+				continue
+			}
+
+			transaction.Discounts = append(transaction.Discounts, domain.TransactionDiscount{
+				PromotionID: &promotion.ID,
+				Name:        promotion.Name,
+				Amount:      amount,
+				ItemIndex:   i,
+			})
+			items[i].DiscountAmount += amount
+			transaction.DiscountAmount += amount
+			discounted[i] = true
+			locked[i] = !promotion.Stackable
+		}
+	}
+
+	transaction.TotalAmount = max(0, transaction.Subtotal-transaction.DiscountAmount)
+	return cartShares
+}
+
+// shareCartDiscount adds each eligible line's part of a cart discount to its
+// share, proportional to the line's remaining amount out of base. The last
+// line takes the rounding remainder.
+func shareCartDiscount(shares []int, eligible []int, net func(int) int, amount int, base int) {
+	remaining := amount
+	for n, i := range eligible {
+		share := remaining
+		if n < len(eligible)-1 {
+			share = min(amount*net(i)/base, remaining)
+		}
+		shares[i] += share
+		remaining -= share
+	}
+}
+
+func promotionTargets(promotion domain.Promotion, productID int, categoryID int) bool {
+	if len(promotion.ProductIDs) == 0 && len(promotion.CategoryIDs) == 0 {
+		return true
+	}
+	return slices.Contains(promotion.ProductIDs, productID) || slices.Contains(promotion.CategoryIDs, categoryID)
+}
+
+func lineDiscount(promotion domain.Promotion, item domain.TransactionItem) int {
+	switch promotion.Type {
+	case domain.PromotionTypePercentage:
+		return (item.Subtotal - item.DiscountAmount) * promotion.Value / 100
+	case domain.PromotionTypeFixed:
+		return promotion.Value * item.Quantity
+	case domain.PromotionTypeBuyXGetY:
+		free := item.Quantity / (promotion.BuyQuantity + promotion.GetQuantity) * promotion.GetQuantity
+		return free * item.UnitPrice
+	}
+	return 0
+}
+
+// cartDiscount returns the discount a cart-level or bundle promotion gives on
+// the eligible lines, whose remaining amount is base. Bundles group the most
+// expensive units first.
+func cartDiscount(promotion domain.Promotion, items []domain.TransactionItem, eligible []int, base int) int {
+	switch promotion.Type {
+	case domain.PromotionTypePercentage:
+		return base * promotion.Value / 100
+	case domain.PromotionTypeFixed:
+		return min(promotion.Value, base)
+	case domain.PromotionTypeBundle:
+		// units are grouped by price rather than listed one by one, since
+		// quantities can be large
+		type priced struct{ price, quantity int }
+		var groups []priced
+		for _, i := range eligible {
+			groups = append(groups, priced{items[i].UnitPrice, items[i].Quantity})
+		}
+		slices.SortFunc(groups, func(a, b priced) int { return b.price - a.price })
+
+		size := promotion.BundleQuantity
+		saving := func(total int) int { return max(0, total-promotion.BundlePrice) }
+		amount, partial, filled := 0, 0, 0
+		for _, group := range groups {
+			quantity := group.quantity
+			if filled > 0 {
+				take := min(quantity, size-filled)
+				partial += take * group.price
+				filled += take
+				quantity -= take
+				if filled == size {
+					amount += saving(partial)
+					partial, filled = 0, 0
+				}
+			}
+			bundles := quantity / size
+			amount += bundles * saving(size*group.price)
+			if rest := quantity - bundles*size; rest > 0 {
+				partial, filled = rest*group.price, rest
+			}
+		}
+		return min(amount, base)
+	}
+	return 0
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	domain "kasir-api/internal/domains"
+	"testing"
+)
+
+func transactionOf(items ...domain.TransactionItem) *domain.Transaction {
+	transaction := &domain.Transaction{Items: items}
+	for i := range transaction.Items {
+		transaction.Items[i].Subtotal = transaction.Items[i].UnitPrice * transaction.Items[i].Quantity
+		transaction.Subtotal += transaction.Items[i].Subtotal
+	}
+	return transaction
+}
+
+func TestApplyPromotionsStackedCartDiscountsStopAtZero(t *testing.T) {
+	transaction := transactionOf(domain.TransactionItem{ProductID: 1, UnitPrice: 100000, Quantity: 1})
+	promotions := []domain.Promotion{
+		{ID: 1, Type: domain.PromotionTypeFixed, Scope: domain.PromotionScopeCart, Value: 60000, Stackable: true},
+		{ID: 2, Type: domain.PromotionTypeFixed, Scope: domain.PromotionScopeCart, Value: 60000, Stackable: true},
+	}
+
+	applyPromotions(transaction, promotions, map[int]int{})
+
+	if transaction.DiscountAmount != 100000 {
+		t.Errorf("discount = %d, want 100000", transaction.DiscountAmount)
+	}
+	if transaction.TotalAmount != 0 {
+		t.Errorf("total = %d, want 0", transaction.TotalAmount)
+	}
+	if len(transaction.Discounts) != 2 || transaction.Discounts[1].Amount != 40000 {
+		t.Errorf("discounts = %+v, want the second one limited to 40000", transaction.Discounts)
+	}
+}
+
+func TestApplyPromotionsStackedCartPercentageUsesRemainder(t *testing.T) {
+	transaction := transactionOf(
+		domain.TransactionItem{ProductID: 1, UnitPrice: 30000, Quantity: 2},
+		domain.TransactionItem{ProductID: 2, UnitPrice: 40000, Quantity: 1},
+	)
+	promotions := []domain.Promotion{
+		{ID: 1, Type: domain.PromotionTypeFixed, Scope: domain.PromotionScopeCart, Value: 50000, Stackable: true},
+		{ID: 2, Type: domain.PromotionTypePercentage, Scope: domain.PromotionScopeCart, Value: 10, Stackable: true},
+	}
+
+	applyPromotions(transaction, promotions, map[int]int{})
+
+	// 10% of the 50000 left after the fixed discount
+	if transaction.DiscountAmount != 55000 || transaction.TotalAmount != 45000 {
+		t.Errorf("discount = %d, total = %d, want 55000 and 45000", transaction.DiscountAmount, transaction.TotalAmount)
+	}
+}
+
+func TestApplyPromotionsLineDiscountAfterCartDiscount(t *testing.T) {
+	transaction := transactionOf(domain.TransactionItem{ProductID: 1, UnitPrice: 10000, Quantity: 1})
+	promotions := []domain.Promotion{
+		{ID: 1, Type: domain.PromotionTypeFixed, Scope: domain.PromotionScopeCart, Value: 8000, Stackable: true},
+		{ID: 2, Type: domain.PromotionTypeFixed, Scope: domain.PromotionScopeLine, Value: 5000, Stackable: true},
+	}
+
+	applyPromotions(transaction, promotions, map[int]int{})
+
+	if transaction.DiscountAmount != 10000 || transaction.TotalAmount != 0 {
+		t.Errorf("discount = %d, total = %d, want 10000 and 0", transaction.DiscountAmount, transaction.TotalAmount)
+	}
+}
+
+func TestApplyPromotionsNonStackableLocksLines(t *testing.T) {
+	transaction := transactionOf(domain.TransactionItem{ProductID: 1, UnitPrice: 10000, Quantity: 2})
+	promotions := []domain.Promotion{
+		{ID: 1, Type: domain.PromotionTypePercentage, Scope: domain.PromotionScopeLine, Value: 10},
+		{ID: 2, Type: domain.PromotionTypeFixed, Scope: domain.PromotionScopeCart, Value: 1000, Stackable: true},
+	}
+
+	applyPromotions(transaction, promotions, map[int]int{})
+
+	if transaction.DiscountAmount != 2000 || len(transaction.Discounts) != 1 {
+		t.Errorf("discount = %d with %d discounts, want only the first promotion", transaction.DiscountAmount, len(transaction.Discounts))
+	}
+}
+
+func TestApplyPromotionsTargetsCategory(t *testing.T) {
+	transaction := transactionOf(
+		domain.TransactionItem{ProductID: 1, UnitPrice: 10000, Quantity: 1},
+		domain.TransactionItem{ProductID: 2, UnitPrice: 20000, Quantity: 1},
+	)
+	promotions := []domain.Promotion{
+		{ID: 1, Type: domain.PromotionTypePercentage, Scope: domain.PromotionScopeLine, Value: 50, CategoryIDs: []int{7}},
+	}
+
+	applyPromotions(transaction, promotions, map[int]int{1: 3, 2: 7})
+
+	if transaction.Items[0].DiscountAmount != 0 || transaction.Items[1].DiscountAmount != 10000 {
+		t.Errorf("line discounts = %d and %d, want 0 and 10000", transaction.Items[0].DiscountAmount, transaction.Items[1].DiscountAmount)
+	}
+}
+
+func TestApplyPromotionsMinSpend(t *testing.T) {
+	transaction := transactionOf(domain.TransactionItem{ProductID: 1, UnitPrice: 10000, Quantity: 1})
+	promotions := []domain.Promotion{
+		{ID: 1, Type: domain.PromotionTypeFixed, Scope: domain.PromotionScopeCart, Value: 1000, MinSpend: 50000},
+	}
+
+	applyPromotions(transaction, promotions, map[int]int{})
+
+	if transaction.DiscountAmount != 0 || transaction.TotalAmount != 10000 {
+		t.Errorf("discount = %d, total = %d, want no discount", transaction.DiscountAmount, transaction.TotalAmount)
+	}
+}
+
+func TestCartDiscountBundle(t *testing.T) {
+	bundle := domain.Promotion{Type: domain.PromotionTypeBundle, BundleQuantity: 3, BundlePrice: 25000}
+	tests := []struct {
+		name  string
+		items []domain.TransactionItem
+		want  int
+	}{
+		{
+			name:  "not enough units",
+			items: []domain.TransactionItem{{UnitPrice: 10000, Quantity: 2}},
+			want:  0,
+		},
+		{
+			name:  "one price",
+			items: []domain.TransactionItem{{UnitPrice: 10000, Quantity: 7}},
+			want:  2 * 5000,
+		},
+		{
+			// units 15000 15000 12000 | 12000 10000 10000 | 10000
+			name: "bundles across prices, most expensive first",
+			items: []domain.TransactionItem{
+				{UnitPrice: 10000, Quantity: 3},
+				{UnitPrice: 15000, Quantity: 2},
+				{UnitPrice: 12000, Quantity: 2},
+			},
+			want: (42000 - 25000) + (32000 - 25000),
+		},
+		{
+			name:  "bundle above its price gives nothing",
+			items: []domain.TransactionItem{{UnitPrice: 5000, Quantity: 6}},
+			want:  0,
+		},
+		{
+			name:  "large quantity",
+			items: []domain.TransactionItem{{UnitPrice: 10000, Quantity: 3_000_000_000}},
+			want:  1_000_000_000 * 5000,
+		},
+	}
+
+	for _, test := range tests {
+		eligible := make([]int, len(test.items))
+		base := 0
+		for i, item := range test.items {
+			eligible[i] = i
+			base += item.UnitPrice * item.Quantity
+		}
+		if got := cartDiscount(bundle, test.items, eligible, base); got != test.want {
+			t.Errorf("%s: discount = %d, want %d", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+)
+
+type PurchaseService interface {
+	CreatePurchase(ctx context.Context, supplier string, lines []domain.PurchaseLine) (*domain.Purchase, error)
+	GetPurchases(ctx context.Context, page int, pageSize int) ([]domain.Purchase, int, error)
+	GetPurchaseByID(ctx context.Context, id int) (*domain.Purchase, error)
+}
+
+type PurchaseServiceImpl struct {
+	purchaseRepository repository.PurchaseRepository
+	productRepository  repository.ProductRepository
+	variantRepository  repository.VariantRepository
+	unitRepository     repository.UnitRepository
+}
+
+func NewPurchaseService(
+	purchaseRepository repository.PurchaseRepository,
+	productRepository repository.ProductRepository,
+	variantRepository repository.VariantRepository,
+	unitRepository repository.UnitRepository,
+) PurchaseService {
+	return &PurchaseServiceImpl{
+		purchaseRepository: purchaseRepository,
+		productRepository:  productRepository,
+		variantRepository:  variantRepository,
+		unitRepository:     unitRepository,
+	}
+}
+
+// CreatePurchase receives goods into stock. Each line may be bought in any
+// unit of the product; stock is always added in the base unit.
+func (s *PurchaseServiceImpl) CreatePurchase(ctx context.Context, supplier string, lines []domain.PurchaseLine) (*domain.Purchase, error) {
+	purchase := &domain.Purchase{Supplier: supplier}
+
+	for _, line := range lines {
+		product, err := s.productRepository.GetProductByID(ctx, line.ProductID)
+		if err != nil {
+			return nil, utils.ErrProductNotFound
+		}
+		if product.IsComposite() {
+			return nil, utils.ErrCompositeStock
+		}
+
+		if line.VariantID != nil {
+			variant, err := s.variantRepository.GetVariantByID(ctx, *line.VariantID)
+			if err != nil || variant.ProductID != product.ID {
+				return nil, utils.ErrVariantNotFound
+			}
+		} else {
+			variants, err := s.variantRepository.GetVariantsByProductIDs(ctx, []int{product.ID})
+			if err != nil {
+				return nil, err
+			}
+			if len(variants) > 0 {
+				return nil, utils.ErrVariantRequired
+			}
+		}
+
+		unit, err := resolveUnit(ctx, s.unitRepository, product, line.UnitID)
+		if err != nil {
+			return nil, err
+		}
+
+		item := domain.PurchaseItem{
+			ProductID:    product.ID,
+			VariantID:    line.VariantID,
+			UnitID:       line.UnitID,
+			UnitName:     unit.Name,
+			Quantity:     line.Quantity,
+			BaseQuantity: line.Quantity * unit.ConversionFactor,
+			UnitCost:     line.UnitCost,
+			Subtotal:     line.Quantity * line.UnitCost,
+		}
+		purchase.Items = append(purchase.Items, item)
+		purchase.TotalCost += item.Subtotal
+		purchase.StockMovements = append(purchase.StockMovements, domain.StockMovement{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.BaseQuantity,
+			Reason:    domain.StockReasonPurchase,
+		})
+	}
+
+	return s.purchaseRepository.CreatePurchase(ctx, purchase)
+}
+
+func (s *PurchaseServiceImpl) GetPurchases(ctx context.Context, page int, pageSize int) ([]domain.Purchase, int, error) {
+	purchases, total, err := s.purchaseRepository.GetPurchases(ctx, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if purchases == nil {
+		purchases = []domain.Purchase{}
+	}
+
+	return purchases, total, nil
+}
+
+func (s *PurchaseServiceImpl) GetPurchaseByID(ctx context.Context, id int) (*domain.Purchase, error) {
+	purchase, err := s.purchaseRepository.GetPurchaseByID(ctx, id)
+	if err != nil {
+		return nil, utils.ErrPurchaseNotFound
+	}
+	return purchase, nil
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+	"math"
+	"time"
+)
+
+type ReceivableService interface {
+	SetCreditLimit(ctx context.Context, customerID int, limit int) error
+	GetReceivableAccount(ctx context.Context, customerID int, page int, pageSize int) (*domain.ReceivableAccount, int, error)
+	RecordRepayment(ctx context.Context, repayment *domain.Repayment) ([]domain.ReceivableEntry, error)
+	GetAgingReport(ctx context.Context, asOf time.Time) (*domain.AgingReport, error)
+}
+
+type ReceivableServiceImpl struct {
+	receivableRepository repository.ReceivableRepository
+	customerRepository   repository.CustomerRepository
+}
+
+func NewReceivableService(receivableRepository repository.ReceivableRepository, customerRepository repository.CustomerRepository) ReceivableService {
+	return &ReceivableServiceImpl{
+		receivableRepository: receivableRepository,
+		customerRepository:   customerRepository,
+	}
+}
+
+func (s *ReceivableServiceImpl) SetCreditLimit(ctx context.Context, customerID int, limit int) error {
+	if _, err := s.customerRepository.GetCustomerByID(ctx, customerID); err != nil {
+		return utils.ErrCustomerNotFound
+	}
+	return s.receivableRepository.SetCreditLimit(ctx, customerID, limit)
+}
+
+func (s *ReceivableServiceImpl) GetReceivableAccount(ctx context.Context, customerID int, page int, pageSize int) (*domain.ReceivableAccount, int, error) {
+	if _, err := s.customerRepository.GetCustomerByID(ctx, customerID); err != nil {
+		return nil, 0, utils.ErrCustomerNotFound
+	}
+
+	account, total, err := s.receivableRepository.GetReceivableAccount(ctx, customerID, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	if account.Open == nil {
+		account.Open = []domain.OpenReceivable{}
+	}
+	if account.Entries == nil {
+		account.Entries = []domain.ReceivableEntry{}
+	}
+	return account, total, nil
+}
+
+func (s *ReceivableServiceImpl) RecordRepayment(ctx context.Context, repayment *domain.Repayment) ([]domain.ReceivableEntry, error) {
+	if _, err := s.customerRepository.GetCustomerByID(ctx, repayment.CustomerID); err != nil {
+		return nil, utils.ErrCustomerNotFound
+	}
+	return s.receivableRepository.RecordRepayment(ctx, repayment)
+}
+
+// GetAgingReport buckets what every customer owed at the end of the asOf
+// day by the age of the transactions behind it, in whole days.
+func (s *ReceivableServiceImpl) GetAgingReport(ctx context.Context, asOf time.Time) (*domain.AgingReport, error) {
+	open, err := s.receivableRepository.GetOpenReceivables(ctx, asOf.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.AgingReport{AsOf: asOf, Customers: []domain.AgingRow{}}
+	rows := make(map[int]int)
+	for _, receivable := range open {
+		index, ok := rows[receivable.CustomerID]
+		if !ok {
+			index = len(report.Customers)
+			rows[receivable.CustomerID] = index
+			report.Customers = append(report.Customers, domain.AgingRow{
+				CustomerID:   receivable.CustomerID,
+				CustomerName: receivable.CustomerName,
+			})
+		}
+
+		created := receivable.CreatedAt.In(asOf.Location())
+		day := time.Date(created.Year(), created.Month(), created.Day(), 0, 0, 0, 0, asOf.Location())
+		age := int(math.Round(asOf.Sub(day).Hours() / 24))
+
+		report.Customers[index].Add(age, receivable.Outstanding)
+		report.Totals.Add(age, receivable.Outstanding)
+	}
+	return report, nil
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+	"slices"
+)
+
+type RoleService interface {
+	GetPermissions() []string
+	GetRoles(ctx context.Context) ([]domain.Role, error)
+	GetRoleByID(ctx context.Context, id int) (*domain.Role, error)
+	CreateRole(ctx context.Context, role *domain.Role) (*domain.Role, error)
+	UpdateRole(ctx context.Context, id int, role *domain.Role) (*domain.Role, error)
+	DeleteRole(ctx context.Context, id int) error
+	SetUserRoles(ctx context.Context, userID int, roles []string) (*domain.User, error)
+}
+
+type RoleServiceImpl struct {
+	roleRepository repository.RoleRepository
+	userRepository repository.UserRepository
+}
+
+func NewRoleService(roleRepository repository.RoleRepository, userRepository repository.UserRepository) RoleService {
+	return &RoleServiceImpl{
+		roleRepository: roleRepository,
+		userRepository: userRepository,
+	}
+}
+
+func (s *RoleServiceImpl) GetPermissions() []string {
+	return domain.Permissions
+}
+
+func (s *RoleServiceImpl) GetRoles(ctx context.Context) ([]domain.Role, error) {
+	roles, err := s.roleRepository.GetRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if roles == nil {
+		roles = []domain.Role{}
+	}
+	return roles, nil
+}
+
+func (s *RoleServiceImpl) GetRoleByID(ctx context.Context, id int) (*domain.Role, error) {
+	role, err := s.roleRepository.GetRoleByID(ctx, id)
+	if err != nil {
+		return nil, utils.ErrRoleNotFound
+	}
+	return role, nil
+}
+
+func (s *RoleServiceImpl) CreateRole(ctx context.Context, role *domain.Role) (*domain.Role, error) {
+	if err := normalizePermissions(role); err != nil {
+		return nil, err
+	}
+	return s.roleRepository.CreateRole(ctx, role)
+}
+
+func (s *RoleServiceImpl) UpdateRole(ctx context.Context, id int, role *domain.Role) (*domain.Role, error) {
+	role.ID = id
+	if err := normalizePermissions(role); err != nil {
+		return nil, err
+	}
+	return s.roleRepository.UpdateRole(ctx, role)
+}
+
+func (s *RoleServiceImpl) DeleteRole(ctx context.Context, id int) error {
+	return s.roleRepository.DeleteRole(ctx, id)
+}
+
+// SetUserRoles replaces the roles of a user and returns the updated user.
+func (s *RoleServiceImpl) SetUserRoles(ctx context.Context, userID int, roles []string) (*domain.User, error) {
+	if _, err := s.userRepository.GetUserByID(ctx, userID); err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+	if err := s.roleRepository.SetUserRoles(ctx, userID, uniqueStrings(roles)); err != nil {
+		return nil, err
+	}
+	return s.userRepository.GetUserByID(ctx, userID)
+}
+
+// normalizePermissions sorts and de-duplicates the permissions of a role,
+// rejecting any the API does not know.
+func normalizePermissions(role *domain.Role) error {
+	for _, permission := range role.Permissions {
+		if !domain.IsPermission(permission) {
+			return fmt.Errorf("%w: %s", utils.ErrUnknownPermission, permission)
+		}
+	}
+	role.Permissions = uniqueStrings(role.Permissions)
+	return nil
+}
+
+func uniqueStrings(values []string) []string {
+	values = append([]string{}, values...)
+	slices.Sort(values)
+	return slices.Compact(values)
+}
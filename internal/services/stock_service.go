@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+)
+
+type StockService interface {
+	GetMovements(ctx context.Context, productID int, page int, pageSize int) ([]domain.StockMovement, int, error)
+}
+
+type StockServiceImpl struct {
+	stockRepository   repository.StockRepository
+	productRepository repository.ProductRepository
+}
+
+func NewStockService(stockRepository repository.StockRepository, productRepository repository.ProductRepository) StockService {
+	return &StockServiceImpl{
+		stockRepository:   stockRepository,
+		productRepository: productRepository,
+	}
+}
+
+func (s *StockServiceImpl) GetMovements(ctx context.Context, productID int, page int, pageSize int) ([]domain.StockMovement, int, error) {
+	if _, err := s.productRepository.GetProductByID(ctx, productID); err != nil {
+		return nil, 0, utils.ErrProductNotFound
+	}
+
+	movements, total, err := s.stockRepository.GetMovements(ctx, productID, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if movements == nil {
+		movements = []domain.StockMovement{}
+	}
+
+	return movements, total, nil
+}
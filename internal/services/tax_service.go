@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+	"math"
+)
+
+type TaxService interface {
+	GetTaxRates(ctx context.Context) ([]domain.TaxRate, error)
+	CreateTaxRate(ctx context.Context, rate *domain.TaxRate) (*domain.TaxRate, error)
+	UpdateTaxRate(ctx context.Context, id int, rate *domain.TaxRate) (*domain.TaxRate, error)
+	DeleteTaxRate(ctx context.Context, id int) error
+	GetStoreSettings(ctx context.Context) (*domain.StoreSettings, error)
+	UpdateStoreSettings(ctx context.Context, settings *domain.StoreSettings) (*domain.StoreSettings, error)
+	SetProductTax(ctx context.Context, productID int, tax *domain.ProductTax) error
+	SetCategoryTax(ctx context.Context, categoryID int, taxRateID *int) error
+}
+
+type TaxServiceImpl struct {
+	taxRepository      repository.TaxRepository
+	productRepository  repository.ProductRepository
+	categoryRepository repository.CategoryRepository
+}
+
+func NewTaxService(
+	taxRepository repository.TaxRepository,
+	productRepository repository.ProductRepository,
+	categoryRepository repository.CategoryRepository,
+) TaxService {
+	return &TaxServiceImpl{
+		taxRepository:      taxRepository,
+		productRepository:  productRepository,
+		categoryRepository: categoryRepository,
+	}
+}
+
+func (s *TaxServiceImpl) GetTaxRates(ctx context.Context) ([]domain.TaxRate, error) {
+	rates, err := s.taxRepository.GetTaxRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if rates == nil {
+		rates = []domain.TaxRate{}
+	}
+	return rates, nil
+}
+
+func (s *TaxServiceImpl) CreateTaxRate(ctx context.Context, rate *domain.TaxRate) (*domain.TaxRate, error) {
+	return s.taxRepository.CreateTaxRate(ctx, rate)
+}
+
+func (s *TaxServiceImpl) UpdateTaxRate(ctx context.Context, id int, rate *domain.TaxRate) (*domain.TaxRate, error) {
+	if _, err := s.taxRepository.GetTaxRateByID(ctx, id); err != nil {
+		return nil, utils.ErrTaxRateNotFound
+	}
+	return s.taxRepository.UpdateTaxRate(ctx, id, rate)
+}
+
+func (s *TaxServiceImpl) DeleteTaxRate(ctx context.Context, id int) error {
+	if _, err := s.taxRepository.GetTaxRateByID(ctx, id); err != nil {
+		return utils.ErrTaxRateNotFound
+	}
+	return s.taxRepository.DeleteTaxRate(ctx, id)
+}
+
+func (s *TaxServiceImpl) GetStoreSettings(ctx context.Context) (*domain.StoreSettings, error) {
+	return s.taxRepository.GetStoreSettings(ctx)
+}
+
+func (s *TaxServiceImpl) UpdateStoreSettings(ctx context.Context, settings *domain.StoreSettings) (*domain.StoreSettings, error) {
+	if err := s.ensureTaxRate(ctx, settings.DefaultTaxRateID); err != nil {
+		return nil, err
+	}
+	return s.taxRepository.UpdateStoreSettings(ctx, settings)
+}
+
+func (s *TaxServiceImpl) SetProductTax(ctx context.Context, productID int, tax *domain.ProductTax) error {
+	if _, err := s.productRepository.GetProductByID(ctx, productID); err != nil {
+		return utils.ErrProductNotFound
+	}
+	if err := s.ensureTaxRate(ctx, tax.TaxRateID); err != nil {
+		return err
+	}
+	return s.taxRepository.SetProductTax(ctx, productID, tax)
+}
+
+func (s *TaxServiceImpl) SetCategoryTax(ctx context.Context, categoryID int, taxRateID *int) error {
+	if _, err := s.categoryRepository.GetCategoryByID(ctx, categoryID); err != nil {
+		return utils.ErrCategoryNotFound
+	}
+	if err := s.ensureTaxRate(ctx, taxRateID); err != nil {
+		return err
+	}
+	return s.taxRepository.SetCategoryTax(ctx, categoryID, taxRateID)
+}
+
+func (s *TaxServiceImpl) ensureTaxRate(ctx context.Context, id *int) error {
+	if id == nil {
+		return nil
+	}
+	if _, err := s.taxRepository.GetTaxRateByID(ctx, *id); err != nil {
+		return utils.ErrTaxRateNotFound
+	}
+	return nil
+}
+
+// applyCharges adds service charge and tax to a transaction whose discounts
+// are final, so each line is taxed on what the customer actually pays.
+// cartShares holds the part of the promotion cart discounts each line took;
+// the voucher and manual discount, which come off the whole cart, are spread
+// over the lines in proportion to what is left of them. rates maps product
+// IDs to their effective tax rate; products missing from it are not taxed.
+func applyCharges(transaction *domain.Transaction, settings *domain.StoreSettings, rates map[int]domain.TaxRate, cartShares []int) {
+	items := transaction.Items
+
+	cartDiscount := 0
+	for _, discount := range transaction.Discounts {
+		if discount.ItemIndex < 0 {
+			cartDiscount += discount.Amount
+		}
+	}
+
+	amounts := make([]int, len(items))
+	shares := make([]int, len(items))
+	var eligible []int
+	total := 0
+	for i, item := range items {
+		amounts[i] = item.Subtotal - item.DiscountAmount - cartShares[i]
+		cartDiscount -= cartShares[i]
+		if amounts[i] > 0 {
+			eligible = append(eligible, i)
+			total += amounts[i]
+		}
+	}
+	if cartDiscount > 0 && total > 0 {
+		shareCartDiscount(shares, eligible, func(i int) int { return amounts[i] }, cartDiscount, total)
+	}
+
+	transaction.TaxInclusive = settings.TaxInclusive
+	for i := range items {
+		item := &items[i]
+		item.TaxRate = rates[item.ProductID].Rate
+
+		service, tax, added := lineCharges(amounts[i]-shares[i], item.TaxRate, settings)
+		item.ServiceCharge = service
+		item.TaxAmount = tax
+
+		transaction.ServiceCharge += service
+		transaction.TaxAmount += tax
+		transaction.TotalAmount += added
+	}
+}
+
+// lineCharges returns the service charge and tax on a line amount, and how
+// much they add to what the customer pays. With tax-inclusive prices the tax
+// already contained in the amount is reported but not added again.
+func lineCharges(amount int, taxRate float64, settings *domain.StoreSettings) (int, int, int) {
+	rate := taxRate / 100
+	serviceRate := settings.ServiceChargeRate / 100
+
+	base, included := amount, 0
+	if settings.TaxInclusive {
+		included = roundRupiah(float64(amount) * rate / (1 + rate))
+		base = amount - included
+	}
+
+	var service, tax int
+	if settings.ServiceChargeOrder == domain.ServiceChargeAfterTax {
+		tax = roundRupiah(float64(base) * rate)
+		if settings.TaxInclusive {
+			tax = included
+		}
+		service = roundRupiah(float64(base+tax) * serviceRate)
+	} else {
+		service = roundRupiah(float64(base) * serviceRate)
+		tax = roundRupiah(float64(base+service) * rate)
+		if settings.TaxInclusive {
+			tax = included + roundRupiah(float64(service)*rate)
+		}
+	}
+
+	return service, tax, service + tax - included
+}
+
+func roundRupiah(value float64) int {
+	return int(math.Round(value))
+}
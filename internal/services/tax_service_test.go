@@ -0,0 +1,174 @@
+package services
+
+import (
+	domain "kasir-api/internal/domains"
+	"testing"
+)
+
+func TestLineCharges(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int
+		taxRate  float64
+		settings domain.StoreSettings
+		service  int
+		tax      int
+		added    int
+	}{
+		{
+			name:     "exclusive tax",
+			amount:   10000,
+			taxRate:  10,
+			settings: domain.StoreSettings{},
+			tax:      1000,
+			added:    1000,
+		},
+		{
+			name:     "inclusive tax is reported but not added",
+			amount:   11100,
+			taxRate:  11,
+			settings: domain.StoreSettings{TaxInclusive: true},
+			tax:      1100,
+			added:    0,
+		},
+		{
+			name:     "tax-exempt line",
+			amount:   10000,
+			settings: domain.StoreSettings{ServiceChargeRate: 5},
+			service:  500,
+			added:    500,
+		},
+		{
+			name:     "service charge before tax is taxed",
+			amount:   10000,
+			taxRate:  10,
+			settings: domain.StoreSettings{ServiceChargeRate: 5, ServiceChargeOrder: domain.ServiceChargeBeforeTax},
+			service:  500,
+			tax:      1050,
+			added:    1550,
+		},
+		{
+			name:     "service charge after tax is charged on the tax",
+			amount:   10000,
+			taxRate:  10,
+			settings: domain.StoreSettings{ServiceChargeRate: 5, ServiceChargeOrder: domain.ServiceChargeAfterTax},
+			service:  550,
+			tax:      1000,
+			added:    1550,
+		},
+		{
+			name:     "inclusive tax with service charge before tax",
+			amount:   11000,
+			taxRate:  10,
+			settings: domain.StoreSettings{TaxInclusive: true, ServiceChargeRate: 5, ServiceChargeOrder: domain.ServiceChargeBeforeTax},
+			service:  500,
+			tax:      1050,
+			added:    550,
+		},
+		{
+			name:     "inclusive tax with service charge after tax",
+			amount:   11000,
+			taxRate:  10,
+			settings: domain.StoreSettings{TaxInclusive: true, ServiceChargeRate: 5, ServiceChargeOrder: domain.ServiceChargeAfterTax},
+			service:  550,
+			tax:      1000,
+			added:    550,
+		},
+	}
+
+	for _, test := range tests {
+		service, tax, added := lineCharges(test.amount, test.taxRate, &test.settings)
+		if service != test.service || tax != test.tax || added != test.added {
+			t.Errorf("%s: service %d, tax %d, added %d, want %d, %d and %d",
+				test.name, service, tax, added, test.service, test.tax, test.added)
+		}
+	}
+}
+
+func TestApplyCharges(t *testing.T) {
+	tests := []struct {
+		name       string
+		items      []domain.TransactionItem
+		discounts  []domain.TransactionDiscount
+		cartShares []int
+		settings   domain.StoreSettings
+		taxes      []int
+		total      int
+	}{
+		{
+			name: "tax-exempt products are not taxed",
+			items: []domain.TransactionItem{
+				{ProductID: 1, UnitPrice: 10000, Quantity: 1},
+				{ProductID: 2, UnitPrice: 10000, Quantity: 1},
+			},
+			cartShares: []int{0, 0},
+			taxes:      []int{1000, 0},
+			total:      21000,
+		},
+		{
+			name: "promotion cart discount stays on its eligible line",
+			items: []domain.TransactionItem{
+				{ProductID: 1, UnitPrice: 60000, Quantity: 1},
+				{ProductID: 3, UnitPrice: 40000, Quantity: 1},
+			},
+			discounts:  []domain.TransactionDiscount{{Amount: 10000, ItemIndex: -1}},
+			cartShares: []int{10000, 0},
+			taxes:      []int{5000, 4000},
+			total:      99000,
+		},
+		{
+			name: "voucher is spread over what is left of each line",
+			items: []domain.TransactionItem{
+				{ProductID: 1, UnitPrice: 60000, Quantity: 1},
+				{ProductID: 3, UnitPrice: 40000, Quantity: 1},
+			},
+			discounts: []domain.TransactionDiscount{
+				{Amount: 10000, ItemIndex: -1},
+				{Amount: 9000, ItemIndex: -1},
+			},
+			cartShares: []int{10000, 0},
+			taxes:      []int{4500, 3600},
+			total:      89100,
+		},
+		{
+			name: "last line takes the rounding remainder",
+			items: []domain.TransactionItem{
+				{ProductID: 3, UnitPrice: 10000, Quantity: 1},
+				{ProductID: 3, UnitPrice: 10000, Quantity: 1},
+				{ProductID: 3, UnitPrice: 10000, Quantity: 1},
+			},
+			discounts:  []domain.TransactionDiscount{{Amount: 100, ItemIndex: -1}},
+			cartShares: []int{0, 0, 0},
+			settings:   domain.StoreSettings{TaxInclusive: true},
+			// 9967, 9967 and 9966 left, each containing 10% tax
+			taxes: []int{906, 906, 906},
+			total: 29900,
+		},
+	}
+	rates := map[int]domain.TaxRate{1: {Rate: 10}, 3: {Rate: 10}}
+
+	for _, test := range tests {
+		transaction := transactionOf(test.items...)
+		transaction.Discounts = test.discounts
+		for _, discount := range test.discounts {
+			transaction.DiscountAmount += discount.Amount
+		}
+		transaction.TotalAmount = transaction.Subtotal - transaction.DiscountAmount
+
+		applyCharges(transaction, &test.settings, rates, test.cartShares)
+
+		sum := 0
+		for i, item := range transaction.Items {
+			sum += item.TaxAmount
+			if item.TaxAmount != test.taxes[i] {
+				t.Errorf("%s: line %d tax %d, want %d", test.name, i, item.TaxAmount, test.taxes[i])
+			}
+		}
+		if sum != transaction.TaxAmount {
+			t.Errorf("%s: line taxes add up to %d, transaction tax is %d", test.name, sum, transaction.TaxAmount)
+		}
+		if transaction.TotalAmount != test.total {
+			t.Errorf("%s: total %d, want %d", test.name, transaction.TotalAmount, test.total)
+		}
+	}
+}
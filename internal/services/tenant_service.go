@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+)
+
+type TenantService interface {
+	GetTenants(ctx context.Context) ([]domain.Tenant, error)
+	CreateTenant(ctx context.Context, tenant *domain.Tenant, owner *domain.User, password string) (*domain.Tenant, error)
+}
+
+type TenantServiceImpl struct {
+	tenantRepository repository.TenantRepository
+}
+
+func NewTenantService(tenantRepository repository.TenantRepository) TenantService {
+	return &TenantServiceImpl{tenantRepository: tenantRepository}
+}
+
+func (s *TenantServiceImpl) GetTenants(ctx context.Context) ([]domain.Tenant, error) {
+	if !inDefaultTenant(ctx) {
+		return nil, utils.ErrDefaultTenantOnly
+	}
+	tenants, err := s.tenantRepository.GetTenants(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if tenants == nil {
+		tenants = []domain.Tenant{}
+	}
+	return tenants, nil
+}
+
+// CreateTenant opens a new store with the default roles and settings, and
+// its owner as the first user. Any tenant's owner can grant tenant:manage
+// within their own tenant, so only the default tenant may use it.
+func (s *TenantServiceImpl) CreateTenant(ctx context.Context, tenant *domain.Tenant, owner *domain.User, password string) (*domain.Tenant, error) {
+	if !inDefaultTenant(ctx) {
+		return nil, utils.ErrDefaultTenantOnly
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	owner.PasswordHash = hash
+	owner.Active = true
+	owner.Roles = []string{domain.RoleOwner}
+	return s.tenantRepository.CreateTenant(ctx, tenant, owner)
+}
+
+func inDefaultTenant(ctx context.Context) bool {
+	tenantID, ok := utils.TenantIDFromContext(ctx)
+	return ok && tenantID == domain.DefaultTenantID
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+)
+
+type TerminalService interface {
+	GetTerminals(ctx context.Context) ([]domain.Terminal, error)
+	CreateTerminal(ctx context.Context, terminal *domain.Terminal) (*domain.Terminal, error)
+	UpdateTerminal(ctx context.Context, id int, terminal *domain.Terminal) (*domain.Terminal, error)
+}
+
+type TerminalServiceImpl struct {
+	terminalRepository repository.TerminalRepository
+}
+
+func NewTerminalService(terminalRepository repository.TerminalRepository) TerminalService {
+	return &TerminalServiceImpl{terminalRepository: terminalRepository}
+}
+
+func (s *TerminalServiceImpl) GetTerminals(ctx context.Context) ([]domain.Terminal, error) {
+	terminals, err := s.terminalRepository.GetTerminals(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if terminals == nil {
+		terminals = []domain.Terminal{}
+	}
+	return terminals, nil
+}
+
+// CreateTerminal registers a terminal and returns its key. Only a hash of
+// the key is stored, so this is the one time it can be read.
+func (s *TerminalServiceImpl) CreateTerminal(ctx context.Context, terminal *domain.Terminal) (*domain.Terminal, error) {
+	key, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	terminal, err = s.terminalRepository.CreateTerminal(ctx, terminal, hashToken(key))
+	if err != nil {
+		return nil, err
+	}
+	terminal.Key = key
+	return terminal, nil
+}
+
+func (s *TerminalServiceImpl) UpdateTerminal(ctx context.Context, id int, terminal *domain.Terminal) (*domain.Terminal, error) {
+	if _, err := s.terminalRepository.GetTerminalByID(ctx, id); err != nil {
+		return nil, utils.ErrTerminalNotFound
+	}
+	terminal.ID = id
+	return s.terminalRepository.UpdateTerminal(ctx, terminal)
+}
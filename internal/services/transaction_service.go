@@ -0,0 +1,583 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+	"maps"
+	"slices"
+	"time"
+)
+
+type TransactionService interface {
+	Checkout(ctx context.Context, checkout *domain.Checkout) (*domain.Transaction, error)
+	Quote(ctx context.Context, checkout *domain.Checkout) (*domain.PriceQuote, error)
+	GetTransactions(ctx context.Context, filter domain.TransactionFilter, page int, pageSize int) ([]domain.Transaction, int, error)
+	GetTransactionByID(ctx context.Context, id int) (*domain.Transaction, error)
+	RefundTransaction(ctx context.Context, id int) (*domain.Transaction, error)
+}
+
+type TransactionServiceImpl struct {
+	transactionRepository   repository.TransactionRepository
+	productRepository       repository.ProductRepository
+	variantRepository       repository.VariantRepository
+	modifierRepository      repository.ModifierRepository
+	unitRepository          repository.UnitRepository
+	promotionRepository     repository.PromotionRepository
+	voucherRepository       repository.VoucherRepository
+	taxRepository           repository.TaxRepository
+	priceListRepository     repository.PriceListRepository
+	priceScheduleRepository repository.PriceScheduleRepository
+	customerRepository      repository.CustomerRepository
+	loyaltyRepository       repository.LoyaltyRepository
+	giftCardRepository      repository.GiftCardRepository
+	approvalRepository      repository.ApprovalRepository
+}
+
+func NewTransactionService(
+	transactionRepository repository.TransactionRepository,
+	productRepository repository.ProductRepository,
+	variantRepository repository.VariantRepository,
+	modifierRepository repository.ModifierRepository,
+	unitRepository repository.UnitRepository,
+	promotionRepository repository.PromotionRepository,
+	voucherRepository repository.VoucherRepository,
+	taxRepository repository.TaxRepository,
+	priceListRepository repository.PriceListRepository,
+	priceScheduleRepository repository.PriceScheduleRepository,
+	customerRepository repository.CustomerRepository,
+	loyaltyRepository repository.LoyaltyRepository,
+	giftCardRepository repository.GiftCardRepository,
+	approvalRepository repository.ApprovalRepository,
+) TransactionService {
+	return &TransactionServiceImpl{
+		transactionRepository:   transactionRepository,
+		productRepository:       productRepository,
+		variantRepository:       variantRepository,
+		modifierRepository:      modifierRepository,
+		unitRepository:          unitRepository,
+		promotionRepository:     promotionRepository,
+		voucherRepository:       voucherRepository,
+		taxRepository:           taxRepository,
+		priceListRepository:     priceListRepository,
+		priceScheduleRepository: priceScheduleRepository,
+		customerRepository:      customerRepository,
+		loyaltyRepository:       loyaltyRepository,
+		giftCardRepository:      giftCardRepository,
+		approvalRepository:      approvalRepository,
+	}
+}
+
+func (s *TransactionServiceImpl) Checkout(ctx context.Context, checkout *domain.Checkout) (*domain.Transaction, error) {
+	transaction := &domain.Transaction{CustomerID: checkout.CustomerID}
+	if cashier := utils.AuthUserFromContext(ctx); cashier != nil {
+		transaction.CashierID = cashier.UserID()
+		transaction.TerminalID = cashier.TerminalID
+	}
+	categories := make(map[int]int, len(checkout.Items))
+	var approvals []domain.Approval
+
+	if len(checkout.Items) == 0 && len(checkout.GiftCards) == 0 {
+		return nil, utils.ErrEmptyCheckout
+	}
+
+	if checkout.CustomerID != nil {
+		if _, err := s.customerRepository.GetCustomerByID(ctx, *checkout.CustomerID); err != nil {
+			return nil, utils.ErrCustomerNotFound
+		}
+	}
+	priceListID, err := s.priceListRepository.GetCustomerPriceListID(ctx, checkout.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range checkout.Items {
+		line, product, err := s.priceItem(ctx, item, priceListID)
+		if err != nil {
+			return nil, err
+		}
+
+		ingredients, err := s.applyModifiers(ctx, item, line)
+		if err != nil {
+			return nil, err
+		}
+		if item.PriceOverride != nil && *item.PriceOverride != line.UnitPrice {
+			approvals = append(approvals, overridePrice(line, *item.PriceOverride))
+		}
+		line.Subtotal = line.UnitPrice * line.Quantity
+
+		transaction.Items = append(transaction.Items, *line)
+		transaction.Subtotal += line.Subtotal
+		categories[product.ID] = product.Category.ID
+
+		movements, err := s.saleMovements(ctx, product, line)
+		if err != nil {
+			return nil, err
+		}
+		transaction.StockMovements = append(transaction.StockMovements, movements...)
+		transaction.StockMovements = append(transaction.StockMovements, ingredients...)
+	}
+
+	promotions, err := s.promotionRepository.GetActivePromotions(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	cartShares := applyPromotions(transaction, promotions, categories)
+
+	if checkout.VoucherCode != "" {
+		if err := s.applyVoucher(ctx, transaction, checkout.VoucherCode); err != nil {
+			return nil, err
+		}
+	}
+
+	if checkout.ManualDiscount > 0 {
+		approval, err := s.applyManualDiscount(ctx, transaction, checkout.ManualDiscount)
+		if err != nil {
+			return nil, err
+		}
+		if approval != nil {
+			approvals = append(approvals, *approval)
+		}
+	}
+
+	settings, err := s.taxRepository.GetStoreSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rates, err := s.taxRepository.GetEffectiveTaxRates(ctx, slices.Collect(maps.Keys(categories)))
+	if err != nil {
+		return nil, err
+	}
+	applyCharges(transaction, settings, rates, cartShares)
+
+	if err := s.addGiftCardSales(ctx, transaction, checkout.GiftCards); err != nil {
+		return nil, err
+	}
+	if err := s.resolveGiftCardPayments(ctx, checkout.Payments); err != nil {
+		return nil, err
+	}
+
+	loyalty, err := s.loyaltyRepository.GetLoyaltySettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyPayments(transaction, checkout.Payments, loyalty); err != nil {
+		return nil, err
+	}
+	if loyalty.Enabled && transaction.CustomerID != nil {
+		if err := s.earnPoints(ctx, transaction, loyalty); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := authorizeApprovals(ctx, approvals); err != nil {
+		return nil, err
+	}
+	transaction.Approvals = approvals
+
+	return s.transactionRepository.CreateTransaction(ctx, transaction)
+}
+
+// overridePrice sells a line at the unit price the cashier entered, keeping
+// the price it would have sold at.
+func overridePrice(line *domain.TransactionItem, price int) domain.Approval {
+	original := line.UnitPrice
+	line.OriginalUnitPrice = &original
+	line.UnitPrice = price
+	return domain.Approval{
+		Action:  domain.ApprovalActionPriceOverride,
+		Details: fmt.Sprintf("%s: unit price %d overridden to %d", line.Name, original, price),
+	}
+}
+
+// applyManualDiscount takes a cashier's discount off what is left after
+// promotions and vouchers. A discount above the configured share of the
+// subtotal returns the approval it needs.
+func (s *TransactionServiceImpl) applyManualDiscount(ctx context.Context, transaction *domain.Transaction, amount int) (*domain.Approval, error) {
+	if amount > transaction.TotalAmount {
+		return nil, utils.ErrInvalidDiscount
+	}
+
+	transaction.Discounts = append(transaction.Discounts, domain.TransactionDiscount{
+		Name:      "Manual discount",
+		Amount:    amount,
+		ItemIndex: -1,
+	})
+	transaction.DiscountAmount += amount
+	transaction.TotalAmount -= amount
+
+	settings, err := s.approvalRepository.GetApprovalSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if float64(amount)*100 <= float64(transaction.Subtotal)*settings.DiscountThresholdPercent {
+		return nil, nil
+	}
+	return &domain.Approval{
+		Action:  domain.ApprovalActionDiscount,
+		Details: fmt.Sprintf("manual discount %d on subtotal %d", amount, transaction.Subtotal),
+	}, nil
+}
+
+// addGiftCardSales adds the gift cards sold or topped up to the total. They
+// are not discounted or taxed, being only prepaid value. A sale without a
+// code issues a new card with a random code.
+func (s *TransactionServiceImpl) addGiftCardSales(ctx context.Context, transaction *domain.Transaction, giftCards []domain.CheckoutGiftCard) error {
+	for _, giftCard := range giftCards {
+		sale := domain.GiftCardSale{Amount: giftCard.Amount}
+		if giftCard.Code == "" {
+			code, err := newGiftCardCode()
+			if err != nil {
+				return err
+			}
+			sale.Code = code
+		} else {
+			card, err := s.activeGiftCard(ctx, giftCard.Code)
+			if err != nil {
+				return err
+			}
+			sale.GiftCardID = &card.ID
+			sale.Code = card.Code
+		}
+
+		transaction.GiftCards = append(transaction.GiftCards, sale)
+		transaction.GiftCardAmount += sale.Amount
+		transaction.TotalAmount += sale.Amount
+	}
+	return nil
+}
+
+// resolveGiftCardPayments looks up the card of every gift card tender. The
+// balance is checked again when the card is debited.
+func (s *TransactionServiceImpl) resolveGiftCardPayments(ctx context.Context, payments []domain.CheckoutPayment) error {
+	for i := range payments {
+		payment := &payments[i]
+		if payment.Method != domain.PaymentMethodGiftCard {
+			continue
+		}
+		card, err := s.activeGiftCard(ctx, payment.Code)
+		if err != nil {
+			return err
+		}
+		if card.Balance < payment.Amount {
+			return utils.ErrInsufficientGiftCardBalance
+		}
+		payment.GiftCardID = &card.ID
+	}
+	return nil
+}
+
+func (s *TransactionServiceImpl) activeGiftCard(ctx context.Context, code string) (*domain.GiftCard, error) {
+	card, err := s.giftCardRepository.GetGiftCardByCode(ctx, normalizeGiftCardCode(code))
+	if err != nil {
+		return nil, utils.ErrGiftCardNotFound
+	}
+	if !card.Active {
+		return nil, utils.ErrGiftCardInactive
+	}
+	return card, nil
+}
+
+// earnPoints credits one point per SpendPerPoint Rupiah not paid with points,
+// plus the bonus points of every product sold per base unit. Gift cards
+// sold earn nothing; points are earned when they are spent.
+func (s *TransactionServiceImpl) earnPoints(ctx context.Context, transaction *domain.Transaction, loyalty *domain.LoyaltySettings) error {
+	productIDs := make([]int, len(transaction.Items))
+	for i, item := range transaction.Items {
+		productIDs[i] = item.ProductID
+	}
+	bonus, err := s.loyaltyRepository.GetBonusPoints(ctx, productIDs)
+	if err != nil {
+		return err
+	}
+
+	transaction.PointsEarned = pointsEarned(transaction, loyalty, bonus)
+	transaction.PointsExpireAt = loyalty.ExpiresAt(time.Now())
+	return nil
+}
+
+// pointsEarned returns the points a transaction earns given the bonus
+// points per base unit of its products.
+func pointsEarned(transaction *domain.Transaction, loyalty *domain.LoyaltySettings, bonus map[int]int) int {
+	paid := transaction.TotalAmount - transaction.GiftCardAmount - transaction.PointsRedeemed*loyalty.PointValue
+	points := max(0, paid) / loyalty.SpendPerPoint
+	for _, item := range transaction.Items {
+		points += bonus[item.ProductID] * item.BaseQuantity
+	}
+	return points
+}
+
+// applyVoucher discounts what is left after promotions. The voucher is only
+// claimed when the transaction is stored.
+func (s *TransactionServiceImpl) applyVoucher(ctx context.Context, transaction *domain.Transaction, code string) error {
+	voucher, amount, err := checkVoucher(ctx, s.voucherRepository, code, transaction.CustomerID, transaction.TotalAmount)
+	if err != nil {
+		return err
+	}
+
+	transaction.Discounts = append(transaction.Discounts, domain.TransactionDiscount{
+		VoucherID: &voucher.ID,
+		Name:      voucher.Name,
+		Amount:    amount,
+		ItemIndex: -1,
+	})
+	transaction.DiscountAmount += amount
+	transaction.TotalAmount -= amount
+	return nil
+}
+
+// Quote prices a cart the same way checkout does, including the customer's
+// price list and modifiers, without storing anything.
+func (s *TransactionServiceImpl) Quote(ctx context.Context, checkout *domain.Checkout) (*domain.PriceQuote, error) {
+	if len(checkout.Items) == 0 {
+		return nil, utils.ErrEmptyCheckout
+	}
+	if checkout.CustomerID != nil {
+		if _, err := s.customerRepository.GetCustomerByID(ctx, *checkout.CustomerID); err != nil {
+			return nil, utils.ErrCustomerNotFound
+		}
+	}
+
+	priceListID, err := s.priceListRepository.GetCustomerPriceListID(ctx, checkout.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &domain.PriceQuote{
+		CustomerID:  checkout.CustomerID,
+		PriceListID: priceListID,
+		Items:       []domain.TransactionItem{},
+	}
+	for _, item := range checkout.Items {
+		line, _, err := s.priceItem(ctx, item, priceListID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.applyModifiers(ctx, item, line); err != nil {
+			return nil, err
+		}
+		line.Subtotal = line.UnitPrice * line.Quantity
+
+		quote.Items = append(quote.Items, *line)
+		quote.Subtotal += line.Subtotal
+	}
+	return quote, nil
+}
+
+// priceItem resolves the name and unit price of a checkout line. Products
+// that have variants can only be sold through one of their variants, and a
+// scheduled product price counts as soon as it is due. The
+// quantity may be given in any unit of the product and is converted to the
+// base unit for stock. A price list entry, picked by the quantity in base
+// units, replaces the product or variant price, and a running price window
+// can lower it further. Both are prices per base unit and win over a
+// unit's own price: a wholesale customer buying a carton pays the wholesale
+// price per piece times the pieces in it.
+func (s *TransactionServiceImpl) priceItem(ctx context.Context, item domain.CheckoutItem, priceListID *int) (*domain.TransactionItem, *domain.Product, error) {
+	product, err := s.productRepository.GetProductByID(ctx, item.ProductID)
+	if err != nil {
+		return nil, nil, utils.ErrProductNotFound
+	}
+	now := time.Now()
+	due, err := s.priceScheduleRepository.GetDuePrices(ctx, []int{product.ID}, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(due) > 0 {
+		product.Price = due[0].Price
+	}
+
+	line := &domain.TransactionItem{
+		ProductID: product.ID,
+		Name:      product.Name,
+		Quantity:  item.Quantity,
+		UnitPrice: product.Price,
+	}
+
+	if item.VariantID != nil {
+		variant, err := s.variantRepository.GetVariantByID(ctx, *item.VariantID)
+		if err != nil || variant.ProductID != product.ID {
+			return nil, nil, utils.ErrVariantNotFound
+		}
+		line.VariantID = &variant.ID
+		line.Name = product.Name + " - " + variant.Name
+		line.UnitPrice = variant.Price
+	} else {
+		variants, err := s.variantRepository.GetVariantsByProductIDs(ctx, []int{product.ID})
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(variants) > 0 {
+			return nil, nil, utils.ErrVariantRequired
+		}
+	}
+
+	unit, err := resolveUnit(ctx, s.unitRepository, product, item.UnitID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if item.UnitID != nil {
+		line.UnitID = &unit.ID
+	}
+	line.UnitName = unit.Name
+	line.BaseQuantity = item.Quantity * unit.ConversionFactor
+
+	if priceListID != nil {
+		price, err := s.priceListRepository.ResolvePrice(ctx, *priceListID, product.ID, line.VariantID, line.BaseQuantity)
+		if err != nil {
+			return nil, nil, err
+		}
+		if price != nil {
+			line.UnitPrice = *price
+			line.PriceListID = priceListID
+		}
+	}
+
+	windows, err := s.priceScheduleRepository.GetActivePriceWindows(ctx, []int{product.ID}, []int{product.Category.ID})
+	if err != nil {
+		return nil, nil, err
+	}
+	basePrice := windowPrice(line.UnitPrice, product.ID, product.Category.ID, windows, now)
+	if line.PriceListID != nil || basePrice != line.UnitPrice {
+		line.UnitPrice = basePrice * unit.ConversionFactor
+	} else {
+		line.UnitPrice = unit.PriceFor(basePrice)
+	}
+
+	return line, product, nil
+}
+
+// applyModifiers checks the selected modifiers against the min/max rules of
+// every group linked to the product or its category, adds their price deltas
+// to the unit price and returns the ingredient stock they consume, per unit
+// of the line in its base unit.
+func (s *TransactionServiceImpl) applyModifiers(ctx context.Context, item domain.CheckoutItem, line *domain.TransactionItem) ([]domain.StockMovement, error) {
+	groups, err := s.modifierRepository.GetProductModifierGroups(ctx, item.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[int]bool, len(item.ModifierIDs))
+	for _, id := range item.ModifierIDs {
+		if selected[id] {
+			return nil, utils.ErrModifierSelection
+		}
+		selected[id] = true
+	}
+
+	var movements []domain.StockMovement
+	matched := 0
+	for _, group := range groups {
+		count := 0
+		for _, modifier := range group.Modifiers {
+			if !selected[modifier.ID] {
+				continue
+			}
+			count++
+			line.UnitPrice += modifier.PriceDelta
+			line.Modifiers = append(line.Modifiers, domain.TransactionItemModifier{
+				ModifierID: modifier.ID,
+				Name:       modifier.Name,
+				PriceDelta: modifier.PriceDelta,
+			})
+			if modifier.IngredientProductID != nil && modifier.IngredientQuantity > 0 {
+				movements = append(movements, domain.StockMovement{
+					ProductID: *modifier.IngredientProductID,
+					Quantity:  -line.BaseQuantity * modifier.IngredientQuantity,
+					Reason:    domain.StockReasonSale,
+				})
+			}
+		}
+		if count < group.MinSelect || (group.MaxSelect > 0 && count > group.MaxSelect) {
+			return nil, fmt.Errorf("%w: %s", utils.ErrModifierSelection, group.Name)
+		}
+		matched += count
+	}
+
+	if matched != len(selected) {
+		return nil, utils.ErrInvalidModifier
+	}
+	return movements, nil
+}
+
+// saleMovements returns the ledger entries for selling a line. Bundles and
+// recipes never hold stock themselves; their components are deducted instead.
+func (s *TransactionServiceImpl) saleMovements(ctx context.Context, product *domain.Product, line *domain.TransactionItem) ([]domain.StockMovement, error) {
+	if !product.IsComposite() {
+		return []domain.StockMovement{{
+			ProductID: line.ProductID,
+			VariantID: line.VariantID,
+			Quantity:  -line.BaseQuantity,
+			Reason:    domain.StockReasonSale,
+		}}, nil
+	}
+
+	components, err := s.productRepository.GetComponents(ctx, []int{product.ID})
+	if err != nil {
+		return nil, err
+	}
+	if len(components) == 0 {
+		return nil, fmt.Errorf("%w: %s has no components", utils.ErrInsufficientStock, product.Name)
+	}
+
+	movements := make([]domain.StockMovement, len(components))
+	for i, component := range components {
+		movements[i] = domain.StockMovement{
+			ProductID: component.ComponentID,
+			Quantity:  -line.BaseQuantity * component.Quantity,
+			Reason:    domain.StockReasonSale,
+		}
+	}
+	return movements, nil
+}
+
+func (s *TransactionServiceImpl) GetTransactions(ctx context.Context, filter domain.TransactionFilter, page int, pageSize int) ([]domain.Transaction, int, error) {
+	transactions, total, err := s.transactionRepository.GetTransactions(ctx, filter, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if transactions == nil {
+		transactions = []domain.Transaction{}
+	}
+
+	return transactions, total, nil
+}
+
+func (s *TransactionServiceImpl) GetTransactionByID(ctx context.Context, id int) (*domain.Transaction, error) {
+	transaction, err := s.transactionRepository.GetTransactionByID(ctx, id)
+	if err != nil {
+		return nil, utils.ErrTransactionNotFound
+	}
+	return transaction, nil
+}
+
+// RefundTransaction refunds a whole transaction. Points that were paid with
+// are given back as a new lot under the current expiry policy. Voiding
+// needs a supervisor unless the requester may void themselves.
+func (s *TransactionServiceImpl) RefundTransaction(ctx context.Context, id int) (*domain.Transaction, error) {
+	transaction, err := s.transactionRepository.GetTransactionByID(ctx, id)
+	if err != nil {
+		return nil, utils.ErrTransactionNotFound
+	}
+	if transaction.Status == domain.TransactionStatusRefunded {
+		return nil, utils.ErrTransactionRefunded
+	}
+
+	approvals := []domain.Approval{{
+		TransactionID: id,
+		Action:        domain.ApprovalActionVoid,
+		Details:       fmt.Sprintf("refund of transaction total %d", transaction.TotalAmount),
+	}}
+	if err := authorizeApprovals(ctx, approvals); err != nil {
+		return nil, err
+	}
+
+	loyalty, err := s.loyaltyRepository.GetLoyaltySettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.transactionRepository.RefundTransaction(ctx, id, loyalty.ExpiresAt(time.Now()), approvals)
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+)
+
+type UnitService interface {
+	GetUnits(ctx context.Context, productID int) ([]domain.ProductUnit, error)
+	CreateUnit(ctx context.Context, unit *domain.ProductUnit) (*domain.ProductUnit, error)
+	UpdateUnit(ctx context.Context, id int, unit *domain.ProductUnit) (*domain.ProductUnit, error)
+	DeleteUnit(ctx context.Context, id int) error
+}
+
+type UnitServiceImpl struct {
+	unitRepository    repository.UnitRepository
+	productRepository repository.ProductRepository
+}
+
+func NewUnitService(unitRepository repository.UnitRepository, productRepository repository.ProductRepository) UnitService {
+	return &UnitServiceImpl{
+		unitRepository:    unitRepository,
+		productRepository: productRepository,
+	}
+}
+
+func (s *UnitServiceImpl) GetUnits(ctx context.Context, productID int) ([]domain.ProductUnit, error) {
+	if _, err := s.productRepository.GetProductByID(ctx, productID); err != nil {
+		return nil, utils.ErrProductNotFound
+	}
+
+	units, err := s.unitRepository.GetUnitsByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if units == nil {
+		units = []domain.ProductUnit{}
+	}
+	return units, nil
+}
+
+func (s *UnitServiceImpl) CreateUnit(ctx context.Context, unit *domain.ProductUnit) (*domain.ProductUnit, error) {
+	product, err := s.productRepository.GetProductByID(ctx, unit.ProductID)
+	if err != nil {
+		return nil, utils.ErrProductNotFound
+	}
+	if unit.Name == product.BaseUnit {
+		return nil, utils.ErrDuplicateUnit
+	}
+	return s.unitRepository.CreateUnit(ctx, unit)
+}
+
+func (s *UnitServiceImpl) UpdateUnit(ctx context.Context, id int, unit *domain.ProductUnit) (*domain.ProductUnit, error) {
+	existing, err := s.unitRepository.GetUnitByID(ctx, id)
+	if err != nil {
+		return nil, utils.ErrUnitNotFound
+	}
+
+	unit.ProductID = existing.ProductID
+	product, err := s.productRepository.GetProductByID(ctx, unit.ProductID)
+	if err != nil {
+		return nil, utils.ErrProductNotFound
+	}
+	if unit.Name == product.BaseUnit {
+		return nil, utils.ErrDuplicateUnit
+	}
+	return s.unitRepository.UpdateUnit(ctx, id, unit)
+}
+
+func (s *UnitServiceImpl) DeleteUnit(ctx context.Context, id int) error {
+	if _, err := s.unitRepository.GetUnitByID(ctx, id); err != nil {
+		return utils.ErrUnitNotFound
+	}
+	return s.unitRepository.DeleteUnit(ctx, id)
+}
+
+// resolveUnit returns the unit a line is sold or bought in. A nil unitID
+// means the product's base unit, which always has a conversion factor of 1.
+func resolveUnit(ctx context.Context, unitRepository repository.UnitRepository, product *domain.Product, unitID *int) (*domain.ProductUnit, error) {
+	if unitID == nil {
+		return &domain.ProductUnit{ProductID: product.ID, Name: product.BaseUnit, ConversionFactor: 1}, nil
+	}
+
+	unit, err := unitRepository.GetUnitByID(ctx, *unitID)
+	if err != nil || unit.ProductID != product.ID {
+		return nil, utils.ErrUnitNotFound
+	}
+	return unit, nil
+}
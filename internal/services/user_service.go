@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+)
+
+type UserService interface {
+	GetUsers(ctx context.Context, page int, pageSize int) ([]domain.User, int, error)
+	GetUserByID(ctx context.Context, id int) (*domain.User, error)
+	CreateUser(ctx context.Context, user *domain.User, password string) (*domain.User, error)
+	RevokeSessions(ctx context.Context, id int) error
+	SetPIN(ctx context.Context, id int, pin string) error
+}
+
+type UserServiceImpl struct {
+	userRepository    repository.UserRepository
+	sessionRepository repository.SessionRepository
+}
+
+func NewUserService(userRepository repository.UserRepository, sessionRepository repository.SessionRepository) UserService {
+	return &UserServiceImpl{
+		userRepository:    userRepository,
+		sessionRepository: sessionRepository,
+	}
+}
+
+func (s *UserServiceImpl) GetUsers(ctx context.Context, page int, pageSize int) ([]domain.User, int, error) {
+	users, total, err := s.userRepository.GetUsers(ctx, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	if users == nil {
+		users = []domain.User{}
+	}
+	return users, total, nil
+}
+
+func (s *UserServiceImpl) GetUserByID(ctx context.Context, id int) (*domain.User, error) {
+	user, err := s.userRepository.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, utils.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (s *UserServiceImpl) CreateUser(ctx context.Context, user *domain.User, password string) (*domain.User, error) {
+	hash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	user.PasswordHash = hash
+	user.Active = true
+	user.Roles = uniqueStrings(user.Roles)
+	return s.userRepository.CreateUser(ctx, user)
+}
+
+// RevokeSessions logs a user out everywhere, for example after a device
+// was lost.
+func (s *UserServiceImpl) RevokeSessions(ctx context.Context, id int) error {
+	if _, err := s.userRepository.GetUserByID(ctx, id); err != nil {
+		return utils.ErrUserNotFound
+	}
+	return s.sessionRepository.RevokeUserSessions(ctx, id)
+}
+
+// SetPIN sets the quick-switch PIN of a user, which also lifts a lockout.
+func (s *UserServiceImpl) SetPIN(ctx context.Context, id int, pin string) error {
+	if _, err := s.userRepository.GetUserByID(ctx, id); err != nil {
+		return utils.ErrUserNotFound
+	}
+	hash, err := hashPassword(pin)
+	if err != nil {
+		return err
+	}
+	return s.userRepository.SetPIN(ctx, id, hash)
+}
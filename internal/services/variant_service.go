@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+	"slices"
+)
+
+type VariantService interface {
+	GetVariants(ctx context.Context, productID int) ([]domain.VariantOption, []domain.ProductVariant, error)
+	ReplaceVariantOptions(ctx context.Context, productID int, options []domain.VariantOption) ([]domain.VariantOption, error)
+	CreateVariant(ctx context.Context, variant *domain.ProductVariant) (*domain.ProductVariant, error)
+	UpdateVariant(ctx context.Context, id int, variant *domain.ProductVariant) (*domain.ProductVariant, error)
+	DeleteVariant(ctx context.Context, id int) error
+}
+
+type VariantServiceImpl struct {
+	variantRepository repository.VariantRepository
+	productRepository repository.ProductRepository
+}
+
+func NewVariantService(variantRepository repository.VariantRepository, productRepository repository.ProductRepository) VariantService {
+	return &VariantServiceImpl{
+		variantRepository: variantRepository,
+		productRepository: productRepository,
+	}
+}
+
+func (s *VariantServiceImpl) GetVariants(ctx context.Context, productID int) ([]domain.VariantOption, []domain.ProductVariant, error) {
+	if _, err := s.productRepository.GetProductByID(ctx, productID); err != nil {
+		return nil, nil, utils.ErrProductNotFound
+	}
+
+	options, err := s.variantRepository.GetVariantOptions(ctx, productID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	variants, err := s.variantRepository.GetVariantsByProductIDs(ctx, []int{productID})
+	if err != nil {
+		return nil, nil, err
+	}
+	if variants == nil {
+		variants = []domain.ProductVariant{}
+	}
+
+	return options, variants, nil
+}
+
+func (s *VariantServiceImpl) ReplaceVariantOptions(ctx context.Context, productID int, options []domain.VariantOption) ([]domain.VariantOption, error) {
+	if _, err := s.productRepository.GetProductByID(ctx, productID); err != nil {
+		return nil, utils.ErrProductNotFound
+	}
+	return s.variantRepository.ReplaceVariantOptions(ctx, productID, options)
+}
+
+func (s *VariantServiceImpl) CreateVariant(ctx context.Context, variant *domain.ProductVariant) (*domain.ProductVariant, error) {
+	product, err := s.productRepository.GetProductByID(ctx, variant.ProductID)
+	if err != nil {
+		return nil, utils.ErrProductNotFound
+	}
+	if product.IsComposite() {
+		return nil, utils.ErrCompositeVariants
+	}
+	if err := s.validateOptions(ctx, variant); err != nil {
+		return nil, err
+	}
+	return s.variantRepository.CreateVariant(ctx, variant)
+}
+
+func (s *VariantServiceImpl) UpdateVariant(ctx context.Context, id int, variant *domain.ProductVariant) (*domain.ProductVariant, error) {
+	existing, err := s.variantRepository.GetVariantByID(ctx, id)
+	if err != nil {
+		return nil, utils.ErrVariantNotFound
+	}
+
+	variant.ProductID = existing.ProductID
+	if err := s.validateOptions(ctx, variant); err != nil {
+		return nil, err
+	}
+	return s.variantRepository.UpdateVariant(ctx, id, variant)
+}
+
+func (s *VariantServiceImpl) DeleteVariant(ctx context.Context, id int) error {
+	if _, err := s.variantRepository.GetVariantByID(ctx, id); err != nil {
+		return utils.ErrVariantNotFound
+	}
+	return s.variantRepository.DeleteVariant(ctx, id)
+}
+
+// validateOptions makes sure a variant picks exactly one defined value for
+// every option of its product, e.g. {"size": "L", "color": "black"}.
+func (s *VariantServiceImpl) validateOptions(ctx context.Context, variant *domain.ProductVariant) error {
+	options, err := s.variantRepository.GetVariantOptions(ctx, variant.ProductID)
+	if err != nil {
+		return err
+	}
+
+	if len(variant.Options) != len(options) {
+		return utils.ErrInvalidVariant
+	}
+	for _, option := range options {
+		value, ok := variant.Options[option.Name]
+		if !ok || !slices.Contains(option.Values, value) {
+			return utils.ErrInvalidVariant
+		}
+	}
+	return nil
+}
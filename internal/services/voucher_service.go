@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+	"strings"
+	"time"
+)
+
+type VoucherService interface {
+	GetVouchers(ctx context.Context, page int, pageSize int) ([]domain.Voucher, int, error)
+	GetVoucherByID(ctx context.Context, id int) (*domain.Voucher, error)
+	CreateVoucher(ctx context.Context, voucher *domain.Voucher) (*domain.Voucher, error)
+	UpdateVoucher(ctx context.Context, id int, voucher *domain.Voucher) (*domain.Voucher, error)
+	DeleteVoucher(ctx context.Context, id int) error
+	ValidateVoucher(ctx context.Context, code string, customerID *int, amount int) (*domain.VoucherValidation, error)
+}
+
+type VoucherServiceImpl struct {
+	voucherRepository repository.VoucherRepository
+}
+
+func NewVoucherService(voucherRepository repository.VoucherRepository) VoucherService {
+	return &VoucherServiceImpl{voucherRepository: voucherRepository}
+}
+
+func (s *VoucherServiceImpl) GetVouchers(ctx context.Context, page int, pageSize int) ([]domain.Voucher, int, error) {
+	vouchers, total, err := s.voucherRepository.GetVouchers(ctx, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if vouchers == nil {
+		vouchers = []domain.Voucher{}
+	}
+
+	return vouchers, total, nil
+}
+
+func (s *VoucherServiceImpl) GetVoucherByID(ctx context.Context, id int) (*domain.Voucher, error) {
+	voucher, err := s.voucherRepository.GetVoucherByID(ctx, id)
+	if err != nil {
+		return nil, utils.ErrVoucherNotFound
+	}
+	return voucher, nil
+}
+
+func (s *VoucherServiceImpl) CreateVoucher(ctx context.Context, voucher *domain.Voucher) (*domain.Voucher, error) {
+	if err := validateVoucher(voucher); err != nil {
+		return nil, err
+	}
+	return s.voucherRepository.CreateVoucher(ctx, voucher)
+}
+
+func (s *VoucherServiceImpl) UpdateVoucher(ctx context.Context, id int, voucher *domain.Voucher) (*domain.Voucher, error) {
+	if _, err := s.voucherRepository.GetVoucherByID(ctx, id); err != nil {
+		return nil, utils.ErrVoucherNotFound
+	}
+	if err := validateVoucher(voucher); err != nil {
+		return nil, err
+	}
+	return s.voucherRepository.UpdateVoucher(ctx, id, voucher)
+}
+
+func (s *VoucherServiceImpl) DeleteVoucher(ctx context.Context, id int) error {
+	if _, err := s.voucherRepository.GetVoucherByID(ctx, id); err != nil {
+		return utils.ErrVoucherNotFound
+	}
+	return s.voucherRepository.DeleteVoucher(ctx, id)
+}
+
+// ValidateVoucher tells the POS whether a code can be used on a cart of the
+// given amount and how much it would take off. The usage limits are checked
+// again atomically when the voucher is redeemed at checkout.
+func (s *VoucherServiceImpl) ValidateVoucher(ctx context.Context, code string, customerID *int, amount int) (*domain.VoucherValidation, error) {
+	voucher, discount, err := checkVoucher(ctx, s.voucherRepository, code, customerID, amount)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.VoucherValidation{Voucher: voucher, Amount: amount, Discount: discount}, nil
+}
+
+func normalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func validateVoucher(voucher *domain.Voucher) error {
+	voucher.Code = normalizeVoucherCode(voucher.Code)
+	if voucher.Code == "" {
+		return utils.ErrInvalidVoucher
+	}
+	if voucher.DiscountType == domain.PromotionTypePercentage && voucher.Value > 100 {
+		return utils.ErrInvalidVoucher
+	}
+	if voucher.StartsAt != nil && voucher.EndsAt != nil && !voucher.EndsAt.After(*voucher.StartsAt) {
+		return utils.ErrInvalidVoucher
+	}
+	return nil
+}
+
+// checkVoucher looks up a code and returns the discount it gives on amount,
+// or the reason it cannot be used right now.
+func checkVoucher(
+	ctx context.Context,
+	voucherRepository repository.VoucherRepository,
+	code string,
+	customerID *int,
+	amount int,
+) (*domain.Voucher, int, error) {
+	voucher, err := voucherRepository.GetVoucherByCode(ctx, normalizeVoucherCode(code))
+	if err != nil {
+		return nil, 0, utils.ErrVoucherNotFound
+	}
+
+	now := time.Now()
+	if !voucher.Active ||
+		(voucher.StartsAt != nil && now.Before(*voucher.StartsAt)) ||
+		(voucher.EndsAt != nil && !now.Before(*voucher.EndsAt)) {
+		return nil, 0, utils.ErrVoucherInactive
+	}
+	if amount < voucher.MinPurchase {
+		return nil, 0, utils.ErrVoucherMinPurchase
+	}
+	if voucher.UsageLimit > 0 && voucher.UsedCount >= voucher.UsageLimit {
+		return nil, 0, utils.ErrVoucherLimitReached
+	}
+
+	if voucher.PerCustomerLimit > 0 {
+		if customerID == nil {
+			return nil, 0, utils.ErrVoucherCustomerRequired
+		}
+		used, err := voucherRepository.CountCustomerRedemptions(ctx, voucher.ID, *customerID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if used >= voucher.PerCustomerLimit {
+			return nil, 0, utils.ErrVoucherLimitReached
+		}
+	}
+
+	return voucher, voucher.Discount(amount), nil
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	domain "kasir-api/internal/domains"
+	repository "kasir-api/internal/repositories"
+	"kasir-api/internal/utils"
+	"testing"
+	"time"
+)
+
+// voucherRepositoryStub serves one voucher and a fixed number of
+// redemptions by any customer.
+type voucherRepositoryStub struct {
+	repository.VoucherRepository
+	voucher     domain.Voucher
+	redemptions int
+}
+
+func (r *voucherRepositoryStub) GetVoucherByCode(ctx context.Context, code string) (*domain.Voucher, error) {
+	if code != r.voucher.Code {
+		return nil, sql.ErrNoRows
+	}
+	voucher := r.voucher
+	return &voucher, nil
+}
+
+func (r *voucherRepositoryStub) CountCustomerRedemptions(ctx context.Context, voucherID int, customerID int) (int, error) {
+	return r.redemptions, nil
+}
+
+func TestCheckVoucher(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	customerID := 7
+	percent := func(v domain.Voucher) domain.Voucher {
+		v.Code, v.Active, v.DiscountType, v.Value = "HEMAT", true, domain.PromotionTypePercentage, 10
+		return v
+	}
+
+	tests := []struct {
+		name        string
+		voucher     domain.Voucher
+		redemptions int
+		code        string
+		customerID  *int
+		amount      int
+		discount    int
+		err         error
+	}{
+		{name: "percentage", voucher: percent(domain.Voucher{}), amount: 50000, discount: 5000},
+		{name: "code is normalized", voucher: percent(domain.Voucher{}), code: " hemat ", amount: 50000, discount: 5000},
+		{name: "unknown code", voucher: percent(domain.Voucher{}), code: "LAIN", amount: 50000, err: utils.ErrVoucherNotFound},
+		{name: "percentage capped", voucher: percent(domain.Voucher{MaxDiscount: 3000}), amount: 50000, discount: 3000},
+		{name: "percentage below the cap", voucher: percent(domain.Voucher{MaxDiscount: 3000}), amount: 20000, discount: 2000},
+		{
+			name:    "fixed never exceeds the amount",
+			voucher: domain.Voucher{Code: "HEMAT", Active: true, DiscountType: domain.PromotionTypeFixed, Value: 25000},
+			amount:  20000, discount: 20000,
+		},
+		{name: "inactive", voucher: domain.Voucher{Code: "HEMAT", DiscountType: domain.PromotionTypeFixed, Value: 1000}, amount: 50000, err: utils.ErrVoucherInactive},
+		{name: "not started yet", voucher: percent(domain.Voucher{StartsAt: &future}), amount: 50000, err: utils.ErrVoucherInactive},
+		{name: "ended", voucher: percent(domain.Voucher{EndsAt: &past}), amount: 50000, err: utils.ErrVoucherInactive},
+		{name: "within its window", voucher: percent(domain.Voucher{StartsAt: &past, EndsAt: &future}), amount: 50000, discount: 5000},
+		{name: "below the minimum spend", voucher: percent(domain.Voucher{MinPurchase: 100000}), amount: 99999, err: utils.ErrVoucherMinPurchase},
+		{name: "at the minimum spend", voucher: percent(domain.Voucher{MinPurchase: 100000}), amount: 100000, discount: 10000},
+		{name: "usage limit reached", voucher: percent(domain.Voucher{UsageLimit: 5, UsedCount: 5}), amount: 50000, err: utils.ErrVoucherLimitReached},
+		{name: "usage limit not reached", voucher: percent(domain.Voucher{UsageLimit: 5, UsedCount: 4}), amount: 50000, discount: 5000},
+		{name: "per customer limit needs a customer", voucher: percent(domain.Voucher{PerCustomerLimit: 1}), amount: 50000, err: utils.ErrVoucherCustomerRequired},
+		{
+			name:    "per customer limit reached",
+			voucher: percent(domain.Voucher{PerCustomerLimit: 1}), redemptions: 1, customerID: &customerID,
+			amount: 50000, err: utils.ErrVoucherLimitReached,
+		},
+		{
+			name:    "per customer limit not reached",
+			voucher: percent(domain.Voucher{PerCustomerLimit: 2}), redemptions: 1, customerID: &customerID,
+			amount: 50000, discount: 5000,
+		},
+	}
+
+	for _, test := range tests {
+		code := test.code
+		if code == "" {
+			code = test.voucher.Code
+		}
+		stub := &voucherRepositoryStub{voucher: test.voucher, redemptions: test.redemptions}
+
+		_, discount, err := checkVoucher(context.Background(), stub, code, test.customerID, test.amount)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: error %v, want %v", test.name, err, test.err)
+			continue
+		}
+		if discount != test.discount {
+			t.Errorf("%s: discount %d, want %d", test.name, discount, test.discount)
+		}
+	}
+}
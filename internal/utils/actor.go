@@ -0,0 +1,19 @@
+package utils
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor returns a context carrying the name of whoever made the request,
+// used to attribute changes such as price updates. WithAuthUser sets it to
+// the authenticated user or API key; background jobs set it to name
+// themselves.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor stored by WithActor, or "" if none.
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Headers a supervisor approves an operation with: either an approval grant
+// they issued, or their username and PIN entered on the requesting terminal.
+const (
+	ApprovalTokenHeader = "X-Approval-Token"
+	ApproverHeader      = "X-Approver"
+	ApproverPINHeader   = "X-Approver-PIN"
+)
+
+// ApprovalRequiredError lists the actions of an operation that the user may
+// not do alone. Retrying with approver credentials completes it.
+type ApprovalRequiredError struct {
+	Actions []string
+}
+
+func (e *ApprovalRequiredError) Error() string {
+	return ErrApprovalRequired.Error() + ": " + strings.Join(e.Actions, ", ")
+}
+
+func (e *ApprovalRequiredError) Unwrap() error {
+	return ErrApprovalRequired
+}
+
+// Approver is the supervisor who approved the operations of a request, and
+// how they proved it. Approving with a grant limits them to the grant's
+// actions on its transaction, or on a new sale when TransactionID is nil.
+type Approver struct {
+	AuthUser
+	Method        string
+	GrantID       *int
+	Actions       []string
+	TransactionID *int
+}
+
+// Covers reports whether the approval extends to the action on the
+// transaction, 0 being a sale that is not saved yet.
+func (a *Approver) Covers(action string, transactionID int) bool {
+	if a.GrantID == nil {
+		return true
+	}
+	if !slices.Contains(a.Actions, action) {
+		return false
+	}
+	if a.TransactionID == nil {
+		return transactionID == 0
+	}
+	return *a.TransactionID == transactionID
+}
+
+type approverKey struct{}
+
+func WithApprover(ctx context.Context, approver *Approver) context.Context {
+	return context.WithValue(ctx, approverKey{}, approver)
+}
+
+// ApproverFromContext returns the approver stored by WithApprover, or nil
+// when the request carried no approval.
+func ApproverFromContext(ctx context.Context) *Approver {
+	approver, _ := ctx.Value(approverKey{}).(*Approver)
+	return approver
+}
+
+// ApprovalRequiredResponse answers 403 with the actions that need approval,
+// so the register can ask a supervisor and send the request again.
+func ApprovalRequiredResponse(w http.ResponseWriter, err *ApprovalRequiredError) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+
+	return json.NewEncoder(w).Encode(Response{
+		Status:  false,
+		Message: ErrApprovalRequired.Error(),
+		Data: map[string]interface{}{
+			"approval_required": true,
+			"actions":           err.Actions,
+		},
+	})
+}
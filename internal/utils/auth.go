@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"context"
+	"slices"
+)
+
+// TerminalKeyHeader carries the secret key of a registered terminal. PIN
+// login and every request of a PIN session must send it.
+const TerminalKeyHeader = "X-Terminal-Key"
+
+// AuthUser is the authenticated user of a request, taken from its access
+// token, or the API key the request was made with.
+type AuthUser struct {
+	ID          int
+	TenantID    int
+	Username    string
+	SessionID   string
+	Permissions []string
+	// TerminalID is the terminal a PIN session was opened on.
+	TerminalID *int
+	// APIKeyID is set instead of ID when the request used an API key.
+	APIKeyID *int
+}
+
+// Can reports whether any of the user's roles, or the API key, grants the
+// permission.
+func (u *AuthUser) Can(permission string) bool {
+	return slices.Contains(u.Permissions, permission)
+}
+
+// UserID returns the ID of the logged in user, or nil for an API key.
+func (u *AuthUser) UserID() *int {
+	if u.APIKeyID != nil {
+		return nil
+	}
+	return &u.ID
+}
+
+type authUserKey struct{}
+
+// WithAuthUser returns a context carrying the authenticated user. The
+// username also becomes the actor that changes are attributed to, and the
+// request only sees the data of the user's tenant.
+func WithAuthUser(ctx context.Context, user *AuthUser) context.Context {
+	ctx = context.WithValue(ctx, authUserKey{}, user)
+	ctx = WithTenantID(ctx, user.TenantID)
+	return WithActor(ctx, user.Username)
+}
+
+// AuthUserFromContext returns the user stored by WithAuthUser, or nil for
+// unauthenticated requests.
+func AuthUserFromContext(ctx context.Context) *AuthUser {
+	user, _ := ctx.Value(authUserKey{}).(*AuthUser)
+	return user
+}
@@ -3,8 +3,119 @@ package utils
 import "errors"
 
 var (
-	ErrEmptyDatabaseURL = errors.New("DATABASE_URL is not set")
+	ErrEmptyDatabaseURL  = errors.New("DATABASE_URL is not set")
+	ErrDatabaseBypassRLS = errors.New("the database role bypasses row level security; connect as a role that is not a superuser, has no BYPASSRLS and did not run the migrations")
 
 	ErrProductNotFound  = errors.New("product not found")
 	ErrCategoryNotFound = errors.New("category not found")
+
+	ErrNotComposite      = errors.New("product is not a bundle or recipe")
+	ErrInvalidComponent  = errors.New("components must be existing standard products")
+	ErrCompositeVariants = errors.New("bundle and recipe products cannot have variants")
+	ErrNestedComposite   = errors.New("a component of a bundle or recipe, or a modifier ingredient, cannot be a bundle or recipe itself")
+
+	ErrModifierGroupNotFound = errors.New("modifier group not found")
+	ErrInvalidModifier       = errors.New("modifier is not available for this product")
+	ErrModifierSelection     = errors.New("modifier selection does not satisfy group rules")
+	ErrInvalidModifierGroup  = errors.New("max_select must be 0 or greater than or equal to min_select")
+	ErrInvalidIngredient     = errors.New("modifier ingredients must be standard products, not bundles or recipes")
+
+	ErrUnitNotFound     = errors.New("unit not found")
+	ErrDuplicateUnit    = errors.New("unit name or barcode already exists")
+	ErrCompositeStock   = errors.New("bundle and recipe products do not hold stock")
+	ErrPurchaseNotFound = errors.New("purchase not found")
+
+	ErrPromotionNotFound = errors.New("promotion not found")
+	ErrInvalidPromotion  = errors.New("invalid promotion rule")
+
+	ErrTaxRateNotFound = errors.New("tax rate not found")
+
+	ErrUnauthorized        = errors.New("authentication required")
+	ErrInvalidCredentials  = errors.New("invalid username or password")
+	ErrInvalidToken        = errors.New("invalid or expired token")
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrDuplicateUser       = errors.New("username already exists")
+	ErrForbidden           = errors.New("you do not have permission to do this")
+	ErrRoleNotFound        = errors.New("role not found")
+	ErrDuplicateRole       = errors.New("role name already exists")
+	ErrUnknownPermission   = errors.New("unknown permission")
+	ErrRoleInUse           = errors.New("role is still assigned to users")
+	ErrInvalidPIN          = errors.New("invalid username or PIN")
+	ErrPINLocked           = errors.New("PIN is locked after too many failed attempts, try again later")
+	ErrInvalidTerminal     = errors.New("PIN login needs a registered, active terminal")
+	ErrTerminalNotFound    = errors.New("terminal not found")
+	ErrDuplicateTerminal   = errors.New("terminal name already exists")
+	ErrApprovalRequired    = errors.New("manager approval required")
+	ErrInvalidApprover     = errors.New("invalid approver credentials")
+	ErrApprovalGrantUsed   = errors.New("approval grant has expired or was already used")
+	ErrApprovalGrantScope  = errors.New("approval grant can only cover actions you may approve")
+	ErrUserLoginRequired   = errors.New("this endpoint needs a user login, not an API key")
+	ErrAPIKeyNotFound      = errors.New("api key not found")
+	ErrDuplicateAPIKey     = errors.New("api key name already exists")
+	ErrAPIKeyRevoked       = errors.New("api key has been revoked")
+	ErrAPIKeyScope         = errors.New("api key cannot be granted a permission you do not have")
+	ErrInvalidAPIKeyExpiry = errors.New("api key expiry must be in the future")
+	ErrDuplicateTenant     = errors.New("tenant name already exists")
+	ErrDefaultTenantOnly   = errors.New("tenants can only be managed from the default tenant")
+
+	ErrCustomerNotFound  = errors.New("customer not found")
+	ErrDuplicateCustomer = errors.New("customer phone already exists")
+	ErrCustomerHasCredit = errors.New("customer still has an outstanding credit balance")
+
+	ErrCreditCustomerRequired = errors.New("customer_id is required to pay on account")
+	ErrCreditLimitExceeded    = errors.New("customer credit limit exceeded")
+	ErrReceivableNotFound     = errors.New("transaction has no outstanding balance for this customer")
+	ErrRepaymentTooLarge      = errors.New("repayment exceeds the outstanding balance")
+
+	ErrLoyaltyDisabled         = errors.New("loyalty program is not enabled")
+	ErrLoyaltyCustomerRequired = errors.New("customer_id is required to redeem points")
+	ErrInsufficientPoints      = errors.New("customer does not have enough loyalty points")
+	ErrMinRedeemPoints         = errors.New("points redeemed are below the minimum")
+	ErrInvalidPayment          = errors.New("invalid payment")
+	ErrInsufficientPayment     = errors.New("payments do not cover the transaction total")
+
+	ErrGiftCardNotFound            = errors.New("gift card not found")
+	ErrGiftCardInactive            = errors.New("gift card is not active")
+	ErrInsufficientGiftCardBalance = errors.New("gift card balance is not enough")
+	ErrGiftCardSpent               = errors.New("gift card value from this transaction has already been spent")
+	ErrEmptyCheckout               = errors.New("checkout needs at least one item or gift card")
+	ErrInvalidDiscount             = errors.New("manual discount exceeds the transaction total")
+
+	ErrPriceListNotFound  = errors.New("price list not found")
+	ErrDuplicatePriceList = errors.New("price list name or quantity break already exists")
+
+	ErrScheduledPriceNotFound = errors.New("scheduled price not found or already applied")
+	ErrPriceWindowNotFound    = errors.New("price window not found")
+	ErrInvalidPriceWindow     = errors.New("invalid price window")
+
+	ErrVoucherNotFound         = errors.New("voucher not found")
+	ErrDuplicateVoucher        = errors.New("voucher code already exists")
+	ErrInvalidVoucher          = errors.New("invalid voucher rule")
+	ErrVoucherInactive         = errors.New("voucher is not active or outside its validity period")
+	ErrVoucherMinPurchase      = errors.New("purchase amount is below the voucher minimum")
+	ErrVoucherLimitReached     = errors.New("voucher usage limit reached")
+	ErrVoucherCustomerRequired = errors.New("customer_id is required for this voucher")
+
+	ErrProductInUse        = errors.New("product has been sold or purchased, or is a component of a bundle or recipe, and cannot be deleted")
+	ErrVariantInUse        = errors.New("variant has been sold and cannot be deleted")
+	ErrVariantNotFound     = errors.New("variant not found")
+	ErrVariantRequired     = errors.New("product has variants, variant_id is required")
+	ErrInvalidVariant      = errors.New("variant options do not match the product variant options")
+	ErrDuplicateVariant    = errors.New("variant sku or barcode already exists")
+	ErrTransactionNotFound = errors.New("transaction not found")
+	ErrTransactionRefunded = errors.New("transaction has already been refunded")
+	ErrInsufficientStock   = errors.New("insufficient stock")
+
+	ErrVersionMismatch     = errors.New("resource has been modified by another request")
+	ErrInvalidETag         = errors.New("invalid If-Match header")
+	ErrPreconditionMissing = errors.New("If-Match header is required")
+
+	ErrUnsupportedMediaType = errors.New("content type must be application/merge-patch+json")
+	ErrInvalidMergePatch    = errors.New("invalid merge patch document")
+
+	ErrBulkUpdateNoSelector = errors.New("product_ids, category_id or search is required")
+	ErrBulkUpdateNoChange   = errors.New("price or stock change is required")
+	ErrNegativePrice        = errors.New("price change would result in a negative price")
+	ErrNegativeStock        = errors.New("stock change would result in a negative stock")
 )
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+func SetETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", ETag(version))
+}
+
+// IfMatchVersions returns the versions the client accepts from the
+// If-Match header, a comma separated list of ETags. A missing header or "*"
+// yields nil, which repositories treat as "any version".
+func IfMatchVersions(r *http.Request) ([]int, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	var versions []int
+	for _, tag := range strings.Split(header, ",") {
+		version, ok := parseETag(strings.TrimSpace(tag))
+		if !ok {
+			return nil, ErrInvalidETag
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+// NotModified sets the ETag header and reports whether If-None-Match matches
+// the current version, in which case a 304 has already been written.
+func NotModified(w http.ResponseWriter, r *http.Request, version int) bool {
+	SetETag(w, version)
+
+	header := strings.TrimSpace(r.Header.Get("If-None-Match"))
+	if header == "" {
+		return false
+	}
+
+	matched := header == "*"
+	for _, tag := range strings.Split(header, ",") {
+		if v, ok := parseETag(strings.TrimSpace(tag)); ok && v == version {
+			matched = true
+		}
+	}
+	if matched {
+		w.WriteHeader(http.StatusNotModified)
+	}
+	return matched
+}
+
+func parseETag(tag string) (int, bool) {
+	tag = strings.TrimPrefix(tag, "W/")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+	version, err := strconv.Atoi(tag[1 : len(tag)-1])
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+	return version, true
+}
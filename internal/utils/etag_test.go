@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"errors"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestIfMatchVersions(t *testing.T) {
+	tests := []struct {
+		header   string
+		versions []int
+		err      error
+	}{
+		{header: "", versions: nil},
+		{header: "*", versions: nil},
+		{header: `"3"`, versions: []int{3}},
+		{header: `W/"3"`, versions: []int{3}},
+		{header: `"1", "2"`, versions: []int{1, 2}},
+		{header: `"1",W/"2" ,"5"`, versions: []int{1, 2, 5}},
+		{header: `"1", abc`, err: ErrInvalidETag},
+		{header: `"0"`, err: ErrInvalidETag},
+		{header: `"1",`, err: ErrInvalidETag},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest("PUT", "/api/products/1", nil)
+		if test.header != "" {
+			r.Header.Set("If-Match", test.header)
+		}
+
+		versions, err := IfMatchVersions(r)
+		if !errors.Is(err, test.err) {
+			t.Errorf("If-Match %q: error %v, want %v", test.header, err, test.err)
+			continue
+		}
+		if !slices.Equal(versions, test.versions) {
+			t.Errorf("If-Match %q: versions %v, want %v", test.header, versions, test.versions)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+)
+
+const MergePatchContentType = "application/merge-patch+json"
+
+// DecodeMergePatch decodes an RFC 7396 merge patch document into dst and
+// returns the members that were explicitly set to null, which a plain
+// decode into pointer fields cannot tell apart from absent members.
+func DecodeMergePatch(r *http.Request, dst interface{}) (map[string]bool, error) {
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != MergePatchContentType && mediaType != "application/json") {
+			return nil, ErrUnsupportedMediaType
+		}
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, ErrInvalidMergePatch
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(body, &members); err != nil || members == nil {
+		return nil, ErrInvalidMergePatch
+	}
+
+	nulls := make(map[string]bool)
+	for name, value := range members {
+		if bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+			nulls[name] = true
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		return nil, ErrInvalidMergePatch
+	}
+
+	return nulls, nil
+}
@@ -0,0 +1,17 @@
+package utils
+
+import "context"
+
+type tenantIDKey struct{}
+
+// WithTenantID returns a context whose database statements only see the
+// rows of the tenant.
+func WithTenantID(ctx context.Context, tenantID int) context.Context {
+	return context.WithValue(ctx, tenantIDKey{}, tenantID)
+}
+
+// TenantIDFromContext returns the tenant stored by WithTenantID, if any.
+func TenantIDFromContext(ctx context.Context) (int, bool) {
+	tenantID, ok := ctx.Value(tenantIDKey{}).(int)
+	return tenantID, ok
+}
@@ -3,17 +3,33 @@ package utils
 import (
 	"encoding/json"
 	"net/http"
+	"reflect"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
 )
 
-var Validate = validator.New()
+var Validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	// report fields by their json name, e.g. "product_id" instead of "ProductID"
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "" || name == "-" {
+			return field.Name
+		}
+		return name
+	})
+	return v
+}
 
 var validationMessages = map[string]string{
 	"required": "{field} is required",
 	"min":      "{field} must be at least {param} characters",
 	"max":      "{field} must be less than {param} characters",
+	"oneof":    "{field} must be one of {param}",
+	"gt":       "{field} must be greater than {param}",
 }
 
 type FieldError struct {
@@ -59,12 +75,31 @@ func MapValidationErrors(err error) []FieldError {
 }
 
 func ValidationErrorResponse(w http.ResponseWriter, err error) error {
+	return FieldErrorsResponse(w, MapValidationErrors(err))
+}
+
+func FieldErrorsResponse(w http.ResponseWriter, errors []FieldError) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusBadRequest)
 
 	return json.NewEncoder(w).Encode(ValidationError{
 		Status:  false,
 		Message: "validation error",
-		Errors:  MapValidationErrors(err),
+		Errors:  errors,
 	})
 }
+
+// NullFieldErrors reports every non-nullable field that a merge patch tried
+// to remove by setting it to null.
+func NullFieldErrors(nulls map[string]bool, fields ...string) []FieldError {
+	var errors []FieldError
+	for _, field := range fields {
+		if nulls[field] {
+			errors = append(errors, FieldError{
+				Field:   field,
+				Message: field + " cannot be null",
+			})
+		}
+	}
+	return errors
+}
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS modifier_groups (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    min_select INTEGER NOT NULL DEFAULT 0 CHECK (min_select >= 0),
+    max_select INTEGER NOT NULL DEFAULT 0 CHECK (max_select >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS modifiers (
+    id SERIAL PRIMARY KEY,
+    group_id INTEGER NOT NULL REFERENCES modifier_groups(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    price_delta INTEGER NOT NULL DEFAULT 0,
+    ingredient_product_id INTEGER REFERENCES products(id) ON DELETE SET NULL,
+    ingredient_quantity INTEGER NOT NULL DEFAULT 0 CHECK (ingredient_quantity >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS product_modifier_groups (
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    group_id INTEGER NOT NULL REFERENCES modifier_groups(id) ON DELETE CASCADE,
+    PRIMARY KEY (product_id, group_id)
+);
+
+CREATE TABLE IF NOT EXISTS category_modifier_groups (
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    group_id INTEGER NOT NULL REFERENCES modifier_groups(id) ON DELETE CASCADE,
+    PRIMARY KEY (category_id, group_id)
+);
+
+CREATE TABLE IF NOT EXISTS transaction_item_modifiers (
+    id SERIAL PRIMARY KEY,
+    transaction_item_id INTEGER NOT NULL REFERENCES transaction_items(id) ON DELETE CASCADE,
+    modifier_id INTEGER REFERENCES modifiers(id) ON DELETE SET NULL,
+    name VARCHAR(100) NOT NULL,
+    price_delta INTEGER NOT NULL
+);
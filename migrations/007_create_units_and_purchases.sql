@@ -0,0 +1,36 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS base_unit VARCHAR(20) NOT NULL DEFAULT 'pcs';
+
+CREATE TABLE IF NOT EXISTS product_units (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    name VARCHAR(20) NOT NULL,
+    conversion_factor INTEGER NOT NULL CHECK (conversion_factor > 0),
+    price INTEGER NOT NULL DEFAULT 0,
+    barcode VARCHAR(64) UNIQUE,
+    UNIQUE (product_id, name)
+);
+
+ALTER TABLE transaction_items ADD COLUMN IF NOT EXISTS unit_id INTEGER REFERENCES product_units(id) ON DELETE SET NULL;
+ALTER TABLE transaction_items ADD COLUMN IF NOT EXISTS unit_name VARCHAR(20) NOT NULL DEFAULT 'pcs';
+ALTER TABLE transaction_items ADD COLUMN IF NOT EXISTS base_quantity INTEGER NOT NULL DEFAULT 0;
+UPDATE transaction_items SET base_quantity = quantity WHERE base_quantity = 0;
+
+CREATE TABLE IF NOT EXISTS purchases (
+    id SERIAL PRIMARY KEY,
+    supplier VARCHAR(255) NOT NULL DEFAULT '',
+    total_cost INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS purchase_items (
+    id SERIAL PRIMARY KEY,
+    purchase_id INTEGER NOT NULL REFERENCES purchases(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id),
+    variant_id INTEGER REFERENCES product_variants(id) ON DELETE SET NULL,
+    unit_id INTEGER REFERENCES product_units(id) ON DELETE SET NULL,
+    unit_name VARCHAR(20) NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    base_quantity INTEGER NOT NULL,
+    unit_cost INTEGER NOT NULL,
+    subtotal INTEGER NOT NULL
+);
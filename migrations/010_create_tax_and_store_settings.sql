@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS tax_rates (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    rate NUMERIC(5, 2) NOT NULL CHECK (rate >= 0 AND rate <= 100)
+);
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS tax_rate_id INTEGER REFERENCES tax_rates(id) ON DELETE SET NULL;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS tax_exempt BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS tax_rate_id INTEGER REFERENCES tax_rates(id) ON DELETE SET NULL;
+
+-- store_settings always holds exactly one row.
+CREATE TABLE IF NOT EXISTS store_settings (
+    id INTEGER PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    default_tax_rate_id INTEGER REFERENCES tax_rates(id) ON DELETE SET NULL,
+    tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    service_charge_rate NUMERIC(5, 2) NOT NULL DEFAULT 0 CHECK (service_charge_rate >= 0 AND service_charge_rate <= 100),
+    service_charge_order VARCHAR(10) NOT NULL DEFAULT 'before_tax' CHECK (service_charge_order IN ('before_tax', 'after_tax'))
+);
+
+INSERT INTO store_settings (id) VALUES (1) ON CONFLICT DO NOTHING;
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS service_charge INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS tax_amount INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE transaction_items ADD COLUMN IF NOT EXISTS tax_rate NUMERIC(5, 2) NOT NULL DEFAULT 0;
+ALTER TABLE transaction_items ADD COLUMN IF NOT EXISTS service_charge INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE transaction_items ADD COLUMN IF NOT EXISTS tax_amount INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS price_lists (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    is_default BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+-- At most one price list is the default for customers without their own.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_price_lists_default ON price_lists (is_default) WHERE is_default;
+
+CREATE TABLE IF NOT EXISTS price_list_items (
+    id SERIAL PRIMARY KEY,
+    price_list_id INTEGER NOT NULL REFERENCES price_lists(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_id INTEGER REFERENCES product_variants(id) ON DELETE CASCADE,
+    min_quantity INTEGER NOT NULL DEFAULT 1 CHECK (min_quantity >= 1),
+    price INTEGER NOT NULL CHECK (price >= 0)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_price_list_items_break
+    ON price_list_items (price_list_id, product_id, COALESCE(variant_id, 0), min_quantity);
+
+CREATE TABLE IF NOT EXISTS customer_price_lists (
+    customer_id INTEGER PRIMARY KEY,
+    price_list_id INTEGER NOT NULL REFERENCES price_lists(id) ON DELETE CASCADE
+);
+
+ALTER TABLE transaction_items ADD COLUMN IF NOT EXISTS price_list_id INTEGER REFERENCES price_lists(id) ON DELETE SET NULL;
@@ -0,0 +1,33 @@
+-- Future-dated prices. A scheduled price is written to products.price the
+-- first time it is due at sale time, and applied_at records when that happened.
+CREATE TABLE IF NOT EXISTS scheduled_prices (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    price INTEGER NOT NULL CHECK (price >= 0),
+    starts_at TIMESTAMPTZ NOT NULL,
+    applied_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_prices_pending ON scheduled_prices (starts_at) WHERE applied_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_scheduled_prices_product ON scheduled_prices (product_id, starts_at);
+
+-- Recurring time-of-day pricing such as happy hour. days_of_week uses
+-- 0 = Sunday .. 6 = Saturday; an empty array means every day. A window whose
+-- end_time is before start_time runs past midnight.
+CREATE TABLE IF NOT EXISTS price_windows (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    product_id INTEGER REFERENCES products(id) ON DELETE CASCADE,
+    category_id INTEGER REFERENCES categories(id) ON DELETE CASCADE,
+    days_of_week INTEGER[] NOT NULL DEFAULT '{}',
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    adjustment_type VARCHAR(20) NOT NULL CHECK (adjustment_type IN ('percentage', 'price')),
+    value INTEGER NOT NULL CHECK (value >= 0),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    CHECK ((product_id IS NULL) <> (category_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_windows_product ON price_windows (product_id) WHERE product_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_price_windows_category ON price_windows (category_id) WHERE category_id IS NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE price_history ADD COLUMN IF NOT EXISTS changed_by VARCHAR(100) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_price_history_created_at ON price_history (created_at);
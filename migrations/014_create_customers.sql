@@ -0,0 +1,49 @@
+CREATE TABLE IF NOT EXISTS customers (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    phone VARCHAR(20) NOT NULL DEFAULT '',
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    address TEXT NOT NULL DEFAULT '',
+    notes TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customers_phone ON customers (phone) WHERE phone <> '';
+
+-- Customer ids were stored before this table existed. Keep them by creating
+-- placeholder customers so the foreign keys below can be added.
+INSERT INTO customers (id, name)
+SELECT DISTINCT customer_id, 'Customer ' || customer_id
+FROM (
+    SELECT customer_id FROM transactions WHERE customer_id IS NOT NULL
+    UNION
+    SELECT customer_id FROM customer_price_lists
+    UNION
+    SELECT customer_id FROM voucher_redemptions WHERE customer_id IS NOT NULL
+) ids
+ON CONFLICT (id) DO NOTHING;
+
+SELECT setval(pg_get_serial_sequence('customers', 'id'), COALESCE((SELECT MAX(id) FROM customers), 0) + 1, false);
+
+DO $$
+BEGIN
+    ALTER TABLE transactions ADD CONSTRAINT fk_transactions_customer
+        FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE SET NULL;
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+DO $$
+BEGIN
+    ALTER TABLE customer_price_lists ADD CONSTRAINT fk_customer_price_lists_customer
+        FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE CASCADE;
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+DO $$
+BEGIN
+    ALTER TABLE voucher_redemptions ADD CONSTRAINT fk_voucher_redemptions_customer
+        FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE SET NULL;
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_customer ON transactions (customer_id, created_at DESC) WHERE customer_id IS NOT NULL;
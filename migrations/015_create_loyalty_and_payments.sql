@@ -0,0 +1,59 @@
+-- loyalty_settings always holds exactly one row. Customers earn one point
+-- for every spend_per_point Rupiah paid and each point is worth point_value
+-- Rupiah when redeemed. Points expire expiry_days after they are earned;
+-- 0 means they never expire.
+CREATE TABLE IF NOT EXISTS loyalty_settings (
+    id INTEGER PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    spend_per_point INTEGER NOT NULL DEFAULT 10000 CHECK (spend_per_point > 0),
+    point_value INTEGER NOT NULL DEFAULT 1 CHECK (point_value > 0),
+    expiry_days INTEGER NOT NULL DEFAULT 365 CHECK (expiry_days >= 0),
+    min_redeem_points INTEGER NOT NULL DEFAULT 0 CHECK (min_redeem_points >= 0)
+);
+INSERT INTO loyalty_settings (id) VALUES (1) ON CONFLICT DO NOTHING;
+
+-- Bonus points per base unit sold. A product without its own bonus uses
+-- the bonus of its category.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS loyalty_bonus_points INTEGER CHECK (loyalty_bonus_points >= 0);
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS loyalty_bonus_points INTEGER NOT NULL DEFAULT 0 CHECK (loyalty_bonus_points >= 0);
+
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS loyalty_points INTEGER NOT NULL DEFAULT 0;
+
+-- Every change to a customer's points. Positive entries are lots that are
+-- spent oldest-expiry first; remaining is what is left of a lot.
+CREATE TABLE IF NOT EXISTS loyalty_ledger (
+    id SERIAL PRIMARY KEY,
+    customer_id INTEGER NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    transaction_id INTEGER REFERENCES transactions(id) ON DELETE SET NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('earn', 'redeem', 'expire', 'reverse')),
+    points INTEGER NOT NULL,
+    remaining INTEGER NOT NULL DEFAULT 0 CHECK (remaining >= 0),
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_loyalty_ledger_customer ON loyalty_ledger (customer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_loyalty_ledger_lots ON loyalty_ledger (customer_id, expires_at) WHERE remaining > 0;
+CREATE INDEX IF NOT EXISTS idx_loyalty_ledger_transaction ON loyalty_ledger (transaction_id) WHERE transaction_id IS NOT NULL;
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'completed';
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS refunded_at TIMESTAMPTZ;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS change_amount INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS points_earned INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS points_redeemed INTEGER NOT NULL DEFAULT 0;
+
+-- Tenders used to pay a transaction. Transactions made before this table
+-- existed were paid in cash.
+CREATE TABLE IF NOT EXISTS transaction_payments (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    method VARCHAR(20) NOT NULL,
+    amount INTEGER NOT NULL CHECK (amount >= 0),
+    points INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_payments_transaction ON transaction_payments (transaction_id);
+
+INSERT INTO transaction_payments (transaction_id, method, amount)
+SELECT id, 'cash', total_amount FROM transactions
+WHERE NOT EXISTS (SELECT 1 FROM transaction_payments WHERE transaction_payments.transaction_id = transactions.id);
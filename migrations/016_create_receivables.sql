@@ -0,0 +1,23 @@
+-- Store credit (kasbon). A customer may owe at most credit_limit Rupiah;
+-- 0 means they cannot buy on account. credit_balance is what they owe now.
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS credit_limit INTEGER NOT NULL DEFAULT 0 CHECK (credit_limit >= 0);
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS credit_balance INTEGER NOT NULL DEFAULT 0 CHECK (credit_balance >= 0);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS on_account_amount INTEGER NOT NULL DEFAULT 0;
+
+-- Every change to what a customer owes, always against one transaction.
+-- Charges are positive; repayments and refund reversals are negative, so the
+-- outstanding amount of a transaction is the sum of its entries.
+CREATE TABLE IF NOT EXISTS receivable_ledger (
+    id SERIAL PRIMARY KEY,
+    customer_id INTEGER NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('charge', 'repayment', 'reverse')),
+    amount INTEGER NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_receivable_ledger_customer ON receivable_ledger (customer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_receivable_ledger_transaction ON receivable_ledger (transaction_id);
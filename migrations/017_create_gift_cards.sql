@@ -0,0 +1,29 @@
+-- Prepaid gift cards. Codes are random and only ever compared exactly;
+-- balance is kept in step with gift_card_ledger.
+CREATE TABLE IF NOT EXISTS gift_cards (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(32) NOT NULL UNIQUE,
+    balance INTEGER NOT NULL DEFAULT 0 CHECK (balance >= 0),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Every balance movement of a gift card. Issues and top-ups are positive,
+-- redemptions negative; reverse entries undo them on a refund.
+CREATE TABLE IF NOT EXISTS gift_card_ledger (
+    id SERIAL PRIMARY KEY,
+    gift_card_id INTEGER NOT NULL REFERENCES gift_cards(id) ON DELETE CASCADE,
+    transaction_id INTEGER REFERENCES transactions(id) ON DELETE SET NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('issue', 'top_up', 'redeem', 'reverse')),
+    amount INTEGER NOT NULL,
+    balance_after INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_gift_card_ledger_card ON gift_card_ledger (gift_card_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_gift_card_ledger_transaction ON gift_card_ledger (transaction_id) WHERE transaction_id IS NOT NULL;
+
+-- Gift cards sold or topped up at checkout are added to the total after tax.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS gift_card_amount INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE transaction_payments ADD COLUMN IF NOT EXISTS gift_card_id INTEGER REFERENCES gift_cards(id) ON DELETE SET NULL;
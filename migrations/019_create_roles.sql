@@ -0,0 +1,33 @@
+-- A user may hold several roles and gets the union of their permissions.
+-- Permissions are plain strings such as product:write; the API only accepts
+-- the ones it knows.
+CREATE TABLE IF NOT EXISTS roles (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id INTEGER NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    permission VARCHAR(50) NOT NULL,
+    PRIMARY KEY (role_id, permission)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role_id INTEGER NOT NULL REFERENCES roles(id),
+    PRIMARY KEY (user_id, role_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_roles_role ON user_roles (role_id);
+
+-- Users created before roles existed had full access; keep it that way.
+-- The owner role gets its permissions from 020_seed_default_roles.sql.
+INSERT INTO roles (name) VALUES ('owner') ON CONFLICT DO NOTHING;
+
+INSERT INTO user_roles (user_id, role_id)
+SELECT users.id, roles.id
+FROM users
+JOIN roles ON roles.name = 'owner'
+WHERE NOT EXISTS (SELECT 1 FROM user_roles WHERE user_roles.user_id = users.id);
@@ -0,0 +1,35 @@
+-- Default roles. Safe to run again: missing roles and permissions are
+-- restored, anything added through the API is kept.
+INSERT INTO roles (name, description) VALUES
+    ('owner', 'Full access, including users and roles'),
+    ('manager', 'Manages products, categories and prices, and voids transactions'),
+    ('cashier', 'Sells and looks up products')
+ON CONFLICT (name) DO UPDATE SET description = EXCLUDED.description
+WHERE roles.description = '';
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT roles.id, defaults.permission
+FROM (VALUES
+    ('owner', 'product:read'),
+    ('owner', 'product:write'),
+    ('owner', 'product:delete'),
+    ('owner', 'category:read'),
+    ('owner', 'category:write'),
+    ('owner', 'category:delete'),
+    ('owner', 'price:write'),
+    ('owner', 'transaction:void'),
+    ('owner', 'user:manage'),
+    ('owner', 'role:manage'),
+    ('manager', 'product:read'),
+    ('manager', 'product:write'),
+    ('manager', 'product:delete'),
+    ('manager', 'category:read'),
+    ('manager', 'category:write'),
+    ('manager', 'category:delete'),
+    ('manager', 'price:write'),
+    ('manager', 'transaction:void'),
+    ('cashier', 'product:read'),
+    ('cashier', 'category:read')
+) AS defaults (role, permission)
+JOIN roles ON roles.name = defaults.role
+ON CONFLICT DO NOTHING;
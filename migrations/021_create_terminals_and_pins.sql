@@ -0,0 +1,38 @@
+-- Cashiers switch on a shared counter terminal with a numeric PIN instead
+-- of their password. A PIN is locked for a while after too many failures.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pin_hash VARCHAR(255);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pin_failed_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pin_locked_until TIMESTAMPTZ;
+
+-- PIN login is only accepted from a registered terminal, which proves
+-- itself with a secret key. Only the key's SHA-256 hash is kept.
+CREATE TABLE IF NOT EXISTS terminals (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    last_used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- A terminal has at most one active cashier: a PIN login revokes the
+-- session of the cashier before.
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS terminal_id INTEGER REFERENCES terminals(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_sessions_terminal ON sessions (terminal_id) WHERE revoked_at IS NULL;
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS cashier_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS terminal_id INTEGER REFERENCES terminals(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_transactions_cashier ON transactions (cashier_id, created_at DESC);
+
+-- Selling now needs a logged in cashier.
+INSERT INTO role_permissions (role_id, permission)
+SELECT roles.id, grants.permission
+FROM (VALUES
+    ('owner', 'transaction:create'),
+    ('owner', 'terminal:manage'),
+    ('manager', 'transaction:create'),
+    ('manager', 'terminal:manage'),
+    ('cashier', 'transaction:create')
+) AS grants (role, permission)
+JOIN roles ON roles.name = grants.role
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,40 @@
+-- Voids, large manual discounts and price overrides at the register need a
+-- supervisor. Every such operation is recorded with who asked for it and
+-- who approved it, which is the same user when they could do it alone.
+CREATE TABLE IF NOT EXISTS approvals (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('void', 'discount', 'price_override')),
+    details TEXT NOT NULL DEFAULT '',
+    requested_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    approved_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    method VARCHAR(10) NOT NULL CHECK (method IN ('self', 'pin', 'token')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_approvals_transaction ON approvals (transaction_id);
+
+-- approval_settings always holds exactly one row. A manual discount above
+-- discount_threshold_percent of the subtotal needs approval; 0 makes every
+-- manual discount need it.
+CREATE TABLE IF NOT EXISTS approval_settings (
+    id INTEGER PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    discount_threshold_percent NUMERIC(5, 2) NOT NULL DEFAULT 10
+        CHECK (discount_threshold_percent >= 0 AND discount_threshold_percent <= 100)
+);
+
+INSERT INTO approval_settings (id) VALUES (1) ON CONFLICT DO NOTHING;
+
+-- The unit price a line was sold at before the cashier overrode it.
+ALTER TABLE transaction_items ADD COLUMN IF NOT EXISTS original_unit_price INTEGER;
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT roles.id, grants.permission
+FROM (VALUES
+    ('owner', 'discount:approve'),
+    ('owner', 'price:override'),
+    ('manager', 'discount:approve'),
+    ('manager', 'price:override')
+) AS grants (role, permission)
+JOIN roles ON roles.name = grants.role
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,24 @@
+-- API keys let scripts and integrations call the API without a user login.
+-- Only the key's SHA-256 hash is kept; prefix is its first characters, so
+-- a key can be recognised without storing it. A key carries its own
+-- permissions instead of roles.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    prefix VARCHAR(20) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    permissions TEXT[] NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    rotated_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Changes made with an API key are attributed to it in the audit log.
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS api_key_id INTEGER REFERENCES api_keys(id) ON DELETE SET NULL;
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT roles.id, 'apikey:manage' FROM roles WHERE roles.name = 'owner'
+ON CONFLICT DO NOTHING;
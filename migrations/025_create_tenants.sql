@@ -0,0 +1,201 @@
+-- Several independent stores (tenants) can share one deployment. Every
+-- table below carries the tenant its row belongs to, and row level security
+-- keeps each tenant to its own rows: the API sets app.tenant_id to the
+-- tenant of the logged in user or API key before every statement, and new
+-- rows get that tenant by default.
+--
+-- Row level security does not apply to superusers or roles with BYPASSRLS,
+-- so the API must connect as a role without them. Tenant 1 is the store
+-- that existed before tenants; its users are the only ones who can create
+-- new tenants.
+CREATE TABLE IF NOT EXISTS tenants (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO tenants (id, name) VALUES (1, 'Default') ON CONFLICT DO NOTHING;
+SELECT setval(pg_get_serial_sequence('tenants', 'id'), GREATEST((SELECT MAX(id) FROM tenants), 1));
+
+-- The tenant the current statement runs for, or NULL when none is set.
+CREATE OR REPLACE FUNCTION current_tenant_id() RETURNS INTEGER
+LANGUAGE sql STABLE AS $$
+    SELECT NULLIF(current_setting('app.tenant_id', true), '')::INTEGER
+$$;
+
+-- Existing rows belong to tenant 1.
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'products', 'categories', 'price_history', 'product_variant_options', 'product_variants',
+        'product_components', 'stock_movements', 'modifier_groups', 'modifiers',
+        'product_modifier_groups', 'category_modifier_groups', 'product_units',
+        'purchases', 'purchase_items', 'promotions', 'promotion_products', 'promotion_categories',
+        'vouchers', 'voucher_redemptions', 'tax_rates', 'store_settings',
+        'price_lists', 'price_list_items', 'customer_price_lists', 'scheduled_prices', 'price_windows',
+        'customers', 'loyalty_settings', 'loyalty_ledger', 'receivable_ledger',
+        'gift_cards', 'gift_card_ledger', 'transactions', 'transaction_items',
+        'transaction_item_modifiers', 'transaction_discounts', 'transaction_payments',
+        'approvals', 'approval_settings', 'audit_logs',
+        'users', 'roles', 'role_permissions', 'user_roles', 'terminals', 'api_keys'
+    ] LOOP
+        EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS tenant_id INTEGER REFERENCES tenants(id) ON DELETE CASCADE', t);
+        EXECUTE format('UPDATE %I SET tenant_id = 1 WHERE tenant_id IS NULL', t);
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN tenant_id SET DEFAULT current_tenant_id(), ALTER COLUMN tenant_id SET NOT NULL', t);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS %I ON %I (tenant_id)', 'idx_' || t || '_tenant', t);
+    END LOOP;
+END $$;
+
+-- Codes, names and barcodes only have to be unique within a tenant.
+-- Usernames stay unique across tenants, since login finds the tenant from
+-- the username.
+ALTER TABLE product_variants DROP CONSTRAINT IF EXISTS product_variants_sku_key;
+ALTER TABLE product_variants DROP CONSTRAINT IF EXISTS product_variants_barcode_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_variants_sku ON product_variants (tenant_id, sku);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_variants_barcode ON product_variants (tenant_id, barcode);
+
+ALTER TABLE product_units DROP CONSTRAINT IF EXISTS product_units_barcode_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_units_barcode ON product_units (tenant_id, barcode);
+
+ALTER TABLE vouchers DROP CONSTRAINT IF EXISTS vouchers_code_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_vouchers_code ON vouchers (tenant_id, code);
+
+ALTER TABLE gift_cards DROP CONSTRAINT IF EXISTS gift_cards_code_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_gift_cards_code ON gift_cards (tenant_id, code);
+
+ALTER TABLE price_lists DROP CONSTRAINT IF EXISTS price_lists_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_price_lists_name ON price_lists (tenant_id, name);
+DROP INDEX IF EXISTS idx_price_lists_default;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_price_lists_tenant_default ON price_lists (tenant_id) WHERE is_default;
+
+DROP INDEX IF EXISTS idx_customers_phone;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customers_tenant_phone ON customers (tenant_id, phone) WHERE phone <> '';
+
+ALTER TABLE roles DROP CONSTRAINT IF EXISTS roles_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_roles_name ON roles (tenant_id, name);
+
+ALTER TABLE terminals DROP CONSTRAINT IF EXISTS terminals_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_terminals_name ON terminals (tenant_id, name);
+
+ALTER TABLE api_keys DROP CONSTRAINT IF EXISTS api_keys_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_name ON api_keys (tenant_id, name);
+
+-- Each tenant has its own row of every settings table.
+ALTER TABLE store_settings DROP CONSTRAINT IF EXISTS store_settings_pkey;
+ALTER TABLE store_settings DROP COLUMN IF EXISTS id;
+ALTER TABLE store_settings ADD PRIMARY KEY (tenant_id);
+
+ALTER TABLE loyalty_settings DROP CONSTRAINT IF EXISTS loyalty_settings_pkey;
+ALTER TABLE loyalty_settings DROP COLUMN IF EXISTS id;
+ALTER TABLE loyalty_settings ADD PRIMARY KEY (tenant_id);
+
+ALTER TABLE approval_settings DROP CONSTRAINT IF EXISTS approval_settings_pkey;
+ALTER TABLE approval_settings DROP COLUMN IF EXISTS id;
+ALTER TABLE approval_settings ADD PRIMARY KEY (tenant_id);
+
+-- seed_tenant gives a tenant its settings and the default roles. Safe to
+-- run again: missing roles and permissions are restored, anything added
+-- through the API is kept. Re-seed every tenant with
+-- SELECT seed_tenant(id) FROM tenants;
+CREATE OR REPLACE FUNCTION seed_tenant(tenant INTEGER) RETURNS VOID
+LANGUAGE sql AS $$
+    INSERT INTO store_settings (tenant_id) VALUES (tenant) ON CONFLICT DO NOTHING;
+    INSERT INTO loyalty_settings (tenant_id) VALUES (tenant) ON CONFLICT DO NOTHING;
+    INSERT INTO approval_settings (tenant_id) VALUES (tenant) ON CONFLICT DO NOTHING;
+
+    INSERT INTO roles (tenant_id, name, description) VALUES
+        (tenant, 'owner', 'Full access, including users and roles'),
+        (tenant, 'manager', 'Manages products, categories and prices, and voids transactions'),
+        (tenant, 'cashier', 'Sells and looks up products')
+    ON CONFLICT (tenant_id, name) DO UPDATE SET description = EXCLUDED.description
+    WHERE roles.description = '';
+
+    INSERT INTO role_permissions (tenant_id, role_id, permission)
+    SELECT tenant, roles.id, defaults.permission
+    FROM (VALUES
+        ('owner', 'product:read'),
+        ('owner', 'product:write'),
+        ('owner', 'product:delete'),
+        ('owner', 'category:read'),
+        ('owner', 'category:write'),
+        ('owner', 'category:delete'),
+        ('owner', 'price:write'),
+        ('owner', 'price:override'),
+        ('owner', 'discount:approve'),
+        ('owner', 'transaction:create'),
+        ('owner', 'transaction:void'),
+        ('owner', 'terminal:manage'),
+        ('owner', 'user:manage'),
+        ('owner', 'role:manage'),
+        ('owner', 'audit:read'),
+        ('owner', 'apikey:manage'),
+        ('manager', 'product:read'),
+        ('manager', 'product:write'),
+        ('manager', 'product:delete'),
+        ('manager', 'category:read'),
+        ('manager', 'category:write'),
+        ('manager', 'category:delete'),
+        ('manager', 'price:write'),
+        ('manager', 'price:override'),
+        ('manager', 'discount:approve'),
+        ('manager', 'transaction:create'),
+        ('manager', 'transaction:void'),
+        ('manager', 'terminal:manage'),
+        ('cashier', 'product:read'),
+        ('cashier', 'category:read'),
+        ('cashier', 'transaction:create')
+    ) AS defaults (role, permission)
+    JOIN roles ON roles.tenant_id = tenant AND roles.name = defaults.role
+    ON CONFLICT DO NOTHING;
+$$;
+
+SELECT seed_tenant(id) FROM tenants;
+
+-- Only the owners of tenant 1 run the deployment and create tenants.
+INSERT INTO role_permissions (tenant_id, role_id, permission)
+SELECT roles.tenant_id, roles.id, 'tenant:manage' FROM roles WHERE roles.tenant_id = 1 AND roles.name = 'owner'
+ON CONFLICT DO NOTHING;
+
+-- Business data is only visible with its tenant set. The tables needed to
+-- log in are also readable without a tenant, since login is how the tenant
+-- is found; once it is set they are limited to it as well. Sessions and
+-- refresh tokens belong to a user and are only found through one.
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'products', 'categories', 'price_history', 'product_variant_options', 'product_variants',
+        'product_components', 'stock_movements', 'modifier_groups', 'modifiers',
+        'product_modifier_groups', 'category_modifier_groups', 'product_units',
+        'purchases', 'purchase_items', 'promotions', 'promotion_products', 'promotion_categories',
+        'vouchers', 'voucher_redemptions', 'tax_rates', 'store_settings',
+        'price_lists', 'price_list_items', 'customer_price_lists', 'scheduled_prices', 'price_windows',
+        'customers', 'loyalty_settings', 'loyalty_ledger', 'receivable_ledger',
+        'gift_cards', 'gift_card_ledger', 'transactions', 'transaction_items',
+        'transaction_item_modifiers', 'transaction_discounts', 'transaction_payments',
+        'approvals', 'approval_settings', 'audit_logs'
+    ] LOOP
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', t);
+        EXECUTE format(
+            'CREATE POLICY tenant_isolation ON %I USING (tenant_id = current_tenant_id()) WITH CHECK (tenant_id = current_tenant_id())',
+            t
+        );
+    END LOOP;
+
+    FOREACH t IN ARRAY ARRAY['users', 'roles', 'role_permissions', 'user_roles', 'terminals', 'api_keys'] LOOP
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', t);
+        EXECUTE format(
+            'CREATE POLICY tenant_isolation ON %I
+            USING (current_tenant_id() IS NULL OR tenant_id = current_tenant_id())
+            WITH CHECK (current_tenant_id() IS NULL OR tenant_id = current_tenant_id())',
+            t
+        );
+    END LOOP;
+END $$;
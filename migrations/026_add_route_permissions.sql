@@ -0,0 +1,120 @@
+-- Every route now checks a permission. These cover what any logged in
+-- user could reach before: past transactions, customers and their credit,
+-- purchases, promotions and vouchers, settings and reports.
+INSERT INTO role_permissions (tenant_id, role_id, permission)
+SELECT roles.tenant_id, roles.id, grants.permission
+FROM (VALUES
+    ('owner', 'transaction:read'),
+    ('owner', 'customer:read'),
+    ('owner', 'customer:write'),
+    ('owner', 'customer:delete'),
+    ('owner', 'credit:manage'),
+    ('owner', 'purchase:read'),
+    ('owner', 'purchase:write'),
+    ('owner', 'promotion:read'),
+    ('owner', 'promotion:write'),
+    ('owner', 'settings:read'),
+    ('owner', 'settings:write'),
+    ('owner', 'report:read'),
+    ('manager', 'transaction:read'),
+    ('manager', 'customer:read'),
+    ('manager', 'customer:write'),
+    ('manager', 'customer:delete'),
+    ('manager', 'credit:manage'),
+    ('manager', 'purchase:read'),
+    ('manager', 'purchase:write'),
+    ('manager', 'promotion:read'),
+    ('manager', 'promotion:write'),
+    ('manager', 'settings:read'),
+    ('manager', 'settings:write'),
+    ('manager', 'report:read'),
+    ('cashier', 'transaction:read'),
+    ('cashier', 'customer:read'),
+    ('cashier', 'customer:write'),
+    ('cashier', 'promotion:read'),
+    ('cashier', 'settings:read')
+) AS grants (role, permission)
+JOIN roles ON roles.name = grants.role
+ON CONFLICT DO NOTHING;
+
+-- New tenants get them with the default roles.
+CREATE OR REPLACE FUNCTION seed_tenant(tenant INTEGER) RETURNS VOID
+LANGUAGE sql AS $$
+    INSERT INTO store_settings (tenant_id) VALUES (tenant) ON CONFLICT DO NOTHING;
+    INSERT INTO loyalty_settings (tenant_id) VALUES (tenant) ON CONFLICT DO NOTHING;
+    INSERT INTO approval_settings (tenant_id) VALUES (tenant) ON CONFLICT DO NOTHING;
+
+    INSERT INTO roles (tenant_id, name, description) VALUES
+        (tenant, 'owner', 'Full access, including users and roles'),
+        (tenant, 'manager', 'Manages products, categories and prices, and voids transactions'),
+        (tenant, 'cashier', 'Sells and looks up products')
+    ON CONFLICT (tenant_id, name) DO UPDATE SET description = EXCLUDED.description
+    WHERE roles.description = '';
+
+    INSERT INTO role_permissions (tenant_id, role_id, permission)
+    SELECT tenant, roles.id, defaults.permission
+    FROM (VALUES
+        ('owner', 'product:read'),
+        ('owner', 'product:write'),
+        ('owner', 'product:delete'),
+        ('owner', 'category:read'),
+        ('owner', 'category:write'),
+        ('owner', 'category:delete'),
+        ('owner', 'price:write'),
+        ('owner', 'price:override'),
+        ('owner', 'discount:approve'),
+        ('owner', 'transaction:read'),
+        ('owner', 'transaction:create'),
+        ('owner', 'transaction:void'),
+        ('owner', 'customer:read'),
+        ('owner', 'customer:write'),
+        ('owner', 'customer:delete'),
+        ('owner', 'credit:manage'),
+        ('owner', 'purchase:read'),
+        ('owner', 'purchase:write'),
+        ('owner', 'promotion:read'),
+        ('owner', 'promotion:write'),
+        ('owner', 'settings:read'),
+        ('owner', 'settings:write'),
+        ('owner', 'report:read'),
+        ('owner', 'terminal:manage'),
+        ('owner', 'user:manage'),
+        ('owner', 'role:manage'),
+        ('owner', 'audit:read'),
+        ('owner', 'apikey:manage'),
+        ('manager', 'product:read'),
+        ('manager', 'product:write'),
+        ('manager', 'product:delete'),
+        ('manager', 'category:read'),
+        ('manager', 'category:write'),
+        ('manager', 'category:delete'),
+        ('manager', 'price:write'),
+        ('manager', 'price:override'),
+        ('manager', 'discount:approve'),
+        ('manager', 'transaction:read'),
+        ('manager', 'transaction:create'),
+        ('manager', 'transaction:void'),
+        ('manager', 'customer:read'),
+        ('manager', 'customer:write'),
+        ('manager', 'customer:delete'),
+        ('manager', 'credit:manage'),
+        ('manager', 'purchase:read'),
+        ('manager', 'purchase:write'),
+        ('manager', 'promotion:read'),
+        ('manager', 'promotion:write'),
+        ('manager', 'settings:read'),
+        ('manager', 'settings:write'),
+        ('manager', 'report:read'),
+        ('manager', 'terminal:manage'),
+        ('cashier', 'product:read'),
+        ('cashier', 'category:read'),
+        ('cashier', 'transaction:read'),
+        ('cashier', 'transaction:create'),
+        ('cashier', 'customer:read'),
+        ('cashier', 'customer:write'),
+        ('cashier', 'promotion:read'),
+        ('cashier', 'settings:read')
+    ) AS defaults (role, permission)
+    JOIN roles ON roles.tenant_id = tenant AND roles.name = defaults.role
+    ON CONFLICT DO NOTHING;
+$$;
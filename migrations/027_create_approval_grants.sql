@@ -0,0 +1,27 @@
+-- A supervisor approving from their own device issues a grant instead of
+-- handing over their login. A grant covers the listed actions on one
+-- transaction, or on a new sale when transaction_id is NULL, expires after
+-- a few minutes and is used up by the first operation it approves. Only
+-- the SHA-256 hash of its token is kept.
+CREATE TABLE IF NOT EXISTS approval_grants (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL DEFAULT current_tenant_id() REFERENCES tenants(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    approver_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    actions TEXT[] NOT NULL,
+    transaction_id INTEGER REFERENCES transactions(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    consumed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_approval_grants_tenant ON approval_grants (tenant_id);
+
+ALTER TABLE approval_grants ENABLE ROW LEVEL SECURITY;
+ALTER TABLE approval_grants FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON approval_grants;
+CREATE POLICY tenant_isolation ON approval_grants
+    USING (tenant_id = current_tenant_id()) WITH CHECK (tenant_id = current_tenant_id());
+
+-- The grant an approval was given with, if any.
+ALTER TABLE approvals ADD COLUMN IF NOT EXISTS grant_id INTEGER REFERENCES approval_grants(id) ON DELETE SET NULL;
@@ -0,0 +1,140 @@
+-- Audit log entries are written by triggers, inside the same transaction
+-- as the change they record, so a change cannot be saved without its entry.
+-- Besides app.tenant_id the API sets app.actor, app.actor_id,
+-- app.api_key_id, app.ip and app.request_id before every statement; the
+-- entry goes to the tenant the statement runs for. Changes made outside
+-- the API without a tenant, such as migrations, are not logged.
+--
+-- audit_change takes the entity type, the column holding the entity ID and
+-- any further columns to leave out of the changes. tenant_id and version
+-- are always left out, and an update that changed nothing else is not
+-- logged. Stock written by a stock movement (app.stock_movement is on for
+-- the rest of that transaction) is left out of product and variant updates,
+-- since the movement itself is logged. Password, PIN and key hashes are
+-- never written to the log.
+CREATE OR REPLACE FUNCTION audit_change() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+DECLARE
+    ignored TEXT[] := ARRAY['tenant_id', 'version'];
+    old_row JSONB;
+    new_row JSONB;
+    changes JSONB := '{}';
+    change JSONB;
+    field TEXT;
+BEGIN
+    IF current_tenant_id() IS NULL THEN
+        RETURN NULL;
+    END IF;
+
+    FOR i IN 2 .. TG_NARGS - 1 LOOP
+        ignored := ignored || TG_ARGV[i];
+    END LOOP;
+    IF TG_OP = 'UPDATE' AND current_setting('app.stock_movement', true) = 'on' THEN
+        ignored := ignored || 'stock'::TEXT;
+    END IF;
+
+    IF TG_OP <> 'INSERT' THEN
+        old_row := to_jsonb(OLD);
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        new_row := to_jsonb(NEW);
+    END IF;
+
+    FOR field IN
+        SELECT jsonb_object_keys(COALESCE(old_row, '{}') || COALESCE(new_row, '{}'))
+    LOOP
+        CONTINUE WHEN field = ANY (ignored) OR (old_row -> field) IS NOT DISTINCT FROM (new_row -> field);
+        change := '{}';
+        IF old_row ? field THEN
+            change := change || jsonb_build_object('old', old_row -> field);
+        END IF;
+        IF new_row ? field THEN
+            change := change || jsonb_build_object('new', new_row -> field);
+        END IF;
+        changes := changes || jsonb_build_object(field, change);
+    END LOOP;
+
+    IF TG_OP = 'UPDATE' AND changes = '{}' THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO audit_logs (actor, actor_id, api_key_id, action, entity_type, entity_id, changes, ip, request_id)
+    VALUES (
+        COALESCE(current_setting('app.actor', true), ''),
+        NULLIF(current_setting('app.actor_id', true), '')::INTEGER,
+        NULLIF(current_setting('app.api_key_id', true), '')::INTEGER,
+        CASE TG_OP WHEN 'INSERT' THEN 'create' WHEN 'UPDATE' THEN 'update' ELSE 'delete' END,
+        TG_ARGV[0],
+        (COALESCE(new_row, old_row) ->> TG_ARGV[1])::INTEGER,
+        changes,
+        COALESCE(current_setting('app.ip', true), ''),
+        COALESCE(current_setting('app.request_id', true), '')
+    );
+    RETURN NULL;
+END;
+$$;
+
+DO $$
+DECLARE
+    audited TEXT[][] := ARRAY[
+        ['products', 'product', 'id', ''],
+        ['categories', 'category', 'id', ''],
+        ['product_variants', 'variant', 'id', ''],
+        ['product_variant_options', 'variant_option', 'id', ''],
+        ['product_units', 'unit', 'id', ''],
+        ['product_components', 'product_component', 'product_id', ''],
+        ['purchases', 'purchase', 'id', ''],
+        ['purchase_items', 'purchase_item', 'id', ''],
+        ['price_lists', 'price_list', 'id', ''],
+        ['price_list_items', 'price_list_item', 'id', ''],
+        ['customer_price_lists', 'customer_price_list', 'customer_id', ''],
+        ['scheduled_prices', 'scheduled_price', 'id', ''],
+        ['price_windows', 'price_window', 'id', ''],
+        ['api_keys', 'api_key', 'id', 'key_hash, last_used_at'],
+        ['tenants', 'tenant', 'id', ''],
+        ['roles', 'role', 'id', ''],
+        ['role_permissions', 'role_permission', 'role_id', ''],
+        ['user_roles', 'user_role', 'user_id', ''],
+        ['users', 'user', 'id', 'password_hash, pin_hash, pin_failed_attempts, pin_locked_until'],
+        ['terminals', 'terminal', 'id', 'key_hash, last_used_at'],
+        ['customers', 'customer', 'id', ''],
+        ['promotions', 'promotion', 'id', ''],
+        ['promotion_products', 'promotion_product', 'promotion_id', ''],
+        ['promotion_categories', 'promotion_category', 'promotion_id', ''],
+        ['vouchers', 'voucher', 'id', ''],
+        ['tax_rates', 'tax_rate', 'id', ''],
+        ['store_settings', 'store_settings', 'tenant_id', ''],
+        ['loyalty_settings', 'loyalty_settings', 'tenant_id', ''],
+        ['approval_settings', 'approval_settings', 'tenant_id', ''],
+        ['modifier_groups', 'modifier_group', 'id', ''],
+        ['modifiers', 'modifier', 'id', ''],
+        ['product_modifier_groups', 'product_modifier_group', 'product_id', ''],
+        ['category_modifier_groups', 'category_modifier_group', 'category_id', ''],
+        ['gift_cards', 'gift_card', 'id', ''],
+        ['receivable_ledger', 'receivable', 'id', '']
+    ];
+    args TEXT;
+BEGIN
+    FOR i IN 1 .. array_length(audited, 1) LOOP
+        args := quote_literal(audited[i][2]) || ', ' || quote_literal(audited[i][3]);
+        IF audited[i][4] <> '' THEN
+            SELECT args || ', ' || string_agg(quote_literal(trim(col)), ', ')
+            INTO args
+            FROM unnest(string_to_array(audited[i][4], ',')) AS col;
+        END IF;
+
+        EXECUTE format('DROP TRIGGER IF EXISTS audit_change ON %I', audited[i][1]);
+        EXECUTE format(
+            'CREATE TRIGGER audit_change AFTER INSERT OR UPDATE OR DELETE ON %I FOR EACH ROW EXECUTE FUNCTION audit_change(%s)',
+            audited[i][1],
+            args
+        );
+    END LOOP;
+END $$;
+
+-- Stock movements are only ever added. Sales and refunds are already
+-- recorded as transactions, so only the other movements are logged.
+DROP TRIGGER IF EXISTS audit_change ON stock_movements;
+CREATE TRIGGER audit_change AFTER INSERT ON stock_movements
+FOR EACH ROW WHEN (NEW.reference_type IS DISTINCT FROM 'transaction')
+EXECUTE FUNCTION audit_change('stock_movement', 'id');
@@ -0,0 +1,46 @@
+-- The tables needed to log in were readable by every tenant while no
+-- tenant was set. Now they are limited to the tenant the statement runs
+-- for, like all other tables, and the few lookups that happen before the
+-- tenant is known go through functions that only return the tenant:
+-- login finds it by username, PIN login by terminal key and API keys by
+-- key hash. A session keeps the tenant of its user, so refreshing a token
+-- knows it without reading the user first.
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS tenant_id INTEGER REFERENCES tenants(id) ON DELETE CASCADE;
+UPDATE sessions SET tenant_id = users.tenant_id FROM users WHERE users.id = sessions.user_id AND sessions.tenant_id IS NULL;
+ALTER TABLE sessions ALTER COLUMN tenant_id SET NOT NULL;
+
+-- The lookups run as the role that ran this migration. Only that role may
+-- read the rows of every tenant, and only to find a tenant, so the API must
+-- connect as a different role; it refuses to start otherwise.
+CREATE OR REPLACE FUNCTION tenant_of_username(name TEXT) RETURNS INTEGER
+LANGUAGE sql STABLE SECURITY DEFINER SET search_path = pg_catalog, public AS $$
+    SELECT tenant_id FROM users WHERE username = name
+$$;
+
+CREATE OR REPLACE FUNCTION tenant_of_terminal_key(hash TEXT) RETURNS INTEGER
+LANGUAGE sql STABLE SECURITY DEFINER SET search_path = pg_catalog, public AS $$
+    SELECT tenant_id FROM terminals WHERE key_hash = hash
+$$;
+
+CREATE OR REPLACE FUNCTION tenant_of_api_key(hash TEXT) RETURNS INTEGER
+LANGUAGE sql STABLE SECURITY DEFINER SET search_path = pg_catalog, public AS $$
+    SELECT tenant_id FROM api_keys WHERE key_hash = hash
+$$;
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY['users', 'roles', 'role_permissions', 'user_roles', 'terminals', 'api_keys'] LOOP
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', t);
+        EXECUTE format(
+            'CREATE POLICY tenant_isolation ON %I USING (tenant_id = current_tenant_id()) WITH CHECK (tenant_id = current_tenant_id())',
+            t
+        );
+    END LOOP;
+
+    FOREACH t IN ARRAY ARRAY['users', 'terminals', 'api_keys'] LOOP
+        EXECUTE format('DROP POLICY IF EXISTS tenant_lookup ON %I', t);
+        EXECUTE format('CREATE POLICY tenant_lookup ON %I FOR SELECT TO %I USING (true)', t, current_user);
+    END LOOP;
+END $$;
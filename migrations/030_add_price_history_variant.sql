@@ -0,0 +1,3 @@
+-- Price changes of a variant are recorded under its product with the
+-- variant set; product price changes leave it empty.
+ALTER TABLE price_history ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants(id) ON DELETE CASCADE;